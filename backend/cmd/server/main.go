@@ -8,8 +8,15 @@ import (
 	"news-intel-app/internal/database"
 	"news-intel-app/internal/scheduler"
 	"news-intel-app/internal/services/ai"
+	"news-intel-app/internal/services/ask"
 	"news-intel-app/internal/services/collector"
+	"news-intel-app/internal/services/jobs"
+	"news-intel-app/internal/services/prompts"
 	"news-intel-app/internal/services/pusher"
+	"news-intel-app/internal/services/search"
+	"news-intel-app/internal/services/stories"
+	"news-intel-app/internal/services/summaries"
+	"news-intel-app/internal/services/usage"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -32,9 +39,20 @@ func main() {
 		log.Printf("Failed to init default sources: %v", err)
 	}
 
+	// 初始化内置提示词
+	if err := prompts.Init(); err != nil {
+		log.Printf("Failed to init prompts: %v", err)
+	}
+	if err := usage.InitDefaultPricing(); err != nil {
+		log.Printf("Failed to init AI pricing: %v", err)
+	}
+	if err := summaries.InitDefaults(); err != nil {
+		log.Printf("Failed to init summary styles: %v", err)
+	}
+
 	// 初始化服务
 	col := collector.New()
-	aiSvc := ai.New(cfg.OpenAIKey, cfg.OpenAIBase, cfg.OpenAIModel)
+	aiSvc := ai.New(cfg.AIProvider, cfg.OpenAIKey, cfg.OpenAIBase, cfg.OpenAIModel)
 	
 	// 尝试从数据库加载 AI 配置（优先使用数据库配置）
 	if err := aiSvc.LoadConfig(); err != nil {
@@ -43,10 +61,14 @@ func main() {
 		log.Println("AI config loaded from database")
 	}
 	
-	push := pusher.New()
+	push := pusher.New(aiSvc)
+	storyClusterer := stories.New(aiSvc)
+	searcher := search.New(aiSvc)
+	asker := ask.New(aiSvc, searcher)
 
-	// 初始化定时任务
-	sched := scheduler.New(col, aiSvc, push)
+	// 初始化定时任务和后台任务队列
+	queue := jobs.New()
+	sched := scheduler.New(col, aiSvc, push, storyClusterer, searcher, queue)
 	sched.Start()
 	defer sched.Stop()
 
@@ -68,7 +90,7 @@ func main() {
 	app.Static("/", "./frontend/dist")
 
 	// API 路由
-	handler := api.NewHandler(col, aiSvc, push)
+	handler := api.NewHandler(col, aiSvc, push, searcher, asker, queue)
 	handler.RegisterRoutes(app)
 
 	// SPA fallback
@@ -76,21 +98,11 @@ func main() {
 		return c.SendFile("./frontend/dist/index.html")
 	})
 
-	// 启动时执行一次采集和翻译
-	go func() {
-		log.Println("Initial news collection...")
-		newNews, err := col.CollectAll()
-		if err != nil {
-			log.Printf("Initial collect error: %v", err)
-			return
-		}
-		if len(newNews) > 0 {
-			log.Printf("Translating %d new news...", len(newNews))
-			if err := aiSvc.ProcessAndMoveToReading(newNews); err != nil {
-				log.Printf("Initial translate error: %v", err)
-			}
-		}
-	}()
+	// 启动时执行一次采集和翻译（已有排队中的采集任务时不重复添加）
+	log.Println("Initial news collection...")
+	if _, err := queue.EnqueueOnce(jobs.TypeCollect, jobs.CollectPayload{}); err != nil {
+		log.Printf("Failed to enqueue initial collect job: %v", err)
+	}
 
 	// 启动服务器
 	log.Printf("Server starting on port %s", cfg.Port)
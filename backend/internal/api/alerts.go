@@ -0,0 +1,23 @@
+package api
+
+import (
+	"news-intel-app/internal/services/alerts"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetAlerts 管理员告警（默认只返回未确认的，all=true 返回全部）
+func (h *Handler) GetAlerts(c *fiber.Ctx) error {
+	list, err := alerts.List(c.QueryBool("all"))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(list)
+}
+
+func (h *Handler) AcknowledgeAlert(c *fiber.Ctx) error {
+	if err := alerts.Acknowledge(c.Params("id")); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"log"
+	"strings"
+
+	"news-intel-app/internal/services/ask"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Ask 基于新闻库回答问题，回答通过 SSE 流式返回并附带引用的新闻
+//
+// 事件顺序：sources（会话 ID 和检索到的资料）→ delta（回答片段，多次）→ done（完整回答和引用）；
+// 出错时发送 error 事件。请求体 stream 为 false 时直接返回 JSON。
+func (h *Handler) Ask(c *fiber.Ctx) error {
+	var req struct {
+		Question       string `json:"question"`
+		ConversationID string `json:"conversation_id"`
+		Stream         *bool  `json:"stream"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	req.Question = strings.TrimSpace(req.Question)
+	if req.Question == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "question is required"})
+	}
+
+	sess, err := h.ask.Prepare(req.ConversationID, req.Question)
+	if err == ask.ErrConversationNotFound {
+		return c.Status(404).JSON(fiber.Map{"error": "Conversation not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if req.Stream != nil && !*req.Stream {
+		msg, err := h.ask.Answer(context.Background(), sess, nil)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(fiber.Map{
+			"conversation_id": sess.ConversationID,
+			"answer":          msg.Content,
+			"citations":       msg.Citations,
+		})
+	}
+
+	setSSEHeaders(c)
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := writeSSE(w, "sources", fiber.Map{
+			"conversation_id": sess.ConversationID,
+			"sources":         sess.Citations(),
+		}); err != nil {
+			return
+		}
+
+		msg, err := h.ask.Answer(context.Background(), sess, func(delta string) error {
+			return writeSSE(w, "delta", fiber.Map{"text": delta})
+		})
+		if err != nil {
+			log.Printf("Ask error: %v", err)
+			writeSSE(w, "error", fiber.Map{"error": err.Error()})
+			return
+		}
+		writeSSE(w, "done", fiber.Map{
+			"conversation_id": sess.ConversationID,
+			"answer":          msg.Content,
+			"citations":       msg.Citations,
+		})
+	})
+	return nil
+}
+
+func (h *Handler) GetConversations(c *fiber.Ctx) error {
+	list, err := ask.List(c.QueryInt("limit", 50))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(list)
+}
+
+func (h *Handler) GetConversation(c *fiber.Ctx) error {
+	conv, err := ask.Get(c.Params("id"))
+	if err == ask.ErrConversationNotFound {
+		return c.Status(404).JSON(fiber.Map{"error": "Conversation not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(conv)
+}
+
+func (h *Handler) DeleteConversation(c *fiber.Ctx) error {
+	if err := ask.Delete(c.Params("id")); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/transcache"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetTranslationCache 翻译缓存统计及设置
+func (h *Handler) GetTranslationCache(c *fiber.Ctx) error {
+	stats, err := transcache.Stats()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(stats)
+}
+
+// SaveTranslationCacheSettings 保存缓存有效期和条数上限，保存后立即按新设置清理
+func (h *Handler) SaveTranslationCacheSettings(c *fiber.Ctx) error {
+	var req models.CacheSettings
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if req.TTLDays < 0 || req.MaxEntries < 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "ttl_days and max_entries must not be negative"})
+	}
+	if err := transcache.SaveSettings(req); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	removed, err := transcache.Prune()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true, "removed": removed})
+}
+
+// PurgeTranslationCache 清空翻译缓存（kind 可选：translate / summarize / batch_translate；expired=true 只清理过期条目）
+func (h *Handler) PurgeTranslationCache(c *fiber.Ctx) error {
+	var removed int64
+	var err error
+	if c.QueryBool("expired") {
+		removed, err = transcache.Prune()
+	} else {
+		removed, err = transcache.Purge(c.Query("kind"))
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true, "removed": removed})
+}
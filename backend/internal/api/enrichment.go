@@ -0,0 +1,47 @@
+package api
+
+import (
+	"strings"
+
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/enrichment"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// fieldFilterPrefix 按自定义字段筛选新闻的查询参数前缀，如 field.ticker=AAPL
+const fieldFilterPrefix = "field."
+
+// GetEnrichmentFields 自定义提取字段列表
+func (h *Handler) GetEnrichmentFields(c *fiber.Ctx) error {
+	return c.JSON(enrichment.GetFields())
+}
+
+// SaveEnrichmentFields 保存自定义提取字段（整体替换），之后翻译的新闻按新定义提取
+func (h *Handler) SaveEnrichmentFields(c *fiber.Ctx) error {
+	var fields []models.EnrichmentField
+	if err := c.BodyParser(&fields); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if fields == nil {
+		fields = []models.EnrichmentField{}
+	}
+	if err := enrichment.SaveFields(fields); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fields)
+}
+
+// fieldFilterClause 查询参数中 field.<name>=<value> 形式的自定义字段筛选条件
+func fieldFilterClause(c *fiber.Ctx) (string, []interface{}, error) {
+	filters := make(map[string]string)
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		if name := strings.TrimPrefix(string(key), fieldFilterPrefix); name != string(key) && name != "" {
+			filters[name] = strings.TrimSpace(string(value))
+		}
+	})
+	if len(filters) == 0 {
+		return "", nil, nil
+	}
+	return enrichment.FilterClause(enrichment.GetFields(), filters)
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/entities"
+	"news-intel-app/internal/services/translations"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetEntities 实体列表，按时间窗口内的提及次数排序，附带每日提及趋势
+func (h *Handler) GetEntities(c *fiber.Ctx) error {
+	entityType := c.Query("type")
+	keyword := c.Query("q")
+	days := c.QueryInt("days", 30)
+	limit := c.QueryInt("limit", 50)
+	if days < 1 {
+		days = 30
+	}
+	since := fmt.Sprintf("-%d days", days)
+
+	query := `SELECT e.id, e.name, e.type, e.created_at, COUNT(*) AS mentions
+		FROM entities e
+		JOIN news_entities ne ON ne.entity_id = e.id
+		JOIN news n ON n.id = ne.news_id
+		WHERE n.created_at > datetime('now', ?)`
+	args := []interface{}{since}
+
+	if entityType != "" {
+		query += " AND e.type = ?"
+		args = append(args, entityType)
+	}
+	if keyword != "" {
+		query += " AND (e.name LIKE ? OR e.id IN (SELECT entity_id FROM entity_aliases WHERE alias LIKE ?))"
+		args = append(args, "%"+keyword+"%", "%"+entities.NormalizeAlias(keyword, entityType)+"%")
+	}
+	query += " GROUP BY e.id ORDER BY mentions DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	defer rows.Close()
+
+	var list []models.Entity
+	index := make(map[string]int)
+	for rows.Next() {
+		var e models.Entity
+		var createdAt sql.NullTime
+		if err := rows.Scan(&e.ID, &e.Name, &e.Type, &createdAt, &e.Mentions); err != nil {
+			continue
+		}
+		e.CreatedAt = createdAt.Time
+		index[e.ID] = len(list)
+		list = append(list, e)
+	}
+
+	if len(list) == 0 {
+		return c.JSON(list)
+	}
+
+	// 每日提及趋势
+	placeholders := make([]string, len(list))
+	timelineArgs := []interface{}{since}
+	for i, e := range list {
+		placeholders[i] = "?"
+		timelineArgs = append(timelineArgs, e.ID)
+	}
+	trows, err := database.DB.Query(fmt.Sprintf(`
+		SELECT ne.entity_id, date(n.created_at) AS day, COUNT(*)
+		FROM news_entities ne JOIN news n ON n.id = ne.news_id
+		WHERE n.created_at > datetime('now', ?) AND ne.entity_id IN (%s)
+		GROUP BY ne.entity_id, day ORDER BY day
+	`, strings.Join(placeholders, ",")), timelineArgs...)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	defer trows.Close()
+
+	for trows.Next() {
+		var id string
+		var dc models.DailyCount
+		if err := trows.Scan(&id, &dc.Date, &dc.Count); err != nil {
+			continue
+		}
+		if i, ok := index[id]; ok {
+			list[i].Timeline = append(list[i].Timeline, dc)
+		}
+	}
+
+	return c.JSON(list)
+}
+
+// GetEntityNews 提到某个实体的所有新闻
+func (h *Handler) GetEntityNews(c *fiber.Ctx) error {
+	id := c.Params("id")
+	limit := c.QueryInt("limit", 50)
+	offset := c.QueryInt("offset", 0)
+
+	var e models.Entity
+	err := database.DB.QueryRow("SELECT id, name, type FROM entities WHERE id = ?", id).Scan(&e.ID, &e.Name, &e.Type)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Entity not found"})
+	}
+
+	arows, err := database.DB.Query("SELECT alias FROM entity_aliases WHERE entity_id = ? ORDER BY alias", id)
+	if err == nil {
+		defer arows.Close()
+		for arows.Next() {
+			var alias string
+			if arows.Scan(&alias) == nil {
+				e.Aliases = append(e.Aliases, alias)
+			}
+		}
+	}
+
+	rows, err := database.DB.Query(`SELECT `+newsListColumns+` FROM news
+		WHERE id IN (SELECT news_id FROM news_entities WHERE entity_id = ?)
+		ORDER BY created_at DESC LIMIT ? OFFSET ?`, id, limit, offset)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	defer rows.Close()
+
+	news := scanNewsList(rows)
+	translations.Attach(news)
+
+	var total int
+	database.DB.QueryRow("SELECT COUNT(*) FROM news_entities ne JOIN news n ON n.id = ne.news_id WHERE ne.entity_id = ?", id).Scan(&total)
+
+	return c.JSON(fiber.Map{
+		"entity": e,
+		"data":   news,
+		"total":  total,
+	})
+}
+
+// AddEntityAlias 为实体添加别名，之后提取到的该别名都会归到此实体
+func (h *Handler) AddEntityAlias(c *fiber.Ctx) error {
+	id := c.Params("id")
+	var req struct {
+		Alias string `json:"alias"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var entityType string
+	if err := database.DB.QueryRow("SELECT type FROM entities WHERE id = ?", id).Scan(&entityType); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Entity not found"})
+	}
+	if entities.NormalizeAlias(req.Alias, entityType) == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "别名不能为空"})
+	}
+
+	if err := entities.AddAlias(id, req.Alias, entityType); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
+
+// MergeEntity 将另一个实体合并到当前实体
+func (h *Handler) MergeEntity(c *fiber.Ctx) error {
+	id := c.Params("id")
+	var req struct {
+		SourceID string `json:"source_id"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if req.SourceID == "" || req.SourceID == id {
+		return c.Status(400).JSON(fiber.Map{"error": "source_id 无效"})
+	}
+
+	err := entities.Merge(id, req.SourceID)
+	if err == entities.ErrEntityNotFound {
+		return c.Status(404).JSON(fiber.Map{"error": "Entity not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/preference"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetNewsFeedback 记录读者反馈：up（赞）、down（踩）或 not_interested（不感兴趣，同时移出阅读窗口）
+func (h *Handler) SetNewsFeedback(c *fiber.Ctx) error {
+	var req struct {
+		Label string `json:"label"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	err := preference.SetFeedback(c.Params("id"), req.Label)
+	if err == preference.ErrInvalidFeedback {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err == preference.ErrNewsNotFound {
+		return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
+
+// DeleteNewsFeedback 撤销反馈
+func (h *Handler) DeleteNewsFeedback(c *fiber.Ctx) error {
+	if err := preference.DeleteFeedback(c.Params("id")); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
+
+// GetPreferences 偏好模型设置和状态（反馈数、训练时间、权重最高的特征）
+func (h *Handler) GetPreferences(c *fiber.Ctx) error {
+	status, err := preference.Status()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(status)
+}
+
+// SavePreferences 保存偏好模型设置，保存后按新的最少反馈数重新训练
+func (h *Handler) SavePreferences(c *fiber.Ctx) error {
+	var req models.PreferenceSettings
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if req.Threshold < 0 || req.Threshold > 1 {
+		return c.Status(400).JSON(fiber.Map{"error": "threshold must be between 0 and 1"})
+	}
+	if req.MinFeedback < 2 {
+		return c.Status(400).JSON(fiber.Map{"error": "min_feedback must be at least 2"})
+	}
+	if err := preference.SaveSettings(req); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return h.TrainPreferences(c)
+}
+
+// TrainPreferences 立即根据全部反馈重新训练偏好模型并为最近的新闻重新评分
+func (h *Handler) TrainPreferences(c *fiber.Ctx) error {
+	if err := preference.Train(); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return h.GetPreferences(c)
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"strings"
+
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/glossary"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetGlossary 术语列表（可按 lang 筛选，包含通用术语）
+func (h *Handler) GetGlossary(c *fiber.Ctx) error {
+	list, err := glossary.List(c.Query("lang"))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(list)
+}
+
+func (h *Handler) CreateGlossaryTerm(c *fiber.Ctx) error {
+	var t models.GlossaryTerm
+	if err := c.BodyParser(&t); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := normalizeGlossaryTerm(&t); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	err := glossary.Create(&t)
+	if err == glossary.ErrExists {
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(t)
+}
+
+func (h *Handler) UpdateGlossaryTerm(c *fiber.Ctx) error {
+	var t models.GlossaryTerm
+	if err := c.BodyParser(&t); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := normalizeGlossaryTerm(&t); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	t.ID = c.Params("id")
+
+	err := glossary.Update(t)
+	if err == glossary.ErrExists {
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err == glossary.ErrNotFound {
+		return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
+
+func (h *Handler) DeleteGlossaryTerm(c *fiber.Ctx) error {
+	if err := glossary.Delete(c.Params("id")); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
+
+// GetGlossaryViolations 最近的译文违反术语表记录
+func (h *Handler) GetGlossaryViolations(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 100)
+	if limit < 1 || limit > 1000 {
+		limit = 100
+	}
+	list, err := glossary.Violations(limit)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(list)
+}
+
+func (h *Handler) ClearGlossaryViolations(c *fiber.Ctx) error {
+	if err := glossary.ClearViolations(); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
+
+// normalizeGlossaryTerm 去除首尾空白并校验：不保留原文时必须指定译法
+func normalizeGlossaryTerm(t *models.GlossaryTerm) error {
+	t.Lang = strings.TrimSpace(t.Lang)
+	t.Term = strings.TrimSpace(t.Term)
+	t.Translation = strings.TrimSpace(t.Translation)
+	t.Note = strings.TrimSpace(t.Note)
+	if t.Term == "" {
+		return errors.New("term is required")
+	}
+	if t.KeepOriginal {
+		t.Translation = ""
+	} else if t.Translation == "" {
+		return errors.New("translation is required unless keep_original is set")
+	}
+	return nil
+}
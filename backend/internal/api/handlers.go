@@ -1,17 +1,29 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"news-intel-app/internal/database"
 	"news-intel-app/internal/models"
 	"news-intel-app/internal/services/ai"
+	"news-intel-app/internal/services/ask"
 	"news-intel-app/internal/services/collector"
+	"news-intel-app/internal/services/enrichment"
+	"news-intel-app/internal/services/jobs"
+	"news-intel-app/internal/services/preference"
 	"news-intel-app/internal/services/pusher"
+	"news-intel-app/internal/services/search"
+	"news-intel-app/internal/services/sentiment"
+	"news-intel-app/internal/services/summaries"
+	"news-intel-app/internal/services/taxonomy"
+	"news-intel-app/internal/services/translations"
+	"news-intel-app/internal/textutil"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -21,13 +33,19 @@ type Handler struct {
 	collector *collector.Collector
 	ai        *ai.AIService
 	pusher    *pusher.Pusher
+	search    *search.Searcher
+	ask       *ask.Asker
+	jobs      *jobs.Queue
 }
 
-func NewHandler(col *collector.Collector, aiSvc *ai.AIService, push *pusher.Pusher) *Handler {
+func NewHandler(col *collector.Collector, aiSvc *ai.AIService, push *pusher.Pusher, searcher *search.Searcher, asker *ask.Asker, queue *jobs.Queue) *Handler {
 	return &Handler{
 		collector: col,
 		ai:        aiSvc,
 		pusher:    push,
+		search:    searcher,
+		ask:       asker,
+		jobs:      queue,
 	}
 }
 
@@ -36,10 +54,37 @@ func (h *Handler) RegisterRoutes(app *fiber.App) {
 
 	// 新闻相关
 	api.Get("/news", h.GetNews)
+	api.Get("/news/search", h.SearchNews)
 	api.Get("/news/:id", h.GetNewsDetail)
+	api.Get("/news/:id/related", h.GetRelatedNews)
 	api.Delete("/news/:id", h.DeleteNews)
+	api.Post("/news/:id/translate-full", h.TranslateNewsContent)
 	api.Post("/news/collect", h.TriggerCollect)
 	api.Post("/news/process", h.TriggerProcess)
+	api.Get("/news/:id/summaries", h.GetNewsSummaries)
+	api.Post("/news/:id/summaries", h.GenerateNewsSummary)
+	api.Post("/news/:id/feedback", h.SetNewsFeedback)
+	api.Delete("/news/:id/feedback", h.DeleteNewsFeedback)
+
+	// 偏好模型（根据读者反馈训练）
+	api.Get("/preferences", h.GetPreferences)
+	api.Post("/preferences", h.SavePreferences)
+	api.Post("/preferences/train", h.TrainPreferences)
+
+	// 自定义提取字段
+	api.Get("/enrichment-fields", h.GetEnrichmentFields)
+	api.Post("/enrichment-fields", h.SaveEnrichmentFields)
+
+	// 摘要样式
+	api.Get("/summary-styles", h.GetSummaryStyles)
+	api.Post("/summary-styles", h.CreateSummaryStyle)
+	api.Put("/summary-styles/:id", h.UpdateSummaryStyle)
+	api.Delete("/summary-styles/:id", h.DeleteSummaryStyle)
+
+	// 后台任务
+	api.Get("/jobs", h.GetJobs)
+	api.Post("/jobs/:id/retry", h.RetryJob)
+	api.Post("/jobs/:id/cancel", h.CancelJob)
 
 	// 阅读窗口
 	api.Get("/reading", h.GetReadingNews)
@@ -80,11 +125,73 @@ func (h *Handler) RegisterRoutes(app *fiber.App) {
 	api.Post("/templates/preview", h.PreviewTemplate)
 	api.Post("/templates/ai-generate", h.AIGenerateTemplate)
 
+	// 标签
+	api.Get("/tags", h.GetTags)
+	api.Post("/tags", h.CreateTag)
+	api.Put("/tags/:id", h.UpdateTag)
+	api.Delete("/tags/:id", h.DeleteTag)
+	api.Post("/tags/:id/approve", h.ApproveTag)
+
+	// 实体
+	api.Get("/entities", h.GetEntities)
+	api.Get("/entities/:id/news", h.GetEntityNews)
+	api.Post("/entities/:id/aliases", h.AddEntityAlias)
+	api.Post("/entities/:id/merge", h.MergeEntity)
+
+	// 故事（同一事件的多源报道）
+	api.Get("/stories", h.GetStories)
+	api.Get("/stories/:id", h.GetStory)
+	api.Post("/stories/rebuild", h.RebuildStories)
+
+	// 热点趋势和关注词
+	api.Get("/trends", h.GetTrends)
+	api.Post("/trends/refresh", h.RefreshTrends)
+	api.Get("/trend-watches", h.GetTrendWatches)
+	api.Post("/trend-watches", h.CreateTrendWatch)
+	api.Put("/trend-watches/:id", h.UpdateTrendWatch)
+	api.Delete("/trend-watches/:id", h.DeleteTrendWatch)
+
+	// 问答（基于新闻库）
+	api.Post("/ask", h.Ask)
+	api.Get("/conversations", h.GetConversations)
+	api.Get("/conversations/:id", h.GetConversation)
+	api.Delete("/conversations/:id", h.DeleteConversation)
+
+	// 提示词
+	api.Get("/prompts", h.GetPrompts)
+	api.Post("/prompts", h.CreatePrompt)
+	api.Get("/prompts/:name", h.GetPrompt)
+	api.Put("/prompts/:name", h.UpdatePrompt)
+	api.Delete("/prompts/:name", h.DeletePrompt)
+	api.Post("/prompts/:name/rollback", h.RollbackPrompt)
+	api.Post("/prompts/:name/test", h.TestPrompt)
+
 	// AI配置
 	api.Get("/ai/config", h.GetAIConfig)
 	api.Post("/ai/config", h.SaveAIConfig)
 	api.Post("/ai/translate", h.TranslateText)
 	api.Post("/ai/summarize", h.SummarizeText)
+	api.Get("/ai/usage", h.GetAIUsage)
+	api.Get("/ai/pricing", h.GetAIPricing)
+	api.Post("/ai/pricing", h.SaveAIPricing)
+	api.Delete("/ai/pricing/:model", h.DeleteAIPricing)
+	api.Get("/ai/budget", h.GetAIBudget)
+	api.Post("/ai/budget", h.SaveAIBudget)
+	api.Get("/ai/cache", h.GetTranslationCache)
+	api.Post("/ai/cache", h.SaveTranslationCacheSettings)
+	api.Delete("/ai/cache", h.PurgeTranslationCache)
+
+	// 术语表
+	api.Get("/glossary", h.GetGlossary)
+	api.Post("/glossary", h.CreateGlossaryTerm)
+	api.Get("/glossary/violations", h.GetGlossaryViolations)
+	api.Delete("/glossary/violations", h.ClearGlossaryViolations)
+	api.Put("/glossary/:id", h.UpdateGlossaryTerm)
+	api.Delete("/glossary/:id", h.DeleteGlossaryTerm)
+
+	// 告警
+	api.Get("/alerts", h.GetAlerts)
+	api.Post("/alerts/:id/ack", h.AcknowledgeAlert)
 
 	// 统计
 	api.Get("/stats", h.GetStats)
@@ -95,24 +202,48 @@ func (h *Handler) RegisterRoutes(app *fiber.App) {
 func (h *Handler) GetNews(c *fiber.Ctx) error {
 	category := c.Query("category")
 	source := c.Query("source")
+	tagFilter := textutil.SplitList(c.Query("tags"))
 	limit := c.QueryInt("limit", 50)
 	offset := c.QueryInt("offset", 0)
 
-	query := `SELECT id, title, content, summary, url, source, category, image_url, author, 
-		published_at, created_at, translated, trans_title, trans_content, trans_summary, is_filtered, tags 
-		FROM news WHERE is_filtered = 0`
+	// filtered=yes 查看被偏好模型过滤的新闻
+	where := " WHERE is_filtered = 0"
+	if c.Query("filtered") == "yes" {
+		where = " WHERE is_filtered = 1"
+	}
 	args := []interface{}{}
 
 	if category != "" {
-		query += " AND category = ?"
+		where += " AND category = ?"
 		args = append(args, category)
 	}
 	if source != "" {
-		query += " AND source = ?"
+		where += " AND source = ?"
 		args = append(args, source)
 	}
+	if clause, tagArgs := taxonomy.FilterClause(tagFilter); clause != "" {
+		where += " AND " + clause
+		args = append(args, tagArgs...)
+	}
+	clause, sentimentArgs, err := sentiment.FilterClause(textutil.SplitList(c.Query("sentiment")))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if clause != "" {
+		where += " AND " + clause
+		args = append(args, sentimentArgs...)
+	}
+	clause, fieldArgs, err := fieldFilterClause(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if clause != "" {
+		where += " AND " + clause
+		args = append(args, fieldArgs...)
+	}
 
-	query += " ORDER BY created_at DESC LIMIT ? OFFSET ?"
+	query := "SELECT " + newsListColumns + " FROM news" + where + " ORDER BY " + newsOrder(c.Query("sort"), "created_at") + " LIMIT ? OFFSET ?"
+	countArgs := append([]interface{}{}, args...)
 	args = append(args, limit, offset)
 
 	rows, err := database.DB.Query(query, args...)
@@ -121,17 +252,55 @@ func (h *Handler) GetNews(c *fiber.Ctx) error {
 	}
 	defer rows.Close()
 
+	news := scanNewsList(rows)
+	translations.Attach(news)
+
+	// 获取总数
+	var total int
+	database.DB.QueryRow("SELECT COUNT(*) FROM news"+where, countArgs...).Scan(&total)
+
+	return c.JSON(fiber.Map{
+		"data":  news,
+		"total": total,
+	})
+}
+
+// newsListColumns 新闻列表查询的字段，与 scanNewsList 的扫描顺序一致
+var newsListColumns = `id, title, content, summary, url, source, category, image_url, author, 
+	published_at, created_at, translated, trans_title, trans_content, trans_summary, is_filtered, ` + taxonomy.TagsColumnSQL + `, 
+	pref_score, ` + preference.FeedbackColumnSQL + `, COALESCE(sentiment, ''), sentiment_score, COALESCE(fields, '')`
+
+// newsOrder 新闻列表排序：sort=preference 按偏好评分从高到低（未评分的排在最后），否则按 column 倒序
+func newsOrder(sort, column string) string {
+	if sort == "preference" {
+		return "pref_score IS NULL, pref_score DESC, " + column + " DESC"
+	}
+	return column + " DESC"
+}
+
+// scanNewsList 扫描新闻列表查询结果
+func scanNewsList(rows *sql.Rows) []models.News {
 	var news []models.News
 	for rows.Next() {
 		var n models.News
 		var publishedAt, createdAt sql.NullTime
 		var tags, transTitle, transContent, transSummary, content, summary, imageURL, author sql.NullString
+		var prefScore, sentimentScore sql.NullFloat64
+		var fields string
 		err := rows.Scan(&n.ID, &n.Title, &content, &summary, &n.URL, &n.Source, &n.Category,
-			&imageURL, &author, &publishedAt, &createdAt, &n.Translated, &transTitle, &transContent, &transSummary, &n.IsFiltered, &tags)
+			&imageURL, &author, &publishedAt, &createdAt, &n.Translated, &transTitle, &transContent, &transSummary, &n.IsFiltered, &tags,
+			&prefScore, &n.Feedback, &n.Sentiment, &sentimentScore, &fields)
 		if err != nil {
 			log.Printf("Scan error: %v", err)
 			continue
 		}
+		if prefScore.Valid {
+			n.Preference = &prefScore.Float64
+		}
+		if sentimentScore.Valid {
+			n.SentimentScore = &sentimentScore.Float64
+		}
+		n.Fields = enrichment.Decode(fields)
 		if publishedAt.Valid {
 			n.PublishedAt = publishedAt.Time
 		}
@@ -164,32 +333,25 @@ func (h *Handler) GetNews(c *fiber.Ctx) error {
 		}
 		news = append(news, n)
 	}
-
-	// 获取总数
-	var total int
-	countQuery := "SELECT COUNT(*) FROM news WHERE is_filtered = 0"
-	if category != "" {
-		countQuery += " AND category = '" + category + "'"
-	}
-	database.DB.QueryRow(countQuery).Scan(&total)
-
-	return c.JSON(fiber.Map{
-		"data":  news,
-		"total": total,
-	})
+	return news
 }
 
 func (h *Handler) GetNewsDetail(c *fiber.Ctx) error {
 	id := c.Params("id")
 	var n models.News
 	var publishedAt, createdAt sql.NullTime
-	
+	var tags sql.NullString
+	var sentimentScore sql.NullFloat64
+	var fields string
+
 	err := database.DB.QueryRow(`
 		SELECT id, title, content, summary, url, source, category, image_url, author,
-		published_at, created_at, translated, trans_title, trans_content, trans_summary, is_filtered, tags
+		published_at, created_at, translated, trans_title, trans_content, trans_summary, is_filtered, `+taxonomy.TagsColumnSQL+`,
+		COALESCE(sentiment, ''), sentiment_score, COALESCE(fields, '')
 		FROM news WHERE id = ?
 	`, id).Scan(&n.ID, &n.Title, &n.Content, &n.Summary, &n.URL, &n.Source, &n.Category,
-		&n.ImageURL, &n.Author, &publishedAt, &createdAt, &n.Translated, &n.TransTitle, &n.TransContent, &n.TransSummary, &n.IsFiltered, &n.Tags)
+		&n.ImageURL, &n.Author, &publishedAt, &createdAt, &n.Translated, &n.TransTitle, &n.TransContent, &n.TransSummary, &n.IsFiltered, &tags,
+		&n.Sentiment, &sentimentScore, &fields)
 
 	if err != nil {
 		return c.Status(404).JSON(fiber.Map{"error": "News not found"})
@@ -201,8 +363,17 @@ func (h *Handler) GetNewsDetail(c *fiber.Ctx) error {
 	if createdAt.Valid {
 		n.CreatedAt = createdAt.Time
 	}
+	if tags.Valid {
+		n.Tags = tags.String
+	}
+	if sentimentScore.Valid {
+		n.SentimentScore = &sentimentScore.Float64
+	}
+	n.Fields = enrichment.Decode(fields)
+	list := []models.News{n}
+	translations.Attach(list)
 
-	return c.JSON(n)
+	return c.JSON(list[0])
 }
 
 func (h *Handler) DeleteNews(c *fiber.Ctx) error {
@@ -211,34 +382,30 @@ func (h *Handler) DeleteNews(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
+	database.DB.Exec("DELETE FROM news_embeddings WHERE news_id = ?", id)
+	database.DB.Exec("DELETE FROM news_tags WHERE news_id = ?", id)
+	database.DB.Exec("DELETE FROM news_entities WHERE news_id = ?", id)
+	preference.DeleteFeedback(id)
+	translations.Delete(id)
+	summaries.DeleteForNews(id)
 	return c.JSON(fiber.Map{"success": true})
 }
 
+// TriggerCollect 排队采集任务，采集后自动生成翻译任务
 func (h *Handler) TriggerCollect(c *fiber.Ctx) error {
-	go func() {
-		newNews, err := h.collector.CollectAll()
-		if err != nil {
-			log.Printf("Collect error: %v", err)
-			return
-		}
-		// 采集后立即翻译
-		if len(newNews) > 0 {
-			log.Printf("Translating %d new news...", len(newNews))
-			if err := h.ai.ProcessAndMoveToReading(newNews); err != nil {
-				log.Printf("Translate error: %v", err)
-			}
-		}
-	}()
-	return c.JSON(fiber.Map{"message": "Collection and translation started"})
+	id, err := h.jobs.EnqueueOnce(jobs.TypeCollect, jobs.CollectPayload{})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"message": "Collection and translation started", "job_id": id})
 }
 
 func (h *Handler) TriggerProcess(c *fiber.Ctx) error {
-	go func() {
-		if err := h.ai.ProcessUnprocessedNews(10); err != nil {
-			log.Printf("Process error: %v", err)
-		}
-	}()
-	return c.JSON(fiber.Map{"message": "Processing started"})
+	id, err := h.jobs.Enqueue(jobs.TypeProcess, jobs.ProcessPayload{Limit: 10})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"message": "Processing started", "job_id": id})
 }
 
 // ========== 阅读窗口相关 ==========
@@ -246,12 +413,14 @@ func (h *Handler) TriggerProcess(c *fiber.Ctx) error {
 func (h *Handler) GetReadingNews(c *fiber.Ctx) error {
 	category := c.Query("category")
 	pushed := c.Query("pushed") // "all", "yes", "no"
+	tagFilter := textutil.SplitList(c.Query("tags"))
 	limit := c.QueryInt("limit", 50)
 	offset := c.QueryInt("offset", 0)
 
 	query := `SELECT id, title, content, summary, url, source, category, image_url, author, 
 		published_at, created_at, translated, trans_title, trans_content, trans_summary, 
-		is_filtered, tags, in_reading, reading_at, pushed, pushed_at 
+		is_filtered, ` + taxonomy.TagsColumnSQL + `, in_reading, reading_at, pushed, pushed_at, 
+		pref_score, ` + preference.FeedbackColumnSQL + `, COALESCE(sentiment, ''), sentiment_score, COALESCE(fields, '') 
 		FROM news WHERE in_reading = 1`
 	args := []interface{}{}
 
@@ -264,8 +433,28 @@ func (h *Handler) GetReadingNews(c *fiber.Ctx) error {
 	} else if pushed == "no" {
 		query += " AND pushed = 0"
 	}
+	if clause, tagArgs := taxonomy.FilterClause(tagFilter); clause != "" {
+		query += " AND " + clause
+		args = append(args, tagArgs...)
+	}
+	clause, sentimentArgs, err := sentiment.FilterClause(textutil.SplitList(c.Query("sentiment")))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if clause != "" {
+		query += " AND " + clause
+		args = append(args, sentimentArgs...)
+	}
+	clause, fieldArgs, err := fieldFilterClause(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if clause != "" {
+		query += " AND " + clause
+		args = append(args, fieldArgs...)
+	}
 
-	query += " ORDER BY reading_at DESC LIMIT ? OFFSET ?"
+	query += " ORDER BY " + newsOrder(c.Query("sort"), "reading_at") + " LIMIT ? OFFSET ?"
 	args = append(args, limit, offset)
 
 	rows, err := database.DB.Query(query, args...)
@@ -279,13 +468,22 @@ func (h *Handler) GetReadingNews(c *fiber.Ctx) error {
 		var n models.News
 		var publishedAt, createdAt, readingAt, pushedAt sql.NullTime
 		var tags, transTitle, transContent, transSummary, content, summary, imageURL, author sql.NullString
+		var prefScore, sentimentScore sql.NullFloat64
+		var fields string
 		err := rows.Scan(&n.ID, &n.Title, &content, &summary, &n.URL, &n.Source, &n.Category,
 			&imageURL, &author, &publishedAt, &createdAt, &n.Translated, &transTitle, &transContent, &transSummary,
-			&n.IsFiltered, &tags, &n.InReading, &readingAt, &n.Pushed, &pushedAt)
+			&n.IsFiltered, &tags, &n.InReading, &readingAt, &n.Pushed, &pushedAt, &prefScore, &n.Feedback, &n.Sentiment, &sentimentScore, &fields)
 		if err != nil {
 			log.Printf("Scan reading news error: %v", err)
 			continue
 		}
+		if prefScore.Valid {
+			n.Preference = &prefScore.Float64
+		}
+		if sentimentScore.Valid {
+			n.SentimentScore = &sentimentScore.Float64
+		}
+		n.Fields = enrichment.Decode(fields)
 		if publishedAt.Valid {
 			n.PublishedAt = publishedAt.Time
 		}
@@ -324,6 +522,7 @@ func (h *Handler) GetReadingNews(c *fiber.Ctx) error {
 		}
 		news = append(news, n)
 	}
+	translations.Attach(news)
 
 	// 获取总数
 	var total, unpushedCount int
@@ -366,7 +565,7 @@ func (h *Handler) ClearPushedNews(c *fiber.Ctx) error {
 // ========== 新闻源相关 ==========
 
 func (h *Handler) GetSources(c *fiber.Ctx) error {
-	rows, err := database.DB.Query("SELECT id, name, type, url, category, enabled, interval_mins, created_at FROM news_sources ORDER BY created_at DESC")
+	rows, err := database.DB.Query("SELECT id, name, type, url, category, enabled, interval_mins, full_translate, created_at FROM news_sources ORDER BY created_at DESC")
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -375,7 +574,7 @@ func (h *Handler) GetSources(c *fiber.Ctx) error {
 	var sources []models.NewsSource
 	for rows.Next() {
 		var s models.NewsSource
-		rows.Scan(&s.ID, &s.Name, &s.Type, &s.URL, &s.Category, &s.Enabled, &s.Interval, &s.CreatedAt)
+		rows.Scan(&s.ID, &s.Name, &s.Type, &s.URL, &s.Category, &s.Enabled, &s.Interval, &s.FullTranslate, &s.CreatedAt)
 		sources = append(sources, s)
 	}
 
@@ -392,9 +591,9 @@ func (h *Handler) CreateSource(c *fiber.Ctx) error {
 	s.CreatedAt = time.Now()
 
 	_, err := database.DB.Exec(`
-		INSERT INTO news_sources (id, name, type, url, category, enabled, interval_mins, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`, s.ID, s.Name, s.Type, s.URL, s.Category, s.Enabled, s.Interval, s.CreatedAt)
+		INSERT INTO news_sources (id, name, type, url, category, enabled, interval_mins, full_translate, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, s.ID, s.Name, s.Type, s.URL, s.Category, s.Enabled, s.Interval, s.FullTranslate, s.CreatedAt)
 
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
@@ -411,9 +610,9 @@ func (h *Handler) UpdateSource(c *fiber.Ctx) error {
 	}
 
 	_, err := database.DB.Exec(`
-		UPDATE news_sources SET name = ?, type = ?, url = ?, category = ?, enabled = ?, interval_mins = ?, updated_at = ?
+		UPDATE news_sources SET name = ?, type = ?, url = ?, category = ?, enabled = ?, interval_mins = ?, full_translate = ?, updated_at = ?
 		WHERE id = ?
-	`, s.Name, s.Type, s.URL, s.Category, s.Enabled, s.Interval, time.Now(), id)
+	`, s.Name, s.Type, s.URL, s.Category, s.Enabled, s.Interval, s.FullTranslate, time.Now(), id)
 
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
@@ -501,7 +700,7 @@ func (h *Handler) DeleteChannel(c *fiber.Ctx) error {
 
 func (h *Handler) TestChannel(c *fiber.Ctx) error {
 	id := c.Params("id")
-	
+
 	var ch models.PushChannel
 	err := database.DB.QueryRow("SELECT id, name, type, config FROM push_channels WHERE id = ?", id).
 		Scan(&ch.ID, &ch.Name, &ch.Type, &ch.Config)
@@ -530,7 +729,7 @@ func (h *Handler) TestChannel(c *fiber.Ctx) error {
 // ========== 推送任务相关 ==========
 
 func (h *Handler) GetTasks(c *fiber.Ctx) error {
-	rows, err := database.DB.Query("SELECT id, name, cron_expr, channel_id, template_id, categories, enabled, last_run_at, created_at FROM push_tasks ORDER BY created_at DESC")
+	rows, err := database.DB.Query("SELECT id, name, cron_expr, channel_id, template_id, categories, tags, briefing, COALESCE(summary_style, ''), enabled, last_run_at, created_at FROM push_tasks ORDER BY created_at DESC")
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -540,10 +739,14 @@ func (h *Handler) GetTasks(c *fiber.Ctx) error {
 	for rows.Next() {
 		var t models.PushTask
 		var lastRunAt sql.NullTime
-		rows.Scan(&t.ID, &t.Name, &t.CronExpr, &t.ChannelID, &t.TemplateID, &t.Categories, &t.Enabled, &lastRunAt, &t.CreatedAt)
+		var tags sql.NullString
+		rows.Scan(&t.ID, &t.Name, &t.CronExpr, &t.ChannelID, &t.TemplateID, &t.Categories, &tags, &t.Briefing, &t.SummaryStyle, &t.Enabled, &lastRunAt, &t.CreatedAt)
 		if lastRunAt.Valid {
 			t.LastRunAt = lastRunAt.Time
 		}
+		if tags.Valid {
+			t.Tags = tags.String
+		}
 		tasks = append(tasks, t)
 	}
 
@@ -556,13 +759,17 @@ func (h *Handler) CreateTask(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	if err := checkSummaryStyle(t.SummaryStyle); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
 	t.ID = uuid.New().String()
 	t.CreatedAt = time.Now()
 
 	_, err := database.DB.Exec(`
-		INSERT INTO push_tasks (id, name, cron_expr, channel_id, template_id, categories, enabled, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`, t.ID, t.Name, t.CronExpr, t.ChannelID, t.TemplateID, t.Categories, t.Enabled, t.CreatedAt)
+		INSERT INTO push_tasks (id, name, cron_expr, channel_id, template_id, categories, tags, briefing, summary_style, enabled, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, t.ID, t.Name, t.CronExpr, t.ChannelID, t.TemplateID, t.Categories, t.Tags, t.Briefing, t.SummaryStyle, t.Enabled, t.CreatedAt)
 
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
@@ -578,10 +785,14 @@ func (h *Handler) UpdateTask(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	if err := checkSummaryStyle(t.SummaryStyle); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
 	_, err := database.DB.Exec(`
-		UPDATE push_tasks SET name = ?, cron_expr = ?, channel_id = ?, template_id = ?, categories = ?, enabled = ?, updated_at = ?
+		UPDATE push_tasks SET name = ?, cron_expr = ?, channel_id = ?, template_id = ?, categories = ?, tags = ?, briefing = ?, summary_style = ?, enabled = ?, updated_at = ?
 		WHERE id = ?
-	`, t.Name, t.CronExpr, t.ChannelID, t.TemplateID, t.Categories, t.Enabled, time.Now(), id)
+	`, t.Name, t.CronExpr, t.ChannelID, t.TemplateID, t.Categories, t.Tags, t.Briefing, t.SummaryStyle, t.Enabled, time.Now(), id)
 
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
@@ -603,11 +814,13 @@ func (h *Handler) RunTask(c *fiber.Ctx) error {
 	id := c.Params("id")
 
 	var t models.PushTask
-	err := database.DB.QueryRow("SELECT id, name, cron_expr, channel_id, template_id, categories, enabled FROM push_tasks WHERE id = ?", id).
-		Scan(&t.ID, &t.Name, &t.CronExpr, &t.ChannelID, &t.TemplateID, &t.Categories, &t.Enabled)
+	var tags sql.NullString
+	err := database.DB.QueryRow("SELECT id, name, cron_expr, channel_id, template_id, categories, tags, briefing, COALESCE(summary_style, ''), enabled FROM push_tasks WHERE id = ?", id).
+		Scan(&t.ID, &t.Name, &t.CronExpr, &t.ChannelID, &t.TemplateID, &t.Categories, &tags, &t.Briefing, &t.SummaryStyle, &t.Enabled)
 	if err != nil {
 		return c.Status(404).JSON(fiber.Map{"error": "Task not found"})
 	}
+	t.Tags = tags.String
 
 	go func() {
 		if err := h.pusher.ExecutePushTask(&t); err != nil {
@@ -689,46 +902,15 @@ func (h *Handler) DeleteTemplate(c *fiber.Ctx) error {
 
 func (h *Handler) PreviewTemplate(c *fiber.Ctx) error {
 	var req struct {
-		Content string `json:"content"`
+		Content  string `json:"content"`
+		Briefing bool   `json:"briefing"` // 是否生成 AI 综述用于预览
 	}
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	// 从阅读窗口获取真实新闻数据用于预览
-	rows, err := database.DB.Query(`
-		SELECT id, title, content, summary, url, source, category, image_url, trans_title, trans_summary 
-		FROM news WHERE in_reading = 1 AND translated = 1 
-		ORDER BY reading_at DESC LIMIT 5
-	`)
-	
-	var news []models.News
-	if err == nil {
-		defer rows.Close()
-		for rows.Next() {
-			var n models.News
-			var transTitle, transSummary, content, summary, imageURL sql.NullString
-			if err := rows.Scan(&n.ID, &n.Title, &content, &summary, &n.URL, &n.Source, &n.Category, &imageURL, &transTitle, &transSummary); err != nil {
-				continue
-			}
-			if transTitle.Valid {
-				n.TransTitle = transTitle.String
-			}
-			if transSummary.Valid {
-				n.TransSummary = transSummary.String
-			}
-			if content.Valid {
-				n.Content = content.String
-			}
-			if summary.Valid {
-				n.Summary = summary.String
-			}
-			if imageURL.Valid {
-				n.ImageURL = imageURL.String
-			}
-			news = append(news, n)
-		}
-	}
+	news, _ := pusher.QueryNews("in_reading = 1 AND translated = 1 ORDER BY reading_at DESC LIMIT 5")
 
 	// 如果没有真实数据，使用示例数据
 	if len(news) == 0 {
@@ -738,7 +920,12 @@ func (h *Handler) PreviewTemplate(c *fiber.Ctx) error {
 		}
 	}
 
-	html, err := h.pusher.RenderTemplate(req.Content, news)
+	var briefing *models.Briefing
+	if req.Briefing {
+		briefing = h.pusher.GenerateBriefing(news)
+	}
+
+	html, err := h.pusher.RenderTemplate(req.Content, news, briefing)
 	if err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -746,11 +933,12 @@ func (h *Handler) PreviewTemplate(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"html": html, "news_count": len(news)})
 }
 
-// AIGenerateTemplate AI生成邮件模板
+// AIGenerateTemplate AI生成邮件模板；stream 为 true 时通过 SSE 流式返回
 func (h *Handler) AIGenerateTemplate(c *fiber.Ctx) error {
 	var req struct {
 		Description     string `json:"description"`
 		CurrentTemplate string `json:"current_template"`
+		Stream          bool   `json:"stream"`
 	}
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
@@ -760,6 +948,12 @@ func (h *Handler) AIGenerateTemplate(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "请输入模板设计需求"})
 	}
 
+	if req.Stream {
+		return streamCompletion(c, "template", func(ctx context.Context, onDelta func(string) error) (string, error) {
+			return h.ai.GenerateEmailTemplateStream(ctx, req.Description, req.CurrentTemplate, onDelta)
+		})
+	}
+
 	template, err := h.ai.GenerateEmailTemplate(req.Description, req.CurrentTemplate)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "AI 生成失败: " + err.Error()})
@@ -768,25 +962,147 @@ func (h *Handler) AIGenerateTemplate(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"template": template})
 }
 
+// ========== 标签相关 ==========
+
+func (h *Handler) GetTags(c *fiber.Ctx) error {
+	query := `SELECT t.id, t.name, t.synonyms, t.status, t.created_at,
+		(SELECT COUNT(*) FROM news_tags nt WHERE nt.tag_id = t.id) AS news_count
+		FROM tags t`
+	args := []interface{}{}
+	if status := c.Query("status"); status != "" {
+		query += " WHERE t.status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY t.name"
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	defer rows.Close()
+
+	var tags []models.Tag
+	for rows.Next() {
+		var t models.Tag
+		var synonyms sql.NullString
+		rows.Scan(&t.ID, &t.Name, &synonyms, &t.Status, &t.CreatedAt, &t.NewsCount)
+		t.Synonyms = synonyms.String
+		tags = append(tags, t)
+	}
+
+	return c.JSON(tags)
+}
+
+func (h *Handler) CreateTag(c *fiber.Ctx) error {
+	var t models.Tag
+	if err := c.BodyParser(&t); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "标签名不能为空"})
+	}
+
+	t.ID = uuid.New().String()
+	t.Synonyms = strings.Join(textutil.SplitList(t.Synonyms), ",")
+	t.Status = taxonomy.StatusApproved
+	t.CreatedAt = time.Now()
+
+	_, err := database.DB.Exec(`
+		INSERT INTO tags (id, name, synonyms, status, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, t.ID, t.Name, t.Synonyms, t.Status, t.CreatedAt)
+
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(t)
+}
+
+func (h *Handler) UpdateTag(c *fiber.Ctx) error {
+	id := c.Params("id")
+	var t models.Tag
+	if err := c.BodyParser(&t); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "标签名不能为空"})
+	}
+	if t.Status != taxonomy.StatusPending {
+		t.Status = taxonomy.StatusApproved
+	}
+
+	_, err := database.DB.Exec(`
+		UPDATE tags SET name = ?, synonyms = ?, status = ?, updated_at = ?
+		WHERE id = ?
+	`, t.Name, strings.Join(textutil.SplitList(t.Synonyms), ","), t.Status, time.Now(), id)
+
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"success": true})
+}
+
+func (h *Handler) DeleteTag(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := database.DB.Exec("DELETE FROM news_tags WHERE tag_id = ?", id); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	if _, err := database.DB.Exec("DELETE FROM tags WHERE id = ?", id); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
+
+// ApproveTag 审核通过 AI 提议的标签
+func (h *Handler) ApproveTag(c *fiber.Ctx) error {
+	id := c.Params("id")
+	_, err := database.DB.Exec("UPDATE tags SET status = ?, updated_at = ? WHERE id = ?", taxonomy.StatusApproved, time.Now(), id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
+
 // ========== AI配置相关 ==========
 
 func (h *Handler) GetAIConfig(c *fiber.Ctx) error {
 	var cfg models.AIConfig
+	var targetLangs, fullTransCategories string
 	err := database.DB.QueryRow(`
-		SELECT id, provider, api_key, base_url, model, enable_trans, enable_summary, enable_filter, target_lang 
+		SELECT id, provider, api_key, base_url, model, enable_trans, enable_summary, enable_filter, target_lang, COALESCE(target_langs, ''), COALESCE(full_trans_categories, ''), enable_tags, allow_new_tags, enable_entities, enable_sentiment, COALESCE(embedding_model, ''), rate_limit_rpm, rate_limit_tpm, timeout_seconds, max_retries, batch_concurrency 
 		FROM ai_configs LIMIT 1
-	`).Scan(&cfg.ID, &cfg.Provider, &cfg.APIKey, &cfg.BaseURL, &cfg.Model, &cfg.EnableTrans, &cfg.EnableSummary, &cfg.EnableFilter, &cfg.TargetLang)
+	`).Scan(&cfg.ID, &cfg.Provider, &cfg.APIKey, &cfg.BaseURL, &cfg.Model, &cfg.EnableTrans, &cfg.EnableSummary, &cfg.EnableFilter, &cfg.TargetLang, &targetLangs, &fullTransCategories, &cfg.EnableTags, &cfg.AllowNewTags, &cfg.EnableEntities, &cfg.EnableSentiment, &cfg.EmbeddingModel, &cfg.RateLimitRPM, &cfg.RateLimitTPM, &cfg.TimeoutSeconds, &cfg.MaxRetries, &cfg.BatchConcurrency)
 
 	if err != nil {
 		// 返回默认配置
 		return c.JSON(models.AIConfig{
-			Provider:     "openai",
-			Model:        "gpt-4o-mini",
-			EnableTrans:  true,
-			EnableSummary: true,
-			TargetLang:   "zh-CN",
+			Provider:            "openai",
+			Model:               "gpt-4o-mini",
+			EnableTrans:         true,
+			EnableSummary:       true,
+			TargetLang:          "zh-CN",
+			EnableTags:          true,
+			EnableEntities:      true,
+			EmbeddingModel:      ai.DefaultEmbeddingModel,
+			TimeoutSeconds:      ai.DefaultTimeoutSeconds,
+			MaxRetries:          ai.DefaultMaxRetries,
+			BatchConcurrency:    ai.DefaultBatchConcurrency,
+			TargetLangs:         []string{"zh-CN"},
+			FullTransCategories: []string{},
 		})
 	}
+	cfg.TargetLangs = textutil.SplitList(targetLangs)
+	if len(cfg.TargetLangs) == 0 {
+		cfg.TargetLangs = []string{cfg.TargetLang}
+	}
+	cfg.FullTransCategories = textutil.SplitList(fullTransCategories)
+	if cfg.FullTransCategories == nil {
+		cfg.FullTransCategories = []string{}
+	}
 
 	return c.JSON(cfg)
 }
@@ -800,11 +1116,30 @@ func (h *Handler) SaveAIConfig(c *fiber.Ctx) error {
 	// 先删除旧配置
 	database.DB.Exec("DELETE FROM ai_configs")
 
+	if cfg.TimeoutSeconds <= 0 {
+		cfg.TimeoutSeconds = ai.DefaultTimeoutSeconds
+	}
+	// 第一个目标语言为主语言；旧版本的双语模式 zh-ug 拆分为中文和维吾尔语两个目标语言
+	langs := strings.Join(cfg.TargetLangs, ",")
+	if langs == "" {
+		langs = cfg.TargetLang
+	}
+	cfg.TargetLangs = textutil.SplitList(strings.ReplaceAll(langs, "zh-ug", "zh-CN,ug"))
+	if len(cfg.TargetLangs) > 0 {
+		cfg.TargetLang = cfg.TargetLangs[0]
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.BatchConcurrency <= 0 {
+		cfg.BatchConcurrency = ai.DefaultBatchConcurrency
+	}
+
 	cfg.ID = uuid.New().String()
 	_, err := database.DB.Exec(`
-		INSERT INTO ai_configs (id, provider, api_key, base_url, model, enable_trans, enable_summary, enable_filter, target_lang)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, cfg.ID, cfg.Provider, cfg.APIKey, cfg.BaseURL, cfg.Model, cfg.EnableTrans, cfg.EnableSummary, cfg.EnableFilter, cfg.TargetLang)
+		INSERT INTO ai_configs (id, provider, api_key, base_url, model, enable_trans, enable_summary, enable_filter, target_lang, target_langs, full_trans_categories, enable_tags, allow_new_tags, enable_entities, enable_sentiment, embedding_model, rate_limit_rpm, rate_limit_tpm, timeout_seconds, max_retries, batch_concurrency)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, cfg.ID, cfg.Provider, cfg.APIKey, cfg.BaseURL, cfg.Model, cfg.EnableTrans, cfg.EnableSummary, cfg.EnableFilter, cfg.TargetLang, strings.Join(cfg.TargetLangs, ","), strings.Join(cfg.FullTransCategories, ","), cfg.EnableTags, cfg.AllowNewTags, cfg.EnableEntities, cfg.EnableSentiment, cfg.EmbeddingModel, cfg.RateLimitRPM, cfg.RateLimitTPM, cfg.TimeoutSeconds, cfg.MaxRetries, cfg.BatchConcurrency)
 
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
@@ -816,10 +1151,12 @@ func (h *Handler) SaveAIConfig(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"success": true})
 }
 
+// TranslateText 翻译文本；stream 为 true 时通过 SSE 流式返回
 func (h *Handler) TranslateText(c *fiber.Ctx) error {
 	var req struct {
 		Text       string `json:"text"`
 		TargetLang string `json:"target_lang"`
+		Stream     bool   `json:"stream"`
 	}
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
@@ -829,6 +1166,12 @@ func (h *Handler) TranslateText(c *fiber.Ctx) error {
 		req.TargetLang = "zh-CN"
 	}
 
+	if req.Stream {
+		return streamCompletion(c, "result", func(ctx context.Context, onDelta func(string) error) (string, error) {
+			return h.ai.TranslateStream(ctx, req.Text, req.TargetLang, onDelta)
+		})
+	}
+
 	result, err := h.ai.Translate(req.Text, req.TargetLang)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
@@ -837,10 +1180,42 @@ func (h *Handler) TranslateText(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"result": result})
 }
 
+// TranslateNewsContent 翻译新闻全文（按段落分片），lang 为空时翻译为主语言
+func (h *Handler) TranslateNewsContent(c *fiber.Ctx) error {
+	var req struct {
+		Lang string `json:"lang"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	list, err := pusher.QueryNews("id = ?", c.Params("id"))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	if len(list) == 0 {
+		return c.Status(404).JSON(fiber.Map{"error": "News not found"})
+	}
+
+	content, err := h.ai.TranslateContent(&list[0], req.Lang)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	lang := req.Lang
+	if lang == "" {
+		lang = h.ai.TargetLang()
+	}
+	return c.JSON(fiber.Map{"lang": lang, "trans_content": content})
+}
+
+// SummarizeText 生成摘要；stream 为 true 时通过 SSE 流式返回
 func (h *Handler) SummarizeText(c *fiber.Ctx) error {
 	var req struct {
 		Text       string `json:"text"`
 		TargetLang string `json:"target_lang"`
+		Stream     bool   `json:"stream"`
 	}
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
@@ -852,6 +1227,12 @@ func (h *Handler) SummarizeText(c *fiber.Ctx) error {
 		targetLang = "zh-CN"
 	}
 
+	if req.Stream {
+		return streamCompletion(c, "result", func(ctx context.Context, onDelta func(string) error) (string, error) {
+			return h.ai.SummarizeStream(ctx, req.Text, targetLang, onDelta)
+		})
+	}
+
 	result, err := h.ai.Summarize(req.Text, targetLang)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
@@ -882,19 +1263,30 @@ func (h *Handler) GetStats(c *fiber.Ctx) error {
 		categoryStats[cat] = count
 	}
 
+	// 最近 sentiment_days 天的情感倾向（整体、按来源、按实体）
+	days := c.QueryInt("sentiment_days", sentiment.DefaultDays)
+	if days < 1 || days > 365 {
+		days = sentiment.DefaultDays
+	}
+	sentimentStats, err := sentiment.Stats(days)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
 	return c.JSON(fiber.Map{
 		"total_news":     totalNews,
 		"today_news":     todayNews,
 		"sources_count":  sourcesCount,
 		"channels_count": channelsCount,
 		"by_category":    categoryStats,
+		"sentiment":      sentimentStats,
 	})
 }
 
 // ========== 自动打包推送 ==========
 
 func (h *Handler) GetAutoPushConfig(c *fiber.Ctx) error {
-	enabled, threshold, channelID, templateID := h.pusher.GetAutoPushConfig()
+	enabled, threshold, channelID, templateID, briefing := h.pusher.GetAutoPushConfig()
 	pendingCount := h.pusher.GetPendingPushCount()
 
 	return c.JSON(fiber.Map{
@@ -902,6 +1294,7 @@ func (h *Handler) GetAutoPushConfig(c *fiber.Ctx) error {
 		"threshold":     threshold,
 		"channel_id":    channelID,
 		"template_id":   templateID,
+		"briefing":      briefing,
 		"pending_count": pendingCount,
 	})
 }
@@ -912,6 +1305,7 @@ func (h *Handler) SaveAutoPushConfig(c *fiber.Ctx) error {
 		Threshold  int    `json:"threshold"`
 		ChannelID  string `json:"channel_id"`
 		TemplateID string `json:"template_id"`
+		Briefing   bool   `json:"briefing"`
 	}
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
@@ -929,12 +1323,17 @@ func (h *Handler) SaveAutoPushConfig(c *fiber.Ctx) error {
 	database.DB.Exec("INSERT OR REPLACE INTO settings (key, value) VALUES ('auto_push_threshold', ?)", fmt.Sprintf("%d", req.Threshold))
 	database.DB.Exec("INSERT OR REPLACE INTO settings (key, value) VALUES ('auto_push_channel_id', ?)", req.ChannelID)
 	database.DB.Exec("INSERT OR REPLACE INTO settings (key, value) VALUES ('auto_push_template_id', ?)", req.TemplateID)
+	briefingStr := "0"
+	if req.Briefing {
+		briefingStr = "1"
+	}
+	database.DB.Exec("INSERT OR REPLACE INTO settings (key, value) VALUES ('auto_push_briefing', ?)", briefingStr)
 
 	return c.JSON(fiber.Map{"success": true})
 }
 
 func (h *Handler) GetAutoPushStatus(c *fiber.Ctx) error {
-	enabled, threshold, _, _ := h.pusher.GetAutoPushConfig()
+	enabled, threshold, _, _, _ := h.pusher.GetAutoPushConfig()
 	pendingCount := h.pusher.GetPendingPushCount()
 
 	return c.JSON(fiber.Map{
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"news-intel-app/internal/services/jobs"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetJobs 后台任务列表（可按 status 筛选）及各状态数量
+func (h *Handler) GetJobs(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 100)
+	if limit < 1 || limit > 1000 {
+		limit = 100
+	}
+	list, err := jobs.List(c.Query("status"), limit)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	counts, err := jobs.Counts()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"jobs": list, "counts": counts})
+}
+
+// RetryJob 重新执行失败或已取消的任务
+func (h *Handler) RetryJob(c *fiber.Ctx) error {
+	return jobResult(c, h.jobs.Retry(c.Params("id")))
+}
+
+// CancelJob 取消排队中的任务
+func (h *Handler) CancelJob(c *fiber.Ctx) error {
+	return jobResult(c, h.jobs.Cancel(c.Params("id")))
+}
+
+func jobResult(c *fiber.Ctx, err error) error {
+	switch err {
+	case nil:
+		return c.JSON(fiber.Map{"success": true})
+	case jobs.ErrNotFound:
+		return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+	case jobs.ErrNotAllowed:
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"strings"
+
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/prompts"
+	"news-intel-app/internal/services/pusher"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *Handler) GetPrompts(c *fiber.Ctx) error {
+	list, err := prompts.List()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(list)
+}
+
+// GetPrompt 提示词详情（含版本历史）
+func (h *Handler) GetPrompt(c *fiber.Ctx) error {
+	p, err := prompts.Get(c.Params("name"))
+	if err == prompts.ErrNotFound {
+		return c.Status(404).JSON(fiber.Map{"error": "Prompt not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(p)
+}
+
+func (h *Handler) CreatePrompt(c *fiber.Ctx) error {
+	var req models.Prompt
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || req.Content == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "name and content are required"})
+	}
+
+	err := prompts.Create(req.Name, req.Description, req.Content)
+	if err == prompts.ErrExists {
+		return c.Status(409).JSON(fiber.Map{"error": "Prompt already exists"})
+	}
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
+
+// UpdatePrompt 修改提示词，保存为新版本
+func (h *Handler) UpdatePrompt(c *fiber.Ctx) error {
+	var req struct {
+		Description string `json:"description"`
+		Content     string `json:"content"`
+		Note        string `json:"note"` // 版本说明
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if req.Content == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "content is required"})
+	}
+
+	err := prompts.Update(c.Params("name"), req.Description, req.Content, req.Note)
+	if err == prompts.ErrNotFound {
+		return c.Status(404).JSON(fiber.Map{"error": "Prompt not found"})
+	}
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	resp := fiber.Map{"success": true, "version": prompts.Version(c.Params("name"))}
+	if prompts.MissingGlossary(c.Params("name"), req.Content) {
+		resp["warning"] = "提示词没有引用 {{.Glossary}}，术语表要求将不会注入，译文可能不遵守术语表"
+	}
+	return c.JSON(resp)
+}
+
+func (h *Handler) DeletePrompt(c *fiber.Ctx) error {
+	err := prompts.Delete(c.Params("name"))
+	if err == prompts.ErrBuiltin {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
+
+// RollbackPrompt 回滚到指定历史版本
+func (h *Handler) RollbackPrompt(c *fiber.Ctx) error {
+	var req struct {
+		Version int `json:"version"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	err := prompts.Rollback(c.Params("name"), req.Version)
+	if err == prompts.ErrVersionAbsent {
+		return c.Status(404).JSON(fiber.Map{"error": "Prompt version not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true, "version": prompts.Version(c.Params("name"))})
+}
+
+// TestPrompt 用示例新闻测试提示词（可传入未保存的草稿 content）
+func (h *Handler) TestPrompt(c *fiber.Ctx) error {
+	var req struct {
+		Content string `json:"content"`
+		NewsID  string `json:"news_id"` // 为空时使用最近的新闻
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	name := c.Params("name")
+	if req.Content == "" && prompts.Version(name) == 0 {
+		return c.Status(404).JSON(fiber.Map{"error": "Prompt not found"})
+	}
+
+	var sample []models.News
+	if req.NewsID != "" {
+		sample, _ = pusher.QueryNews("id = ?", req.NewsID)
+	} else {
+		sample, _ = pusher.QueryNews("is_filtered = 0 ORDER BY created_at DESC LIMIT 3")
+	}
+	if len(sample) == 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "No sample news available"})
+	}
+
+	prompt, output, err := h.ai.TestPrompt(name, req.Content, sample)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error(), "prompt": prompt})
+	}
+	return c.JSON(fiber.Map{"prompt": prompt, "output": output})
+}
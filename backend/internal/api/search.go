@@ -0,0 +1,151 @@
+package api
+
+import (
+	"database/sql"
+	"math"
+	"strings"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/search"
+	"news-intel-app/internal/services/sentiment"
+	"news-intel-app/internal/services/translations"
+	"news-intel-app/internal/textutil"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SearchNews 搜索新闻：semantic 参数按语义相似度排序，q 参数按关键词匹配
+func (h *Handler) SearchNews(c *fiber.Ctx) error {
+	limit := clampLimit(c.QueryInt("limit", 20))
+
+	// 筛选条件对关键词和语义搜索都生效
+	filter, filterArgs, err := sentiment.FilterClause(textutil.SplitList(c.Query("sentiment")))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	clause, fieldArgs, err := fieldFilterClause(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if clause != "" {
+		if filter != "" {
+			filter += " AND "
+		}
+		filter += clause
+		filterArgs = append(filterArgs, fieldArgs...)
+	}
+
+	if query := strings.TrimSpace(c.Query("semantic")); query != "" {
+		// 有筛选条件时先取最多的命中再筛选，避免结果被筛得过少
+		n := limit
+		if filter != "" {
+			n = clampLimit(math.MaxInt)
+		}
+		hits, err := h.search.Semantic(query, n)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		news, err := newsForHits(hits, filter, filterArgs...)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		if len(news) > limit {
+			news = news[:limit]
+		}
+		return c.JSON(fiber.Map{"data": news, "total": len(news)})
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "q or semantic is required"})
+	}
+
+	like := "%" + query + "%"
+	where := "is_filtered = 0 AND (title LIKE ? OR trans_title LIKE ? OR summary LIKE ? OR trans_summary LIKE ?)"
+	args := []interface{}{like, like, like, like}
+	if filter != "" {
+		where += " AND " + filter
+		args = append(args, filterArgs...)
+	}
+	rows, err := database.DB.Query("SELECT "+newsListColumns+" FROM news WHERE "+where+" ORDER BY created_at DESC LIMIT ?", append(args, limit)...)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	defer rows.Close()
+
+	news := scanNewsList(rows)
+	if news == nil {
+		news = []models.News{}
+	}
+	translations.Attach(news)
+	return c.JSON(fiber.Map{"data": news, "total": len(news)})
+}
+
+// GetRelatedNews 与指定新闻语义最相近的新闻
+func (h *Handler) GetRelatedNews(c *fiber.Ctx) error {
+	limit := clampLimit(c.QueryInt("limit", 10))
+	hits, err := h.search.Related(c.Params("id"), limit)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "News not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	news, err := newsForHits(hits, "")
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"data": news})
+}
+
+// clampLimit 将返回条数限制在 1 到 100 之间
+func clampLimit(limit int) int {
+	if limit < 1 {
+		return 1
+	}
+	if limit > 100 {
+		return 100
+	}
+	return limit
+}
+
+// newsForHits 按命中顺序加载新闻并附上相似度；filter 不为空时只保留符合该条件的新闻
+func newsForHits(hits []search.Hit, filter string, filterArgs ...interface{}) ([]models.News, error) {
+	result := []models.News{}
+	if len(hits) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, len(hits))
+	args := make([]interface{}, len(hits))
+	for i, hit := range hits {
+		placeholders[i] = "?"
+		args[i] = hit.NewsID
+	}
+
+	where := "id IN (" + strings.Join(placeholders, ",") + ")"
+	if filter != "" {
+		where += " AND " + filter
+		args = append(args, filterArgs...)
+	}
+	rows, err := database.DB.Query("SELECT "+newsListColumns+" FROM news WHERE "+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[string]models.News)
+	for _, n := range scanNewsList(rows) {
+		byID[n.ID] = n
+	}
+	for _, hit := range hits {
+		if n, ok := byID[hit.NewsID]; ok {
+			n.Score = hit.Score
+			result = append(result, n)
+		}
+	}
+	translations.Attach(result)
+	return result, nil
+}
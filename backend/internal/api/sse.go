@@ -0,0 +1,101 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// setSSEHeaders 设置 SSE 响应头（禁用缓存和反向代理缓冲）
+func setSSEHeaders(c *fiber.Ctx) {
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+}
+
+// writeSSE 写入一个 SSE 事件并立即刷新；客户端断开时返回错误
+func writeSSE(w *bufio.Writer, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// sseHeartbeat 流式响应的心跳间隔，模型迟迟没有输出时也能及时发现客户端断开
+const sseHeartbeat = 10 * time.Second
+
+// streamCompletion 以 SSE 返回一次 AI 生成：delta（片段，多次）→ done（{resultKey: 完整结果}），出错时发送 error。
+// 客户端断开时取消 ctx，中止上游请求；上游输出停顿超过 AI 配置的超时时间时由 AI 客户端中止，同样以 error 结束
+func streamCompletion(c *fiber.Ctx, resultKey string, run func(ctx context.Context, onDelta func(string) error) (string, error)) error {
+	setSSEHeaders(c)
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		defer func() {
+			cancel()
+			wg.Wait()
+		}()
+
+		send := func(event string, data interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+			err := writeSSE(w, event, data)
+			if err != nil {
+				cancel()
+			}
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(sseHeartbeat)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					mu.Lock()
+					_, err := w.WriteString(": ping\n\n")
+					if err == nil {
+						err = w.Flush()
+					}
+					mu.Unlock()
+					if err != nil {
+						cancel()
+						return
+					}
+				}
+			}
+		}()
+
+		result, err := run(ctx, func(delta string) error {
+			return send("delta", fiber.Map{"text": delta})
+		})
+		if ctx.Err() != nil {
+			log.Printf("Stream cancelled: client disconnected")
+			return
+		}
+		if err != nil {
+			log.Printf("Stream error: %v", err)
+			send("error", fiber.Map{"error": err.Error()})
+			return
+		}
+		send("done", fiber.Map{resultKey: result})
+	})
+	return nil
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/jobs"
+	"news-intel-app/internal/services/stories"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetStories 最近的故事列表（每个故事包含所有来源的报道）
+func (h *Handler) GetStories(c *fiber.Ctx) error {
+	hours := c.QueryInt("hours", 48)
+	limit := c.QueryInt("limit", 20)
+
+	list, err := stories.List(hours, limit)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	if list == nil {
+		list = []models.Story{}
+	}
+	return c.JSON(list)
+}
+
+func (h *Handler) GetStory(c *fiber.Ctx) error {
+	st, err := stories.Get(c.Params("id"))
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Story not found"})
+	}
+	return c.JSON(st)
+}
+
+// RebuildStories 立即重新聚类最近的新闻
+func (h *Handler) RebuildStories(c *fiber.Ctx) error {
+	id, err := h.jobs.EnqueueOnce(jobs.TypeStories, nil)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"message": "Story clustering started", "job_id": id})
+}
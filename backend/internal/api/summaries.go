@@ -0,0 +1,155 @@
+package api
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/pusher"
+	"news-intel-app/internal/services/summaries"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// styleNamePattern 样式名称：小写字母、数字、下划线和连字符
+var styleNamePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+func (h *Handler) GetSummaryStyles(c *fiber.Ctx) error {
+	list, err := summaries.List()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(list)
+}
+
+func (h *Handler) CreateSummaryStyle(c *fiber.Ctx) error {
+	var st models.SummaryStyle
+	if err := c.BodyParser(&st); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := normalizeSummaryStyle(&st); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if !styleNamePattern.MatchString(st.Name) {
+		return c.Status(400).JSON(fiber.Map{"error": "name may only contain lowercase letters, digits, '_' and '-'"})
+	}
+
+	err := summaries.Create(&st)
+	if err == summaries.ErrExists {
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(st)
+}
+
+// UpdateSummaryStyle 修改摘要样式（名称不可修改），已生成的该样式摘要会被清除并在下次推送时重新生成
+func (h *Handler) UpdateSummaryStyle(c *fiber.Ctx) error {
+	var st models.SummaryStyle
+	if err := c.BodyParser(&st); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := normalizeSummaryStyle(&st); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	st.ID = c.Params("id")
+
+	err := summaries.Update(st)
+	if err == summaries.ErrNotFound {
+		return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
+
+func (h *Handler) DeleteSummaryStyle(c *fiber.Ctx) error {
+	if err := summaries.Delete(c.Params("id")); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
+
+// GetNewsSummaries 新闻已生成的各样式摘要
+func (h *Handler) GetNewsSummaries(c *fiber.Ctx) error {
+	list, err := summaries.ForNews(c.Params("id"))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(list)
+}
+
+// GenerateNewsSummary 按指定样式（重新）生成新闻摘要，lang 为空时为主语言
+func (h *Handler) GenerateNewsSummary(c *fiber.Ctx) error {
+	var req struct {
+		Style string `json:"style"`
+		Lang  string `json:"lang"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	style, err := summaries.Get(strings.TrimSpace(req.Style))
+	if err == summaries.ErrNotFound {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	list, err := pusher.QueryNews("id = ?", c.Params("id"))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	if len(list) == 0 {
+		return c.Status(404).JSON(fiber.Map{"error": "News not found"})
+	}
+
+	summary, err := h.ai.StyledSummary(&list[0], style, req.Lang)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	lang := req.Lang
+	if lang == "" {
+		lang = h.ai.TargetLang()
+	}
+	return c.JSON(fiber.Map{"style": style.Name, "lang": lang, "summary": summary})
+}
+
+// normalizeSummaryStyle 去除首尾空白并校验格式和长度
+func normalizeSummaryStyle(st *models.SummaryStyle) error {
+	st.Name = strings.TrimSpace(st.Name)
+	st.Label = strings.TrimSpace(st.Label)
+	st.Tone = strings.TrimSpace(st.Tone)
+	st.Instruction = strings.TrimSpace(st.Instruction)
+	if st.Name == "" {
+		return errors.New("name is required")
+	}
+	switch st.Format {
+	case "":
+		st.Format = summaries.FormatParagraph
+	case summaries.FormatParagraph, summaries.FormatBullets, summaries.FormatOneLine:
+	default:
+		return errors.New("format must be paragraph, bullets or one_line")
+	}
+	if st.MaxChars < 10 || st.MaxChars > 2000 {
+		return errors.New("max_chars must be between 10 and 2000")
+	}
+	if st.Format != summaries.FormatBullets {
+		st.Points = 0
+	} else if st.Points < 1 || st.Points > 10 {
+		return errors.New("points must be between 1 and 10")
+	}
+	return nil
+}
+
+// checkSummaryStyle 推送任务选择的摘要样式必须存在（为空表示使用默认摘要）
+func checkSummaryStyle(name string) error {
+	if name == "" {
+		return nil
+	}
+	_, err := summaries.Get(name)
+	return err
+}
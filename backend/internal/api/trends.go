@@ -0,0 +1,118 @@
+package api
+
+import (
+	"errors"
+	"strings"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/jobs"
+	"news-intel-app/internal/services/trends"
+	"news-intel-app/internal/textutil"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetTrends 最近一次分析得到的热点趋势（可按 kind 筛选：term / entity / tag）
+func (h *Handler) GetTrends(c *fiber.Ctx) error {
+	limit := clampLimit(c.QueryInt("limit", 20))
+	list, err := trends.List(c.Query("kind"), limit)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	result := fiber.Map{
+		"data":          list,
+		"window_hours":  trends.WindowHours,
+		"baseline_days": trends.BaselineDays,
+		"computed_at":   nil,
+	}
+	if t := trends.LastComputed(); !t.IsZero() {
+		result["computed_at"] = t
+	}
+	return c.JSON(result)
+}
+
+// RefreshTrends 立即排队一次趋势分析
+func (h *Handler) RefreshTrends(c *fiber.Ctx) error {
+	id, err := h.jobs.EnqueueOnce(jobs.TypeTrends, nil)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"message": "Trend analysis started", "job_id": id})
+}
+
+// GetTrendWatches 关注词列表
+func (h *Handler) GetTrendWatches(c *fiber.Ctx) error {
+	list, err := trends.ListWatches()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(list)
+}
+
+func (h *Handler) CreateTrendWatch(c *fiber.Ctx) error {
+	var w models.TrendWatch
+	if err := c.BodyParser(&w); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := normalizeTrendWatch(&w); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := trends.CreateWatch(&w); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(w)
+}
+
+func (h *Handler) UpdateTrendWatch(c *fiber.Ctx) error {
+	var w models.TrendWatch
+	if err := c.BodyParser(&w); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := normalizeTrendWatch(&w); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	w.ID = c.Params("id")
+
+	err := trends.UpdateWatch(w)
+	if err == trends.ErrWatchNotFound {
+		return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
+
+func (h *Handler) DeleteTrendWatch(c *fiber.Ctx) error {
+	if err := trends.DeleteWatch(c.Params("id")); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
+
+// normalizeTrendWatch 去除首尾空白并校验类型、阈值和推送渠道
+func normalizeTrendWatch(w *models.TrendWatch) error {
+	w.Term = strings.TrimSpace(w.Term)
+	if w.Term == "" {
+		return errors.New("term is required")
+	}
+	switch w.Kind {
+	case "", trends.KindTerm, trends.KindEntity, trends.KindTag:
+	default:
+		return errors.New("kind must be term, entity, tag or empty")
+	}
+	// 标题词按单个词统计，多个词的关注词永远不会匹配
+	if w.Kind == trends.KindTerm && len(textutil.Tokenize(w.Term)) != 1 {
+		return errors.New("term watches must be a single word; use an entity or tag watch for phrases")
+	}
+	if w.MinScore < 0 {
+		return errors.New("min_score must not be negative")
+	}
+	var n int
+	database.DB.QueryRow("SELECT COUNT(*) FROM push_channels WHERE id = ?", w.ChannelID).Scan(&n)
+	if n == 0 {
+		return errors.New("channel not found")
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"strings"
+
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/usage"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetAIUsage AI 调用用量与费用汇总（默认最近 30 天）
+func (h *Handler) GetAIUsage(c *fiber.Ctx) error {
+	days := c.QueryInt("days", 30)
+	if days < 1 || days > 365 {
+		days = 30
+	}
+	summary, err := usage.Summary(days)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(summary)
+}
+
+func (h *Handler) GetAIPricing(c *fiber.Ctx) error {
+	list, err := usage.ListPricing()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(list)
+}
+
+// SaveAIPricing 新增或修改模型单价（美元 / 百万 tokens）和上下文限制
+func (h *Handler) SaveAIPricing(c *fiber.Ctx) error {
+	var req models.ModelPricing
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	req.Model = strings.TrimSpace(req.Model)
+	if req.Model == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "model is required"})
+	}
+	if req.PromptPrice < 0 || req.CompletionPrice < 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "prices must not be negative"})
+	}
+	if req.ContextTokens < 0 || req.MaxOutputTokens < 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "token limits must not be negative"})
+	}
+	if err := usage.SavePricing(req); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
+
+func (h *Handler) DeleteAIPricing(c *fiber.Ctx) error {
+	if err := usage.DeletePricing(c.Params("model")); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
+
+// GetAIBudget 预算设置及本日、本月已用量
+func (h *Handler) GetAIBudget(c *fiber.Ctx) error {
+	status, err := usage.CheckBudget()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(status)
+}
+
+// SaveAIBudget 保存预算设置（daily / monthly 为 0 表示不限制）
+func (h *Handler) SaveAIBudget(c *fiber.Ctx) error {
+	var req models.Budget
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if req.Unit == "" {
+		req.Unit = usage.UnitTokens
+	}
+	if req.Unit != usage.UnitTokens && req.Unit != usage.UnitCost {
+		return c.Status(400).JSON(fiber.Map{"error": "unit must be tokens or cost"})
+	}
+	if req.Daily < 0 || req.Monthly < 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "budget must not be negative"})
+	}
+	req.FallbackModel = strings.TrimSpace(req.FallbackModel)
+	if err := usage.SaveBudget(req); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
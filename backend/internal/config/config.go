@@ -10,6 +10,7 @@ type Config struct {
 	Port        string
 	DBPath      string
 	DataDir     string
+	AIProvider  string
 	OpenAIKey   string
 	OpenAIBase  string
 	OpenAIModel string
@@ -24,6 +25,7 @@ func Load() *Config {
 		Port:        getEnv("PORT", "5555"),
 		DBPath:      getEnv("DB_PATH", "./data/news.db"),
 		DataDir:     getEnv("DATA_DIR", "./data"),
+		AIProvider:  getEnv("AI_PROVIDER", "openai"),
 		OpenAIKey:   getEnv("OPENAI_API_KEY", ""),
 		OpenAIBase:  getEnv("OPENAI_BASE_URL", "https://api.openai.com/v1"),
 		OpenAIModel: getEnv("OPENAI_MODEL", "gpt-4o-mini"),
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -18,8 +19,13 @@ func Init(dbPath string) error {
 		return err
 	}
 
+	// dbPath 可以是带参数的 URI（如测试用的 file:test?mode=memory&cache=shared）
+	sep := "?"
+	if strings.Contains(dbPath, "?") {
+		sep = "&"
+	}
 	var err error
-	DB, err = sql.Open("sqlite3", dbPath+"?_journal_mode=WAL&_busy_timeout=5000")
+	DB, err = sql.Open("sqlite3", dbPath+sep+"_journal_mode=WAL&_busy_timeout=5000")
 	if err != nil {
 		return err
 	}
@@ -57,7 +63,14 @@ func createTables() error {
 		in_reading INTEGER DEFAULT 0,
 		reading_at DATETIME,
 		pushed INTEGER DEFAULT 0,
-		pushed_at DATETIME
+		pushed_at DATETIME,
+		story_id TEXT,
+		source_id TEXT,
+		pref_score REAL,
+		pref_filtered INTEGER DEFAULT 0,
+		sentiment TEXT DEFAULT '',
+		sentiment_score REAL,
+		fields TEXT
 	);
 
 	-- 新闻源表
@@ -69,6 +82,7 @@ func createTables() error {
 		category TEXT,
 		enabled INTEGER DEFAULT 1,
 		interval_mins INTEGER DEFAULT 60,
+		full_translate INTEGER DEFAULT 0,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
@@ -106,6 +120,18 @@ func createTables() error {
 		enable_summary INTEGER DEFAULT 1,
 		enable_filter INTEGER DEFAULT 0,
 		target_lang TEXT DEFAULT 'zh-CN',
+		target_langs TEXT DEFAULT '',
+		full_trans_categories TEXT DEFAULT '',
+		batch_concurrency INTEGER DEFAULT 2,
+		enable_tags INTEGER DEFAULT 1,
+		allow_new_tags INTEGER DEFAULT 0,
+		enable_entities INTEGER DEFAULT 1,
+		enable_sentiment INTEGER DEFAULT 0,
+		embedding_model TEXT DEFAULT '',
+		rate_limit_rpm INTEGER DEFAULT 0,
+		rate_limit_tpm INTEGER DEFAULT 0,
+		timeout_seconds INTEGER DEFAULT 60,
+		max_retries INTEGER DEFAULT 3,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
@@ -118,12 +144,271 @@ func createTables() error {
 		channel_id TEXT,
 		template_id TEXT,
 		categories TEXT,
+		tags TEXT,
+		briefing INTEGER DEFAULT 0,
+		summary_style TEXT DEFAULT '',
 		enabled INTEGER DEFAULT 1,
 		last_run_at DATETIME,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
 
+	-- 标签表（受控词表）
+	CREATE TABLE IF NOT EXISTS tags (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE,
+		synonyms TEXT,
+		status TEXT DEFAULT 'approved',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- 新闻标签关联表
+	CREATE TABLE IF NOT EXISTS news_tags (
+		news_id TEXT NOT NULL,
+		tag_id TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (news_id, tag_id)
+	);
+
+	-- 实体表（组织、人物、产品、地点）
+	CREATE TABLE IF NOT EXISTS entities (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		type TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (name, type)
+	);
+
+	-- 实体别名表（规范化后的别名 -> 实体）
+	CREATE TABLE IF NOT EXISTS entity_aliases (
+		alias TEXT NOT NULL,
+		type TEXT NOT NULL,
+		entity_id TEXT NOT NULL,
+		PRIMARY KEY (alias, type)
+	);
+
+	-- 新闻实体关联表
+	CREATE TABLE IF NOT EXISTS news_entities (
+		news_id TEXT NOT NULL,
+		entity_id TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (news_id, entity_id)
+	);
+
+	-- 故事表（同一事件的多篇报道聚类）
+	CREATE TABLE IF NOT EXISTS stories (
+		id TEXT PRIMARY KEY,
+		title TEXT,
+		summary TEXT,
+		news_count INTEGER DEFAULT 0,
+		signature TEXT,
+		first_seen_at DATETIME,
+		last_seen_at DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- 新闻向量表（语义搜索）
+	CREATE TABLE IF NOT EXISTS news_embeddings (
+		news_id TEXT PRIMARY KEY,
+		model TEXT NOT NULL,
+		dim INTEGER NOT NULL,
+		vector BLOB NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- 问答会话表
+	CREATE TABLE IF NOT EXISTS conversations (
+		id TEXT PRIMARY KEY,
+		title TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- 问答消息表
+	CREATE TABLE IF NOT EXISTS conversation_messages (
+		id TEXT PRIMARY KEY,
+		conversation_id TEXT NOT NULL,
+		role TEXT NOT NULL,
+		content TEXT,
+		citations TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- 提示词表
+	CREATE TABLE IF NOT EXISTS prompts (
+		name TEXT PRIMARY KEY,
+		description TEXT,
+		content TEXT NOT NULL,
+		version INTEGER DEFAULT 1,
+		builtin INTEGER DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- 提示词版本历史
+	CREATE TABLE IF NOT EXISTS prompt_versions (
+		name TEXT NOT NULL,
+		version INTEGER NOT NULL,
+		content TEXT NOT NULL,
+		note TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (name, version)
+	);
+
+	-- AI 调用记录
+	CREATE TABLE IF NOT EXISTS ai_usage (
+		id TEXT PRIMARY KEY,
+		operation TEXT NOT NULL,
+		model TEXT,
+		prompt_tokens INTEGER DEFAULT 0,
+		completion_tokens INTEGER DEFAULT 0,
+		total_tokens INTEGER DEFAULT 0,
+		cost REAL DEFAULT 0,
+		latency_ms INTEGER DEFAULT 0,
+		success INTEGER DEFAULT 1,
+		error TEXT,
+		news_ids TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- 模型单价（美元 / 百万 tokens）和上下文限制（用于批量翻译分批）
+	CREATE TABLE IF NOT EXISTS ai_pricing (
+		model TEXT PRIMARY KEY,
+		prompt_price REAL DEFAULT 0,
+		completion_price REAL DEFAULT 0,
+		context_tokens INTEGER DEFAULT 0,
+		max_output_tokens INTEGER DEFAULT 0
+	);
+
+	-- 新闻译文（每种目标语言一行）
+	CREATE TABLE IF NOT EXISTS news_translations (
+		news_id TEXT NOT NULL,
+		lang TEXT NOT NULL,
+		title TEXT,
+		summary TEXT,
+		content TEXT,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (news_id, lang)
+	);
+
+	-- 术语表（lang 为空表示适用于所有目标语言）
+	CREATE TABLE IF NOT EXISTS glossary (
+		id TEXT PRIMARY KEY,
+		lang TEXT DEFAULT '',
+		term TEXT NOT NULL,
+		translation TEXT DEFAULT '',
+		keep_original INTEGER DEFAULT 0,
+		note TEXT DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(lang, term)
+	);
+
+	-- 摘要样式（长度、格式、语气）
+	CREATE TABLE IF NOT EXISTS summary_styles (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE,
+		label TEXT DEFAULT '',
+		format TEXT DEFAULT 'paragraph',
+		max_chars INTEGER DEFAULT 100,
+		points INTEGER DEFAULT 0,
+		tone TEXT DEFAULT '',
+		instruction TEXT DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- 按样式生成的新闻摘要
+	CREATE TABLE IF NOT EXISTS news_summaries (
+		news_id TEXT NOT NULL,
+		style TEXT NOT NULL,
+		lang TEXT NOT NULL,
+		summary TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (news_id, style, lang)
+	);
+
+	-- 读者反馈（每条新闻一条，用于训练本地偏好模型）
+	CREATE TABLE IF NOT EXISTS news_feedback (
+		news_id TEXT PRIMARY KEY,
+		label TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- 趋势：最近窗口内出现次数显著高于基线的词、实体和标签（每次分析后整体替换）
+	CREATE TABLE IF NOT EXISTS trends (
+		kind TEXT NOT NULL,
+		term TEXT NOT NULL,
+		count INTEGER DEFAULT 0,
+		baseline REAL DEFAULT 0,
+		score REAL DEFAULT 0,
+		news_ids TEXT DEFAULT '',
+		computed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (kind, term)
+	);
+
+	-- 关注词：关注的词、实体或标签出现突增时推送提醒
+	CREATE TABLE IF NOT EXISTS trend_watches (
+		id TEXT PRIMARY KEY,
+		term TEXT NOT NULL,
+		kind TEXT DEFAULT '',
+		channel_id TEXT NOT NULL,
+		min_score REAL DEFAULT 0,
+		enabled INTEGER DEFAULT 1,
+		last_fired_at DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- 译文未遵守术语表的记录
+	CREATE TABLE IF NOT EXISTS glossary_violations (
+		id TEXT PRIMARY KEY,
+		news_id TEXT DEFAULT '',
+		lang TEXT,
+		operation TEXT,
+		term TEXT,
+		expected TEXT,
+		output TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- 翻译缓存（key 为原文、目标语言、提示词版本和模型的哈希）
+	CREATE TABLE IF NOT EXISTS translation_cache (
+		key TEXT PRIMARY KEY,
+		kind TEXT NOT NULL,
+		model TEXT,
+		value TEXT NOT NULL,
+		hits INTEGER DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		last_used_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- 后台任务队列
+	CREATE TABLE IF NOT EXISTS jobs (
+		id TEXT PRIMARY KEY,
+		type TEXT NOT NULL,
+		payload TEXT DEFAULT '{}',
+		status TEXT DEFAULT 'pending',
+		attempts INTEGER DEFAULT 0,
+		max_attempts INTEGER DEFAULT 5,
+		next_run_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		last_error TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		finished_at DATETIME
+	);
+
+	-- 管理员告警（key 用于去重，同一事件只告警一次）
+	CREATE TABLE IF NOT EXISTS alerts (
+		id TEXT PRIMARY KEY,
+		key TEXT UNIQUE,
+		level TEXT DEFAULT 'warning',
+		message TEXT NOT NULL,
+		acknowledged INTEGER DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
 	-- 系统设置表
 	CREATE TABLE IF NOT EXISTS settings (
 		key TEXT PRIMARY KEY,
@@ -137,10 +422,261 @@ func createTables() error {
 	CREATE INDEX IF NOT EXISTS idx_news_published ON news(published_at);
 	CREATE INDEX IF NOT EXISTS idx_news_reading ON news(in_reading);
 	CREATE INDEX IF NOT EXISTS idx_news_translated ON news(translated);
+	CREATE INDEX IF NOT EXISTS idx_news_tags_tag ON news_tags(tag_id);
+	CREATE INDEX IF NOT EXISTS idx_news_entities_entity ON news_entities(entity_id);
+	CREATE INDEX IF NOT EXISTS idx_entity_aliases_entity ON entity_aliases(entity_id);
+	CREATE INDEX IF NOT EXISTS idx_ai_usage_created ON ai_usage(created_at);
+	CREATE INDEX IF NOT EXISTS idx_translation_cache_used ON translation_cache(last_used_at);
+	CREATE INDEX IF NOT EXISTS idx_glossary_violations_created ON glossary_violations(created_at);
+	CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status, next_run_at);
+	CREATE INDEX IF NOT EXISTS idx_conversation_messages_conv ON conversation_messages(conversation_id, created_at);
 	`
 
-	_, err := DB.Exec(tables)
-	return err
+	if _, err := DB.Exec(tables); err != nil {
+		return err
+	}
+
+	return migrate()
+}
+
+// migrations 旧版本数据库需要补充的列
+var migrations = []struct {
+	table  string
+	column string
+	def    string
+}{
+	{"news", "story_id", "TEXT"},
+	{"push_tasks", "tags", "TEXT"},
+	{"push_tasks", "briefing", "INTEGER DEFAULT 0"},
+	{"ai_configs", "enable_tags", "INTEGER DEFAULT 1"},
+	{"ai_configs", "allow_new_tags", "INTEGER DEFAULT 0"},
+	{"ai_configs", "enable_entities", "INTEGER DEFAULT 1"},
+	{"ai_configs", "embedding_model", "TEXT DEFAULT ''"},
+	{"ai_configs", "target_langs", "TEXT DEFAULT ''"},
+	{"ai_configs", "full_trans_categories", "TEXT DEFAULT ''"},
+	{"ai_configs", "rate_limit_rpm", "INTEGER DEFAULT 0"},
+	{"ai_configs", "rate_limit_tpm", "INTEGER DEFAULT 0"},
+	{"ai_configs", "timeout_seconds", "INTEGER DEFAULT 60"},
+	{"ai_configs", "max_retries", "INTEGER DEFAULT 3"},
+	{"news_sources", "full_translate", "INTEGER DEFAULT 0"},
+	{"ai_configs", "batch_concurrency", "INTEGER DEFAULT 2"},
+	{"ai_pricing", "context_tokens", "INTEGER DEFAULT 0"},
+	{"ai_pricing", "max_output_tokens", "INTEGER DEFAULT 0"},
+	{"push_tasks", "summary_style", "TEXT DEFAULT ''"},
+	{"news", "pref_score", "REAL"},
+	{"ai_configs", "enable_sentiment", "INTEGER DEFAULT 0"},
+	{"news", "sentiment", "TEXT DEFAULT ''"},
+	{"news", "sentiment_score", "REAL"},
+	{"news", "fields", "TEXT"},
+	{"news", "source_id", "TEXT"},
+	{"news", "pref_filtered", "INTEGER DEFAULT 0"},
+}
+
+// migrationFills 补列后回填已有数据的语句，键为 "表.列"
+var migrationFills = map[string]string{
+	"news.source_id": "UPDATE news SET source_id = (SELECT id FROM news_sources WHERE news_sources.name = news.source LIMIT 1)",
+}
+
+// migrationIndexes 依赖迁移列的索引，需在补列之后创建
+const migrationIndexes = `
+	CREATE INDEX IF NOT EXISTS idx_news_story ON news(story_id);
+	CREATE INDEX IF NOT EXISTS idx_news_sentiment ON news(sentiment);
+`
+
+// migrate 为已有数据库补充新增的列
+func migrate() error {
+	for _, m := range migrations {
+		exists, err := columnExists(m.table, m.column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := DB.Exec("ALTER TABLE " + m.table + " ADD COLUMN " + m.column + " " + m.def); err != nil {
+			return err
+		}
+		if fill, ok := migrationFills[m.table+"."+m.column]; ok {
+			if _, err := DB.Exec(fill); err != nil {
+				return err
+			}
+		}
+		log.Printf("Migrated: added column %s.%s", m.table, m.column)
+	}
+
+	if _, err := DB.Exec(migrationIndexes); err != nil {
+		return err
+	}
+	return migrateTranslations()
+}
+
+// 旧版本双语模式（zh-ug）的分隔标记
+const (
+	markerZH = "【中文】"
+	markerUG = "【ئۇيغۇرچە】"
+)
+
+// migrateTranslations 把旧版本只存在 trans_title / trans_summary 中的译文补写到 news_translations，
+// 并把双语模式 zh-ug 拆分为 zh-CN 和 ug 两个目标语言。只执行一次
+func migrateTranslations() error {
+	var done string
+	err := DB.QueryRow("SELECT value FROM settings WHERE key = 'migrated_translations'").Scan(&done)
+	if err == nil {
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	primary, targetLangs := "zh-CN", ""
+	err = DB.QueryRow("SELECT COALESCE(target_lang, 'zh-CN'), COALESCE(target_langs, '') FROM ai_configs LIMIT 1").Scan(&primary, &targetLangs)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT OR IGNORE INTO news_translations (news_id, lang, title, summary, content)
+		SELECT id, ?, trans_title, COALESCE(trans_summary, ''), COALESCE(trans_content, '')
+		FROM news WHERE translated = 1 AND COALESCE(trans_title, '') != ''`, primary); err != nil {
+		return err
+	}
+
+	if targetLangs == "" {
+		targetLangs = primary
+	}
+	if strings.Contains(targetLangs, "zh-ug") {
+		if err := migrateBilingual(tx, targetLangs); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("INSERT INTO settings (key, value) VALUES ('migrated_translations', '1')"); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	log.Println("Migrated: backfilled news_translations")
+	return nil
+}
+
+// migrateBilingual 将 zh-ug 配置改为 zh-CN,ug，带标记的双语译文拆成两种语言分别保存
+func migrateBilingual(tx *sql.Tx, targetLangs string) error {
+	var langs []string
+	seen := make(map[string]bool)
+	for _, lang := range strings.Split(targetLangs, ",") {
+		lang = strings.TrimSpace(lang)
+		if lang == "" {
+			continue
+		}
+		expanded := []string{lang}
+		if lang == "zh-ug" {
+			expanded = []string{"zh-CN", "ug"}
+		}
+		for _, l := range expanded {
+			if !seen[l] {
+				seen[l] = true
+				langs = append(langs, l)
+			}
+		}
+	}
+	if _, err := tx.Exec("UPDATE ai_configs SET target_lang = ?, target_langs = ?", langs[0], strings.Join(langs, ",")); err != nil {
+		return err
+	}
+	// zh-ug 原为主语言时，trans_title / trans_summary 中的双语文本也改为中文部分
+	primary := strings.HasPrefix(strings.TrimSpace(targetLangs), "zh-ug")
+
+	rows, err := tx.Query("SELECT news_id, COALESCE(title, ''), COALESCE(summary, ''), COALESCE(content, '') FROM news_translations WHERE lang = 'zh-ug'")
+	if err != nil {
+		return err
+	}
+	type bilingual struct{ newsID, title, summary, content string }
+	var items []bilingual
+	for rows.Next() {
+		var b bilingual
+		if err := rows.Scan(&b.newsID, &b.title, &b.summary, &b.content); err != nil {
+			rows.Close()
+			return err
+		}
+		items = append(items, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, b := range items {
+		zhTitle, ugTitle := splitMarkers(b.title)
+		zhSummary, ugSummary := splitMarkers(b.summary)
+		if _, err := tx.Exec(`
+			INSERT OR IGNORE INTO news_translations (news_id, lang, title, summary, content) VALUES (?, 'zh-CN', ?, ?, ?)`,
+			b.newsID, zhTitle, zhSummary, b.content); err != nil {
+			return err
+		}
+		if ugTitle != "" || ugSummary != "" {
+			if _, err := tx.Exec(`
+				INSERT OR IGNORE INTO news_translations (news_id, lang, title, summary) VALUES (?, 'ug', ?, ?)`,
+				b.newsID, ugTitle, ugSummary); err != nil {
+				return err
+			}
+		}
+		if primary {
+			if _, err := tx.Exec("UPDATE news SET trans_title = ?, trans_summary = ? WHERE id = ?", zhTitle, zhSummary, b.newsID); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 术语表条目归入中文；双语样式摘要缓存直接丢弃，推送时重新生成
+	for _, q := range []string{
+		"DELETE FROM news_translations WHERE lang = 'zh-ug'",
+		"DELETE FROM news_summaries WHERE lang = 'zh-ug'",
+		"UPDATE OR IGNORE glossary SET lang = 'zh-CN' WHERE lang = 'zh-ug'",
+		"DELETE FROM glossary WHERE lang = 'zh-ug'",
+	} {
+		if _, err := tx.Exec(q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitMarkers 拆分旧版本双语模式的 "【中文】...\n【ئۇيغۇرچە】..." 文本；没有标记时整段视为中文
+func splitMarkers(text string) (zh, ug string) {
+	if i := strings.Index(text, markerUG); i >= 0 {
+		zh, ug = text[:i], text[i+len(markerUG):]
+	} else {
+		zh = text
+	}
+	zh = strings.TrimPrefix(strings.TrimSpace(zh), markerZH)
+	return strings.TrimSpace(zh), strings.TrimSpace(ug)
+}
+
+func columnExists(table, column string) (bool, error) {
+	rows, err := DB.Query("PRAGMA table_info(" + table + ")")
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
 }
 
 func Close() {
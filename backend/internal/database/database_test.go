@@ -0,0 +1,92 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSplitMarkers(t *testing.T) {
+	tests := []struct {
+		text, zh, ug string
+	}{
+		{"【中文】苹果发布新手机\n【ئۇيغۇرچە】ئالما يېڭى تېلېفون ئېلان قىلدى", "苹果发布新手机", "ئالما يېڭى تېلېفون ئېلان قىلدى"},
+		{"苹果发布新手机", "苹果发布新手机", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		zh, ug := splitMarkers(tt.text)
+		if zh != tt.zh || ug != tt.ug {
+			t.Errorf("splitMarkers(%q) = %q, %q; want %q, %q", tt.text, zh, ug, tt.zh, tt.ug)
+		}
+	}
+}
+
+func TestMigrateTranslations(t *testing.T) {
+	if err := Init(filepath.Join(t.TempDir(), "news.db")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { DB.Close() })
+
+	for _, q := range []string{
+		"DELETE FROM settings WHERE key = 'migrated_translations'",
+		"INSERT INTO ai_configs (id, provider, target_lang, target_langs) VALUES ('c1', 'mock', 'zh-ug', 'zh-ug,en')",
+		"INSERT INTO news (id, title, url, translated, trans_title, trans_summary) VALUES ('n1', 'Apple', 'u1', 1, '【中文】苹果\n【ئۇيغۇرچە】ئالما', '【中文】摘要\n【ئۇيغۇرچە】خۇلاسە')",
+		"INSERT INTO news (id, title, url, translated) VALUES ('n2', 'Pending', 'u2', 0)",
+		"INSERT INTO glossary (id, lang, term) VALUES ('g1', 'zh-ug', 'Apple')",
+	} {
+		if _, err := DB.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := migrateTranslations(); err != nil {
+		t.Fatal(err)
+	}
+
+	var lang, langs string
+	DB.QueryRow("SELECT target_lang, target_langs FROM ai_configs").Scan(&lang, &langs)
+	if lang != "zh-CN" || langs != "zh-CN,ug,en" {
+		t.Errorf("config = %q, %q; want zh-CN, zh-CN,ug,en", lang, langs)
+	}
+
+	want := map[string][2]string{"zh-CN": {"苹果", "摘要"}, "ug": {"ئالما", "خۇلاسە"}}
+	rows, err := DB.Query("SELECT lang, title, summary FROM news_translations WHERE news_id = 'n1'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string][2]string)
+	for rows.Next() {
+		var l, title, summary string
+		rows.Scan(&l, &title, &summary)
+		got[l] = [2]string{title, summary}
+	}
+	rows.Close()
+	if len(got) != len(want) || got["zh-CN"] != want["zh-CN"] || got["ug"] != want["ug"] {
+		t.Errorf("translations = %v, want %v", got, want)
+	}
+
+	var transTitle string
+	DB.QueryRow("SELECT trans_title FROM news WHERE id = 'n1'").Scan(&transTitle)
+	if transTitle != "苹果" {
+		t.Errorf("trans_title = %q, want 苹果", transTitle)
+	}
+
+	var count int
+	DB.QueryRow("SELECT COUNT(*) FROM news_translations WHERE news_id = 'n2'").Scan(&count)
+	if count != 0 {
+		t.Errorf("untranslated news got %d translations", count)
+	}
+	DB.QueryRow("SELECT lang FROM glossary WHERE id = 'g1'").Scan(&lang)
+	if lang != "zh-CN" {
+		t.Errorf("glossary lang = %q, want zh-CN", lang)
+	}
+
+	// 只执行一次
+	DB.Exec("UPDATE ai_configs SET target_lang = 'zh-ug'")
+	if err := migrateTranslations(); err != nil {
+		t.Fatal(err)
+	}
+	DB.QueryRow("SELECT target_lang FROM ai_configs").Scan(&lang)
+	if lang != "zh-ug" {
+		t.Errorf("migration ran twice")
+	}
+}
@@ -4,48 +4,77 @@ import "time"
 
 // News 新闻模型
 type News struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Content     string    `json:"content"`
-	Summary     string    `json:"summary"`
-	URL         string    `json:"url"`
-	Source      string    `json:"source"`      // 来源: rss, twitter, github, hackernews
-	Category    string    `json:"category"`    // 分类: tech, ai, international, trending
-	ImageURL    string    `json:"image_url"`
-	Author      string    `json:"author"`
-	PublishedAt time.Time `json:"published_at"`
-	CreatedAt   time.Time `json:"created_at"`
-	Translated  bool      `json:"translated"`
-	TransTitle  string    `json:"trans_title"`   // 翻译后标题
-	TransContent string   `json:"trans_content"` // 翻译后内容
-	TransSummary string   `json:"trans_summary"` // 翻译后摘要
-	IsFiltered  bool      `json:"is_filtered"`   // 是否被AI筛选掉
-	Tags        string    `json:"tags"`          // 标签，逗号分隔
-	InReading   bool      `json:"in_reading"`    // 是否在阅读窗口
-	ReadingAt   time.Time `json:"reading_at"`    // 加入阅读窗口时间
-	Pushed      bool      `json:"pushed"`        // 是否已推送
-	PushedAt    time.Time `json:"pushed_at"`     // 推送时间
+	ID             string                 `json:"id"`
+	Title          string                 `json:"title"`
+	Content        string                 `json:"content"`
+	Summary        string                 `json:"summary"`
+	URL            string                 `json:"url"`
+	Source         string                 `json:"source"`              // 来源: rss, twitter, github, hackernews
+	SourceID       string                 `json:"source_id,omitempty"` // 采集该新闻的新闻源 ID
+	Category       string                 `json:"category"`            // 分类: tech, ai, international, trending
+	ImageURL       string                 `json:"image_url"`
+	Author         string                 `json:"author"`
+	PublishedAt    time.Time              `json:"published_at"`
+	CreatedAt      time.Time              `json:"created_at"`
+	Translated     bool                   `json:"translated"`
+	TransTitle     string                 `json:"trans_title"`               // 翻译后标题
+	TransContent   string                 `json:"trans_content"`             // 翻译后内容
+	TransSummary   string                 `json:"trans_summary"`             // 翻译后摘要
+	IsFiltered     bool                   `json:"is_filtered"`               // 是否被AI筛选掉
+	Tags           string                 `json:"tags"`                      // 标签，逗号分隔
+	InReading      bool                   `json:"in_reading"`                // 是否在阅读窗口
+	ReadingAt      time.Time              `json:"reading_at"`                // 加入阅读窗口时间
+	Pushed         bool                   `json:"pushed"`                    // 是否已推送
+	PushedAt       time.Time              `json:"pushed_at"`                 // 推送时间
+	Entities       []EntityMention        `json:"entities,omitempty"`        // AI提取的实体
+	StoryID        string                 `json:"story_id,omitempty"`        // 所属故事（聚类）
+	Score          float64                `json:"score,omitempty"`           // 语义搜索相似度
+	Translations   map[string]Translation `json:"translations,omitempty"`    // 各目标语言的译文
+	SummaryStyle   string                 `json:"summary_style,omitempty"`   // TransSummary 已替换为该样式的摘要
+	Preference     *float64               `json:"preference,omitempty"`      // 偏好模型评分（0-1，越高越可能感兴趣）
+	Feedback       string                 `json:"feedback,omitempty"`        // 读者反馈：up / down / not_interested
+	Sentiment      string                 `json:"sentiment,omitempty"`       // 情感倾向：positive / negative / neutral（批量翻译时由 AI 判断）
+	SentimentScore *float64               `json:"sentiment_score,omitempty"` // 情感评分（-1 最负面，1 最正面）
+	Fields         map[string]interface{} `json:"fields,omitempty"`          // 自定义提取字段的值，模板中以 .Fields.<name> 引用
+}
+
+// Translation 新闻某一语言的译文
+type Translation struct {
+	Lang    string `json:"lang"`
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+	Content string `json:"content,omitempty"`
+}
+
+// T 取指定语言的译文，供模板使用：{{with .T "ug"}}{{.Title}}{{end}}。
+// 没有该语言译文时标题使用原标题
+func (n News) T(lang string) Translation {
+	if t, ok := n.Translations[lang]; ok {
+		return t
+	}
+	return Translation{Lang: lang, Title: n.Title}
 }
 
 // NewsSource 新闻源配置
 type NewsSource struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Type      string    `json:"type"`       // rss, api, scraper
-	URL       string    `json:"url"`
-	Category  string    `json:"category"`
-	Enabled   bool      `json:"enabled"`
-	Interval  int       `json:"interval"`   // 采集间隔(分钟)
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Type          string    `json:"type"` // rss, api, scraper
+	URL           string    `json:"url"`
+	Category      string    `json:"category"`
+	Enabled       bool      `json:"enabled"`
+	Interval      int       `json:"interval"`       // 采集间隔(分钟)
+	FullTranslate bool      `json:"full_translate"` // 自动全文翻译
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 // PushChannel 推送渠道配置
 type PushChannel struct {
 	ID        string    `json:"id"`
 	Name      string    `json:"name"`
-	Type      string    `json:"type"`       // email, ntfy, webhook
-	Config    string    `json:"config"`     // JSON配置
+	Type      string    `json:"type"`   // email, ntfy, webhook
+	Config    string    `json:"config"` // JSON配置
 	Enabled   bool      `json:"enabled"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -53,13 +82,13 @@ type PushChannel struct {
 
 // EmailConfig 邮箱配置
 type EmailConfig struct {
-	SMTPHost     string `json:"smtp_host"`
-	SMTPPort     int    `json:"smtp_port"`
-	Username     string `json:"username"`
-	Password     string `json:"password"`
-	FromAddress  string `json:"from_address"`
-	FromName     string `json:"from_name"`
-	ToAddresses  string `json:"to_addresses"` // 逗号分隔
+	SMTPHost    string `json:"smtp_host"`
+	SMTPPort    int    `json:"smtp_port"`
+	Username    string `json:"username"`
+	Password    string `json:"password"`
+	FromAddress string `json:"from_address"`
+	FromName    string `json:"from_name"`
+	ToAddresses string `json:"to_addresses"` // 逗号分隔
 }
 
 // NtfyConfig ntfy配置
@@ -74,7 +103,7 @@ type EmailTemplate struct {
 	ID        string    `json:"id"`
 	Name      string    `json:"name"`
 	Subject   string    `json:"subject"`
-	Content   string    `json:"content"`     // HTML内容
+	Content   string    `json:"content"` // HTML内容
 	IsDefault bool      `json:"is_default"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -82,29 +111,346 @@ type EmailTemplate struct {
 
 // AIConfig AI配置
 type AIConfig struct {
-	ID           string `json:"id"`
-	Provider     string `json:"provider"`      // openai, claude, ollama
-	APIKey       string `json:"api_key"`
-	BaseURL      string `json:"base_url"`
-	Model        string `json:"model"`
-	EnableTrans  bool   `json:"enable_trans"`  // 启用翻译
-	EnableSummary bool  `json:"enable_summary"` // 启用摘要
-	EnableFilter bool   `json:"enable_filter"`  // 启用筛选
-	TargetLang   string `json:"target_lang"`   // 目标语言
+	ID                  string   `json:"id"`
+	Provider            string   `json:"provider"` // openai, claude, ollama
+	APIKey              string   `json:"api_key"`
+	BaseURL             string   `json:"base_url"`
+	Model               string   `json:"model"`
+	EnableTrans         bool     `json:"enable_trans"`          // 启用翻译
+	EnableSummary       bool     `json:"enable_summary"`        // 启用摘要
+	EnableFilter        bool     `json:"enable_filter"`         // 启用筛选
+	TargetLang          string   `json:"target_lang"`           // 目标语言
+	TargetLangs         []string `json:"target_langs"`          // 多个目标语言（第一个为主语言，与 TargetLang 一致）
+	BatchConcurrency    int      `json:"batch_concurrency"`     // 同时进行的批量翻译请求数
+	FullTransCategories []string `json:"full_trans_categories"` // 自动全文翻译的分类
+	EnableTags          bool     `json:"enable_tags"`           // 启用自动打标签
+	AllowNewTags        bool     `json:"allow_new_tags"`        // 允许AI提议新标签（需审核）
+	EnableEntities      bool     `json:"enable_entities"`       // 启用实体提取
+	EnableSentiment     bool     `json:"enable_sentiment"`      // 启用情感倾向分析（在批量翻译中完成）
+	EmbeddingModel      string   `json:"embedding_model"`       // 向量模型（为空时使用默认模型）
+	RateLimitRPM        int      `json:"rate_limit_rpm"`        // 每分钟请求数上限（0 不限制）
+	RateLimitTPM        int      `json:"rate_limit_tpm"`        // 每分钟 tokens 上限（0 不限制）
+	TimeoutSeconds      int      `json:"timeout_seconds"`       // 单次调用超时
+	MaxRetries          int      `json:"max_retries"`           // 限流或服务端错误时的重试次数
 }
 
 // PushTask 推送任务
 type PushTask struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	CronExpr     string    `json:"cron_expr"` // cron表达式
+	ChannelID    string    `json:"channel_id"`
+	TemplateID   string    `json:"template_id"`
+	Categories   string    `json:"categories"`    // 推送的分类，逗号分隔
+	Tags         string    `json:"tags"`          // 推送的标签，逗号分隔
+	Briefing     bool      `json:"briefing"`      // 推送前由AI生成综述
+	SummaryStyle string    `json:"summary_style"` // 模板渲染的摘要样式（为空时使用默认摘要）
+	Enabled      bool      `json:"enabled"`
+	LastRunAt    time.Time `json:"last_run_at"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Tag 标签（受控词表）
+type Tag struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Synonyms  string    `json:"synonyms"` // 同义词，逗号分隔
+	Status    string    `json:"status"`   // approved, pending
+	NewsCount int       `json:"news_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Entity 实体（组织、人物、产品、地点）
+type Entity struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Type      string       `json:"type"` // organization, person, product, location
+	Aliases   []string     `json:"aliases,omitempty"`
+	Mentions  int          `json:"mentions"`
+	Timeline  []DailyCount `json:"timeline,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// EntityMention AI 从新闻中提取的实体
+type EntityMention struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Story 故事：同一事件的多源报道聚类
+type Story struct {
 	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	CronExpr    string    `json:"cron_expr"`    // cron表达式
+	Title       string    `json:"title"`
+	Summary     string    `json:"summary"` // AI 生成的多来源综合摘要
+	NewsCount   int       `json:"news_count"`
+	Sources     []string  `json:"sources"`
+	News        []News    `json:"news,omitempty"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+// DailyCount 按天统计的数量
+type DailyCount struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// Briefing AI 为一批推送新闻撰写的综述
+type Briefing struct {
+	Takeaways []string `json:"takeaways"` // 最重要的 3 个要点
+	Themes    []string `json:"themes"`    // 主题
+	Watch     []string `json:"watch"`     // 值得关注的后续动向
+}
+
+// Conversation 问答会话
+type Conversation struct {
+	ID        string                `json:"id"`
+	Title     string                `json:"title"` // 首个问题
+	Messages  []ConversationMessage `json:"messages,omitempty"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+// ConversationMessage 会话中的一条消息
+type ConversationMessage struct {
+	ID        string     `json:"id"`
+	Role      string     `json:"role"` // user, assistant
+	Content   string     `json:"content"`
+	Citations []Citation `json:"citations,omitempty"` // 回答引用的新闻
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Citation 回答中引用的新闻
+type Citation struct {
+	Index  int    `json:"index"` // 回答中的引用编号 [n]
+	NewsID string `json:"news_id"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+}
+
+// Prompt AI 提示词（Go text/template 模板）
+type Prompt struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Content     string          `json:"content"`
+	Version     int             `json:"version"`
+	Builtin     bool            `json:"builtin"` // 与内置默认一致，随版本升级自动更新
+	Custom      bool            `json:"custom"`  // 用户新建的提示词
+	Versions    []PromptVersion `json:"versions,omitempty"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// PromptVersion 提示词历史版本
+type PromptVersion struct {
+	Version   int       `json:"version"`
+	Content   string    `json:"content"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ModelPricing 模型单价（美元 / 百万 tokens）和上下文限制
+type ModelPricing struct {
+	Model           string  `json:"model"`
+	PromptPrice     float64 `json:"prompt_price"`
+	CompletionPrice float64 `json:"completion_price"`
+	ContextTokens   int     `json:"context_tokens"`    // 上下文窗口，0 表示使用默认值
+	MaxOutputTokens int     `json:"max_output_tokens"` // 单次输出上限，0 表示使用默认值
+}
+
+// UsageStat AI 调用用量统计
+type UsageStat struct {
+	Key              string  `json:"key"` // 日期、操作或模型
+	Calls            int     `json:"calls"`
+	Errors           int     `json:"errors"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Cost             float64 `json:"cost"` // 美元
+	AvgLatencyMs     float64 `json:"avg_latency_ms"`
+}
+
+// UsageSummary AI 用量汇总
+type UsageSummary struct {
+	Days        int         `json:"days"`
+	Today       UsageStat   `json:"today"`
+	Total       UsageStat   `json:"total"`
+	Daily       []UsageStat `json:"daily"`
+	ByOperation []UsageStat `json:"by_operation"`
+	ByModel     []UsageStat `json:"by_model"`
+}
+
+// Budget AI 预算（0 表示不限制）
+type Budget struct {
+	Unit          string  `json:"unit"` // tokens 或 cost（美元）
+	Daily         float64 `json:"daily"`
+	Monthly       float64 `json:"monthly"`
+	FallbackModel string  `json:"fallback_model"` // 预算用尽后使用的廉价模型，为空则不再调用 AI
+}
+
+// BudgetStatus 预算使用情况
+type BudgetStatus struct {
+	Budget
+	DailySpent   float64 `json:"daily_spent"`
+	MonthlySpent float64 `json:"monthly_spent"`
+	Exceeded     bool    `json:"exceeded"`
+}
+
+// CacheSettings 翻译缓存设置（0 表示不限制）
+type CacheSettings struct {
+	Enabled    bool `json:"enabled"`
+	TTLDays    int  `json:"ttl_days"`
+	MaxEntries int  `json:"max_entries"`
+}
+
+// PreferenceSettings 偏好模型设置
+type PreferenceSettings struct {
+	AutoFilter  bool    `json:"auto_filter"`  // 新采集的新闻评分低于阈值时不进入阅读窗口
+	Threshold   float64 `json:"threshold"`    // 自动过滤阈值（0-1）
+	MinFeedback int     `json:"min_feedback"` // 训练所需的最少反馈数
+}
+
+// PreferenceStatus 偏好模型状态
+type PreferenceStatus struct {
+	PreferenceSettings
+	Trained   bool                `json:"trained"`
+	TrainedAt time.Time           `json:"trained_at"`
+	Positive  int                 `json:"positive"` // 正向反馈数（赞）
+	Negative  int                 `json:"negative"` // 负向反馈数（踩、不感兴趣）
+	Liked     []PreferenceFeature `json:"liked"`    // 最能提高评分的特征
+	Disliked  []PreferenceFeature `json:"disliked"` // 最能降低评分的特征
+}
+
+// PreferenceFeature 偏好模型的特征（标题词、来源、分类或标签）及其权重（对数几率，正数表示偏好）
+type PreferenceFeature struct {
+	Feature string  `json:"feature"`
+	Weight  float64 `json:"weight"`
+}
+
+// CacheStats 翻译缓存统计
+type CacheStats struct {
+	Settings CacheSettings  `json:"settings"`
+	Entries  int            `json:"entries"`
+	Hits     int            `json:"hits"`
+	ByKind   map[string]int `json:"by_kind"`
+}
+
+// GlossaryTerm 术语表条目：原文术语 → 指定译法，或保留原文不翻译
+type GlossaryTerm struct {
+	ID           string    `json:"id"`
+	Lang         string    `json:"lang"` // 目标语言，为空表示所有语言
+	Term         string    `json:"term"`
+	Translation  string    `json:"translation"`
+	KeepOriginal bool      `json:"keep_original"`
+	Note         string    `json:"note"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// GlossaryViolation 译文未使用术语表指定译法的记录
+type GlossaryViolation struct {
+	ID        string    `json:"id"`
+	NewsID    string    `json:"news_id"`
+	Lang      string    `json:"lang"`
+	Operation string    `json:"operation"`
+	Term      string    `json:"term"`
+	Expected  string    `json:"expected"`
+	Output    string    `json:"output"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SummaryStyle 摘要样式：推送任务可选择模板渲染哪种样式的摘要
+type SummaryStyle struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`        // 唯一标识，如 tldr、bullets
+	Label       string    `json:"label"`       // 显示名称
+	Format      string    `json:"format"`      // paragraph / bullets / one_line
+	MaxChars    int       `json:"max_chars"`   // 字数上限（要点格式为每条的上限）
+	Points      int       `json:"points"`      // 要点数（仅 bullets）
+	Tone        string    `json:"tone"`        // 语气
+	Instruction string    `json:"instruction"` // 附加要求
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewsSummary 新闻按某一样式生成的摘要
+type NewsSummary struct {
+	NewsID    string    `json:"news_id"`
+	Style     string    `json:"style"`
+	Lang      string    `json:"lang"`
+	Summary   string    `json:"summary"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EnrichmentField 自定义提取字段：批量翻译时由 AI 按说明从新闻中提取
+type EnrichmentField struct {
+	Name        string `json:"name"`        // 字段名（字母开头，只含字母、数字和下划线）
+	Type        string `json:"type"`        // string / number / boolean / list
+	Instruction string `json:"instruction"` // 提取说明
+}
+
+// SentimentGroup 一组新闻（来源、实体或全部）的情感倾向统计
+type SentimentGroup struct {
+	Key      string  `json:"key"` // 来源或实体名称，整体统计时为空
+	Count    int     `json:"count"`
+	Positive int     `json:"positive"`
+	Negative int     `json:"negative"`
+	Neutral  int     `json:"neutral"`
+	AvgScore float64 `json:"avg_score"` // 平均情感评分（-1 到 1）
+}
+
+// SentimentStats 最近一段时间的情感倾向统计
+type SentimentStats struct {
+	Days     int              `json:"days"`
+	Overall  SentimentGroup   `json:"overall"`
+	BySource []SentimentGroup `json:"by_source"`
+	ByEntity []SentimentGroup `json:"by_entity"` // 提到最多的实体
+}
+
+// Trend 最近窗口内出现次数显著高于基线的词、实体或标签
+type Trend struct {
+	Kind       string    `json:"kind"` // term / entity / tag
+	Term       string    `json:"term"`
+	Count      int       `json:"count"`    // 最近窗口内提到的新闻数
+	Baseline   float64   `json:"baseline"` // 基线期内平均每个窗口提到的新闻数
+	Score      float64   `json:"score"`    // 显著性（泊松近似的 z 分数）
+	NewsIDs    []string  `json:"news_ids"` // 最近窗口内的相关新闻（最多 10 条）
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+// TrendWatch 关注词推送规则：关注的词、实体或标签出现突增时向渠道推送提醒
+type TrendWatch struct {
+	ID          string    `json:"id"`
+	Term        string    `json:"term"`
+	Kind        string    `json:"kind"` // term / entity / tag，为空时匹配任意类型
 	ChannelID   string    `json:"channel_id"`
-	TemplateID  string    `json:"template_id"`
-	Categories  string    `json:"categories"`   // 推送的分类，逗号分隔
+	MinScore    float64   `json:"min_score"` // 为 0 时使用默认的显著性阈值
 	Enabled     bool      `json:"enabled"`
-	LastRunAt   time.Time `json:"last_run_at"`
+	LastFiredAt time.Time `json:"last_fired_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Job 后台任务（持久化在 SQLite，重启后继续执行）
+type Job struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	Payload     string    `json:"payload"` // JSON
+	Status      string    `json:"status"`  // pending / running / done / failed / cancelled
+	Attempts    int       `json:"attempts"`
+	MaxAttempts int       `json:"max_attempts"`
+	NextRunAt   time.Time `json:"next_run_at"`
+	LastError   string    `json:"last_error"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+}
+
+// Alert 管理员告警
+type Alert struct {
+	ID           string    `json:"id"`
+	Level        string    `json:"level"` // info / warning / error
+	Message      string    `json:"message"`
+	Acknowledged bool      `json:"acknowledged"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 // Settings 系统设置
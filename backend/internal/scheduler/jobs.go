@@ -0,0 +1,164 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/services/jobs"
+	"news-intel-app/internal/services/preference"
+	"news-intel-app/internal/services/pusher"
+	"news-intel-app/internal/services/trends"
+)
+
+// registerJobs 注册后台任务的处理函数
+func (s *Scheduler) registerJobs() {
+	s.jobs.Register(jobs.TypeCollect, s.runCollectJob)
+	s.jobs.Register(jobs.TypeTranslate, s.runTranslateJob)
+	s.jobs.Register(jobs.TypeProcess, s.runProcessJob)
+	s.jobs.Register(jobs.TypeTrends, s.runTrendsJob)
+	s.jobs.Register(jobs.TypeStories, s.runStoriesJob)
+}
+
+// runCollectJob 采集新闻，新采集的新闻生成一个翻译任务
+func (s *Scheduler) runCollectJob(payload json.RawMessage) error {
+	var p jobs.CollectPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return err
+	}
+
+	newNews, err := s.collector.CollectAll()
+	if err != nil {
+		return err
+	}
+	if len(newNews) == 0 {
+		log.Println("No new news to translate")
+		return nil
+	}
+
+	ids := make([]string, len(newNews))
+	for i, n := range newNews {
+		ids[i] = n.ID
+	}
+	_, err = s.jobs.Enqueue(jobs.TypeTranslate, jobs.TranslatePayload{NewsIDs: ids, AutoPush: p.AutoPush})
+	return err
+}
+
+// runTranslateJob 批量翻译仍未进入阅读窗口的新闻；有新闻未能处理时返回错误，稍后重试这些新闻
+func (s *Scheduler) runTranslateJob(payload json.RawMessage) error {
+	var p jobs.TranslatePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return err
+	}
+	if len(p.NewsIDs) == 0 {
+		return nil
+	}
+
+	where, args := pendingNewsClause(p.NewsIDs)
+	list, err := pusher.QueryNews(where, args...)
+	if err != nil {
+		return err
+	}
+	// 偏好模型评分，开启自动过滤时低分新闻不再翻译
+	if kept := preference.Screen(list); len(kept) < len(list) {
+		log.Printf("Preference model filtered %d of %d news", len(list)-len(kept), len(list))
+		list = kept
+	}
+	if len(list) > 0 {
+		log.Printf("Translating %d new news...", len(list))
+		if err := s.ai.ProcessAndMoveToReading(list); err != nil {
+			return err
+		}
+		// 翻译后新闻有了标签，按完整特征重新评分
+		ids := make([]string, len(list))
+		for i, n := range list {
+			ids[i] = n.ID
+		}
+		idWhere, idArgs := idsClause(ids)
+		if _, err := preference.Rescore(idWhere, idArgs...); err != nil {
+			log.Printf("Preference rescore error: %v", err)
+		}
+	}
+
+	// 翻译完成后聚类故事，推送时可按故事合并
+	if err := s.stories.Run(); err != nil {
+		log.Printf("Story clustering error: %v", err)
+	}
+
+	var remaining int
+	if err := database.DB.QueryRow("SELECT COUNT(*) FROM news WHERE "+where, args...).Scan(&remaining); err != nil {
+		return err
+	}
+	if remaining > 0 {
+		return fmt.Errorf("%d of %d news not processed", remaining, len(p.NewsIDs))
+	}
+
+	// 全部处理完成后检查自动推送
+	if p.AutoPush {
+		if err := s.pusher.CheckAndAutoPush(); err != nil {
+			log.Printf("Auto push check error: %v", err)
+		}
+	}
+	return nil
+}
+
+// runProcessJob 逐条处理未翻译的新闻
+func (s *Scheduler) runProcessJob(payload json.RawMessage) error {
+	var p jobs.ProcessPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return err
+	}
+	if p.Limit <= 0 {
+		p.Limit = 10
+	}
+	return s.ai.ProcessUnprocessedNews(p.Limit)
+}
+
+// runStoriesJob 重新聚类最近的新闻
+func (s *Scheduler) runStoriesJob(payload json.RawMessage) error {
+	return s.stories.Run()
+}
+
+// runTrendsJob 分析热点趋势，关注词出现突增时推送提醒
+func (s *Scheduler) runTrendsJob(payload json.RawMessage) error {
+	list, err := trends.Analyze()
+	if err != nil {
+		return err
+	}
+	if err := trends.Save(list); err != nil {
+		return err
+	}
+
+	hits, err := trends.CheckWatches(list)
+	if err != nil {
+		return err
+	}
+	// 推送失败时不记录触发时间，下次分析仍会重试
+	for _, hit := range hits {
+		if err := s.pusher.PushTrendAlert(hit.Watch.ChannelID, hit.Trend); err != nil {
+			log.Printf("Trend alert for %q error: %v", hit.Watch.Term, err)
+			continue
+		}
+		if err := trends.MarkFired(hit.Watch.ID); err != nil {
+			log.Printf("Failed to mark trend watch %q fired: %v", hit.Watch.Term, err)
+		}
+	}
+	return nil
+}
+
+// pendingNewsClause 指定新闻中仍未进入阅读窗口（且未被过滤）的筛选条件
+func pendingNewsClause(ids []string) (string, []interface{}) {
+	where, args := idsClause(ids)
+	return "in_reading = 0 AND is_filtered = 0 AND " + where, args
+}
+
+// idsClause 按新闻 ID 筛选的条件
+func idsClause(ids []string) (string, []interface{}) {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return "id IN (?" + strings.Repeat(",?", len(ids)-1) + ")", args
+}
@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"news-intel-app/internal/config"
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/services/ai"
+	"news-intel-app/internal/services/collector"
+	"news-intel-app/internal/services/jobs"
+	"news-intel-app/internal/services/prompts"
+	"news-intel-app/internal/services/pusher"
+	"news-intel-app/internal/services/search"
+	"news-intel-app/internal/services/stories"
+	"news-intel-app/internal/services/summaries"
+	"news-intel-app/internal/services/usage"
+)
+
+const feed = `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Test</title>
+<item><title>Apple beats estimates</title><link>https://example.com/a</link><description>Apple reported record revenue.</description><pubDate>%[1]s</pubDate></item>
+<item><title>Fed holds rates</title><link>https://example.com/b</link><description>The Federal Reserve kept rates unchanged.</description><pubDate>%[1]s</pubDate></item>
+</channel></rss>`
+
+// 采集 → 翻译 → 自动推送全流程，AI 使用模拟服务商
+func TestCollectTranslatePushWithMock(t *testing.T) {
+	t.Setenv("AI_PROVIDER", ai.ProviderMock)
+	cfg := config.Load()
+
+	if err := database.Init("file:pipeline?mode=memory&cache=shared"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.DB.Close() })
+	for _, init := range []func() error{prompts.Init, usage.InitDefaultPricing, summaries.InitDefaults} {
+		if err := init(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rss := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprintf(w, feed, time.Now().Format(time.RFC1123Z))
+	}))
+	defer rss.Close()
+
+	var mu sync.Mutex
+	var pushed []string
+	ntfy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		pushed = append(pushed, string(body))
+		mu.Unlock()
+	}))
+	defer ntfy.Close()
+
+	channel, _ := json.Marshal(map[string]string{"server_url": ntfy.URL, "topic": "news"})
+	for _, q := range []struct {
+		sql  string
+		args []interface{}
+	}{
+		{"INSERT INTO news_sources (id, name, type, url, category, enabled) VALUES ('s1', 'Test', 'rss', ?, 'tech', 1)", []interface{}{rss.URL}},
+		{"INSERT INTO push_channels (id, name, type, config) VALUES ('c1', 'ntfy', 'ntfy', ?)", []interface{}{string(channel)}},
+		{"INSERT OR REPLACE INTO settings (key, value) VALUES ('auto_push_enabled', '1'), ('auto_push_threshold', '2'), ('auto_push_channel_id', 'c1')", nil},
+	} {
+		if _, err := database.DB.Exec(q.sql, q.args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	aiSvc := ai.New(cfg.AIProvider, cfg.OpenAIKey, cfg.OpenAIBase, cfg.OpenAIModel)
+	s := New(collector.New(), aiSvc, pusher.New(aiSvc), stories.New(aiSvc), search.New(aiSvc), jobs.New())
+
+	if err := s.runCollectJob(json.RawMessage(`{"auto_push":true}`)); err != nil {
+		t.Fatal(err)
+	}
+	var payload string
+	if err := database.DB.QueryRow("SELECT payload FROM jobs WHERE type = ?", jobs.TypeTranslate).Scan(&payload); err != nil {
+		t.Fatalf("translate job not enqueued: %v", err)
+	}
+	if err := s.runTranslateJob(json.RawMessage(payload)); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := database.DB.Query("SELECT title, COALESCE(trans_title, ''), translated, in_reading, pushed FROM news")
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for rows.Next() {
+		var title, transTitle string
+		var translated, inReading, isPushed bool
+		rows.Scan(&title, &transTitle, &translated, &inReading, &isPushed)
+		count++
+		if transTitle != "[mock] "+title || !translated || !inReading || !isPushed {
+			t.Errorf("news %q: trans_title=%q translated=%v in_reading=%v pushed=%v", title, transTitle, translated, inReading, isPushed)
+		}
+	}
+	rows.Close()
+	if count != 2 {
+		t.Fatalf("collected %d news, want 2", count)
+	}
+
+	var translations int
+	database.DB.QueryRow("SELECT COUNT(*) FROM news_translations").Scan(&translations)
+	if translations != count {
+		t.Errorf("got %d translation rows, want %d", translations, count)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(pushed) != 1 || !strings.Contains(pushed[0], "[mock] Apple beats estimates") {
+		t.Errorf("ntfy pushes = %q", pushed)
+	}
+}
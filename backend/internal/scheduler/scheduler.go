@@ -2,12 +2,18 @@ package scheduler
 
 import (
 	"log"
+	"time"
 
 	"news-intel-app/internal/database"
 	"news-intel-app/internal/models"
 	"news-intel-app/internal/services/ai"
 	"news-intel-app/internal/services/collector"
+	"news-intel-app/internal/services/jobs"
+	"news-intel-app/internal/services/preference"
 	"news-intel-app/internal/services/pusher"
+	"news-intel-app/internal/services/search"
+	"news-intel-app/internal/services/stories"
+	"news-intel-app/internal/services/transcache"
 
 	"github.com/robfig/cron/v3"
 )
@@ -17,14 +23,20 @@ type Scheduler struct {
 	collector *collector.Collector
 	ai        *ai.AIService
 	pusher    *pusher.Pusher
+	stories   *stories.Clusterer
+	search    *search.Searcher
+	jobs      *jobs.Queue
 }
 
-func New(col *collector.Collector, aiSvc *ai.AIService, push *pusher.Pusher) *Scheduler {
+func New(col *collector.Collector, aiSvc *ai.AIService, push *pusher.Pusher, storyClusterer *stories.Clusterer, searcher *search.Searcher, queue *jobs.Queue) *Scheduler {
 	return &Scheduler{
 		cron:      cron.New(),
 		collector: col,
 		ai:        aiSvc,
 		pusher:    push,
+		stories:   storyClusterer,
+		search:    searcher,
+		jobs:      queue,
 	}
 }
 
@@ -34,40 +46,102 @@ func (s *Scheduler) Start() {
 		s.CollectAndTranslate()
 	})
 
+	// 每15分钟为缺少向量的新闻补算向量（语义搜索）
+	s.cron.AddFunc("*/15 * * * *", func() {
+		s.BackfillEmbeddings()
+	})
+
+	// 每小时清理过期和超出上限的翻译缓存
+	s.cron.AddFunc("0 * * * *", func() {
+		s.PruneTranslationCache()
+	})
+
+	// 每小时根据读者反馈重新训练偏好模型
+	s.cron.AddFunc("20 * * * *", func() {
+		s.TrainPreference()
+	})
+
+	// 每30分钟分析一次热点趋势（错开采集时间）
+	s.cron.AddFunc("10,40 * * * *", func() {
+		s.AnalyzeTrends()
+	})
+
+	// 每天清理一周前已完成的后台任务
+	s.cron.AddFunc("30 3 * * *", func() {
+		s.PruneJobs()
+	})
+
 	// 加载推送任务
 	s.loadPushTasks()
 
+	// 偏好模型只保存在内存中，启动时先训练，之后的翻译任务才能按评分过滤
+	s.TrainPreference()
+
+	// 启动后台任务队列（继续执行上次未完成的任务）
+	s.registerJobs()
+	s.jobs.Start(jobs.DefaultWorkers)
+
 	s.cron.Start()
 	log.Println("Scheduler started")
 }
 
-// CollectAndTranslate 采集新闻并立即翻译
+// CollectAndTranslate 排队采集任务；采集后生成翻译任务，翻译完成后检查自动推送
 func (s *Scheduler) CollectAndTranslate() {
 	log.Println("Scheduled: Collecting news...")
-	newNews, err := s.collector.CollectAll()
+	if _, err := s.jobs.EnqueueOnce(jobs.TypeCollect, jobs.CollectPayload{AutoPush: true}); err != nil {
+		log.Printf("Failed to enqueue collect job: %v", err)
+	}
+}
+
+// AnalyzeTrends 排队趋势分析任务
+func (s *Scheduler) AnalyzeTrends() {
+	if _, err := s.jobs.EnqueueOnce(jobs.TypeTrends, nil); err != nil {
+		log.Printf("Failed to enqueue trends job: %v", err)
+	}
+}
+
+// PruneJobs 清理一周前已完成或已取消的后台任务
+func (s *Scheduler) PruneJobs() {
+	n, err := jobs.Prune(7 * 24 * time.Hour)
 	if err != nil {
-		log.Printf("Scheduled collect error: %v", err)
-		return
+		log.Printf("Job prune error: %v", err)
+	}
+	if n > 0 {
+		log.Printf("Jobs: %d finished jobs pruned", n)
 	}
+}
 
-	if len(newNews) == 0 {
-		log.Println("No new news to translate")
-		return
+// TrainPreference 重新训练偏好模型并为最近的新闻重新评分
+func (s *Scheduler) TrainPreference() {
+	if err := preference.Train(); err != nil {
+		log.Printf("Preference training error: %v", err)
 	}
+}
 
-	log.Printf("Translating %d new news...", len(newNews))
-	if err := s.ai.ProcessAndMoveToReading(newNews); err != nil {
-		log.Printf("Scheduled AI process error: %v", err)
+// BackfillEmbeddings 补算缺失的新闻向量
+func (s *Scheduler) BackfillEmbeddings() {
+	n, err := s.search.Backfill(200)
+	if err != nil {
+		log.Printf("Embedding backfill error: %v", err)
+	}
+	if n > 0 {
+		log.Printf("Embedding backfill: %d news embedded", n)
 	}
+}
 
-	// 翻译完成后检查自动推送
-	if err := s.pusher.CheckAndAutoPush(); err != nil {
-		log.Printf("Auto push check error: %v", err)
+// PruneTranslationCache 清理翻译缓存
+func (s *Scheduler) PruneTranslationCache() {
+	n, err := transcache.Prune()
+	if err != nil {
+		log.Printf("Translation cache prune error: %v", err)
+	}
+	if n > 0 {
+		log.Printf("Translation cache: %d entries pruned", n)
 	}
 }
 
 func (s *Scheduler) loadPushTasks() {
-	rows, err := database.DB.Query("SELECT id, name, cron_expr, channel_id, template_id, categories, enabled FROM push_tasks WHERE enabled = 1")
+	rows, err := database.DB.Query("SELECT id, name, cron_expr, channel_id, template_id, categories, COALESCE(tags, ''), briefing, COALESCE(summary_style, ''), enabled FROM push_tasks WHERE enabled = 1")
 	if err != nil {
 		log.Printf("Failed to load push tasks: %v", err)
 		return
@@ -76,7 +150,7 @@ func (s *Scheduler) loadPushTasks() {
 
 	for rows.Next() {
 		var t models.PushTask
-		if err := rows.Scan(&t.ID, &t.Name, &t.CronExpr, &t.ChannelID, &t.TemplateID, &t.Categories, &t.Enabled); err != nil {
+		if err := rows.Scan(&t.ID, &t.Name, &t.CronExpr, &t.ChannelID, &t.TemplateID, &t.Categories, &t.Tags, &t.Briefing, &t.SummaryStyle, &t.Enabled); err != nil {
 			continue
 		}
 
@@ -95,4 +169,5 @@ func (s *Scheduler) loadPushTasks() {
 
 func (s *Scheduler) Stop() {
 	s.cron.Stop()
+	s.jobs.Stop()
 }
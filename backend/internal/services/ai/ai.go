@@ -3,156 +3,528 @@ package ai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"news-intel-app/internal/database"
 	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/enrichment"
+	"news-intel-app/internal/services/entities"
+	"news-intel-app/internal/services/glossary"
+	"news-intel-app/internal/services/preference"
+	"news-intel-app/internal/services/prompts"
+	"news-intel-app/internal/services/taxonomy"
+	"news-intel-app/internal/services/transcache"
+	"news-intel-app/internal/services/translations"
+	"news-intel-app/internal/services/usage"
+	"news-intel-app/internal/textutil"
 
 	openai "github.com/sashabaranov/go-openai"
 )
 
+// DefaultEmbeddingModel 未配置向量模型时使用的默认模型
+const DefaultEmbeddingModel = "text-embedding-3-small"
+
 type AIService struct {
-	client *openai.Client
-	config *models.AIConfig
+	mu        sync.RWMutex // 保护 client 和 config：LoadConfig 可能在批量处理进行中替换它们
+	client    *openai.Client
+	config    *models.AIConfig
+	transport *limitedTransport // 所有请求共用的限流与重试中间件
+
+	schemaUnsupported atomic.Bool // 服务商不支持 json_schema 结构化输出
 }
 
-func New(apiKey, baseURL, model string) *AIService {
+func New(provider, apiKey, baseURL, model string) *AIService {
+	s := &AIService{
+		transport: newLimitedTransport(),
+		config: &models.AIConfig{
+			Provider:         provider,
+			Model:            model,
+			TargetLang:       "zh-CN",
+			EnableTags:       true,
+			EnableEntities:   true,
+			TimeoutSeconds:   DefaultTimeoutSeconds,
+			MaxRetries:       DefaultMaxRetries,
+			BatchConcurrency: DefaultBatchConcurrency,
+		},
+	}
+	s.client = s.newClient(apiKey, baseURL)
+	return s
+}
+
+// newClient 创建经过限流与重试中间件的客户端
+func (s *AIService) newClient(apiKey, baseURL string) *openai.Client {
 	config := openai.DefaultConfig(apiKey)
 	if baseURL != "" {
 		config.BaseURL = baseURL
 	}
+	config.HTTPClient = &http.Client{Transport: s.transport}
+	return openai.NewClientWithConfig(config)
+}
 
-	return &AIService{
-		client: openai.NewClientWithConfig(config),
-		config: &models.AIConfig{
-			Model:      model,
-			TargetLang: "zh-CN",
-		},
-	}
+// callContext 单次调用的上下文；超时由 limitedTransport 按每次尝试计算，这里只用于取消
+func (s *AIService) callContext() (context.Context, context.CancelFunc) {
+	return context.WithCancel(context.Background())
 }
 
 // LoadConfig 从数据库加载AI配置
 func (s *AIService) LoadConfig() error {
-	row := database.DB.QueryRow("SELECT provider, api_key, base_url, model, enable_trans, enable_summary, enable_filter, target_lang FROM ai_configs LIMIT 1")
-	
+	row := database.DB.QueryRow("SELECT provider, api_key, base_url, model, enable_trans, enable_summary, enable_filter, target_lang, COALESCE(target_langs, ''), COALESCE(full_trans_categories, ''), enable_tags, allow_new_tags, enable_entities, enable_sentiment, COALESCE(embedding_model, ''), rate_limit_rpm, rate_limit_tpm, timeout_seconds, max_retries, batch_concurrency FROM ai_configs LIMIT 1")
+
 	var cfg models.AIConfig
-	err := row.Scan(&cfg.Provider, &cfg.APIKey, &cfg.BaseURL, &cfg.Model, &cfg.EnableTrans, &cfg.EnableSummary, &cfg.EnableFilter, &cfg.TargetLang)
+	var targetLangs, fullTransCategories string
+	err := row.Scan(&cfg.Provider, &cfg.APIKey, &cfg.BaseURL, &cfg.Model, &cfg.EnableTrans, &cfg.EnableSummary, &cfg.EnableFilter, &cfg.TargetLang, &targetLangs, &fullTransCategories, &cfg.EnableTags, &cfg.AllowNewTags, &cfg.EnableEntities, &cfg.EnableSentiment, &cfg.EmbeddingModel, &cfg.RateLimitRPM, &cfg.RateLimitTPM, &cfg.TimeoutSeconds, &cfg.MaxRetries, &cfg.BatchConcurrency)
 	if err != nil {
 		return err
 	}
+	cfg.TargetLangs = textutil.SplitList(targetLangs)
+	if len(cfg.TargetLangs) > 0 {
+		cfg.TargetLang = cfg.TargetLangs[0]
+	}
+	cfg.FullTransCategories = textutil.SplitList(fullTransCategories)
 
-	s.config = &cfg
-	
 	// 重新初始化client
-	config := openai.DefaultConfig(cfg.APIKey)
-	if cfg.BaseURL != "" {
-		config.BaseURL = cfg.BaseURL
-	}
-	s.client = openai.NewClientWithConfig(config)
-	
+	s.transport.configure(cfg.RateLimitRPM, cfg.RateLimitTPM, cfg.MaxRetries, cfg.TimeoutSeconds)
+	s.schemaUnsupported.Store(false)
+	client := s.newClient(cfg.APIKey, cfg.BaseURL)
+
+	s.mu.Lock()
+	s.config = &cfg
+	s.client = client
+	s.mu.Unlock()
 	return nil
 }
 
+// cfg 当前配置。LoadConfig 整体替换配置而不修改旧值，返回的配置可以在并发中安全读取
+func (s *AIService) cfg() *models.AIConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// api 当前配置对应的客户端
+func (s *AIService) api() *openai.Client {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.client
+}
+
+// targetLangs 全部目标语言，第一个为主语言（写入 trans_title / trans_summary）
+func (s *AIService) targetLangs() []string {
+	cfg := s.cfg()
+	if len(cfg.TargetLangs) > 0 {
+		return cfg.TargetLangs
+	}
+	return []string{cfg.TargetLang}
+}
+
+// TargetLang 当前主语言
+func (s *AIService) TargetLang() string {
+	return s.cfg().TargetLang
+}
+
+// EmbeddingModel 当前使用的向量模型
+func (s *AIService) EmbeddingModel() string {
+	if model := s.cfg().EmbeddingModel; model != "" {
+		return model
+	}
+	return DefaultEmbeddingModel
+}
+
+// Embed 调用 embeddings 接口计算文本向量，返回顺序与输入一致
+func (s *AIService) Embed(texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	if err := usage.Allow(s.EmbeddingModel()); err != nil {
+		return nil, err
+	}
+	if s.IsMock() {
+		return s.mockEmbed(texts), nil
+	}
+
+	ctx, cancel := s.callContext()
+	defer cancel()
+	start := time.Now()
+	resp, err := s.api().CreateEmbeddings(
+		ctx,
+		openai.EmbeddingRequest{
+			Model: openai.EmbeddingModel(s.EmbeddingModel()),
+			Input: texts,
+		},
+	)
+	usage.Record(usage.Entry{
+		Operation:    OpEmbed,
+		Model:        s.EmbeddingModel(),
+		PromptTokens: resp.Usage.PromptTokens,
+		Latency:      time.Since(start),
+		Err:          err,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(resp.Data))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, d := range resp.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("embedding index out of range: %d", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	return vectors, nil
+}
+
+// ChatStream 以流式方式调用对话接口，每收到一段内容回调 onDelta，返回完整回复。
+// onDelta 返回错误（如客户端断开）时中止请求
+func (s *AIService) ChatStream(ctx context.Context, messages []openai.ChatCompletionMessage, onDelta func(string) error) (string, error) {
+	return s.chatStream(ctx, OpAsk, nil, openai.ChatCompletionRequest{
+		Model:       s.cfg().Model,
+		Messages:    messages,
+		Temperature: 0.3,
+	}, onDelta)
+}
+
+// chatStream 流式调用对话接口并记录用量；ctx 取消或 onDelta 返回错误时中止请求
+func (s *AIService) chatStream(ctx context.Context, op string, newsIDs []string, req openai.ChatCompletionRequest, onDelta func(string) error) (string, error) {
+	if req.Model == "" {
+		req.Model = s.cfg().Model
+	}
+	if err := usage.Allow(req.Model); err != nil {
+		return "", err
+	}
+	if s.IsMock() {
+		return s.mockStream(op, newsIDs, req, onDelta)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	start := time.Now()
+	var tokens openai.Usage
+	var streamErr error
+	defer func() {
+		usage.Record(usage.Entry{
+			Operation:        op,
+			Model:            req.Model,
+			PromptTokens:     tokens.PromptTokens,
+			CompletionTokens: tokens.CompletionTokens,
+			Latency:          time.Since(start),
+			Err:              streamErr,
+			NewsIDs:          newsIDs,
+		})
+	}()
+
+	req.Stream = true
+	req.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+	stream, streamErr := s.api().CreateChatCompletionStream(ctx, req)
+	if streamErr != nil {
+		return "", streamErr
+	}
+	defer stream.Close()
+
+	var full strings.Builder
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			streamErr = err
+			return full.String(), err
+		}
+		if resp.Usage != nil {
+			tokens = *resp.Usage
+		}
+		if len(resp.Choices) == 0 || resp.Choices[0].Delta.Content == "" {
+			continue
+		}
+		delta := resp.Choices[0].Delta.Content
+		full.WriteString(delta)
+		if onDelta != nil {
+			if err := onDelta(delta); err != nil {
+				streamErr = err
+				return full.String(), err
+			}
+		}
+	}
+	return full.String(), nil
+}
+
+// complete 单轮对话：onDelta 为空时走普通调用，否则流式调用并逐段回调
+func (s *AIService) complete(ctx context.Context, op string, newsIDs []string, req openai.ChatCompletionRequest, onDelta func(string) error) (string, error) {
+	if onDelta != nil {
+		return s.chatStream(ctx, op, newsIDs, req, onDelta)
+	}
+	resp, err := s.chat(op, newsIDs, req)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("no response from AI")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// AI 调用的操作类型（用于用量统计）
+const (
+	OpTranslate        = "translate"
+	OpTranslateContent = "translate_content"
+	OpStyledSummary    = "styled_summary"
+	OpSummarizeSection = "summarize_section"
+	OpSummarize        = "summarize"
+	OpBatchTranslate   = "batch_translate"
+	OpFilter           = "filter"
+	OpSuggestTags      = "suggest_tags"
+	OpExtractEntities  = "extract_entities"
+	OpStorySummary     = "story_summary"
+	OpBriefing         = "briefing"
+	OpEmailTemplate    = "email_template"
+	OpAsk              = "ask"
+	OpPromptTest       = "prompt_test"
+	OpEmbed            = "embed"
+)
+
+// chat 调用对话接口并记录用量；所有非流式对话调用都经过这里，预算用尽时拒绝调用
+func (s *AIService) chat(op string, newsIDs []string, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	if req.Model == "" {
+		req.Model = s.cfg().Model
+	}
+	if err := usage.Allow(req.Model); err != nil {
+		return openai.ChatCompletionResponse{}, err
+	}
+	if s.IsMock() {
+		return s.mockChat(op, newsIDs, req), nil
+	}
+	ctx, cancel := s.callContext()
+	defer cancel()
+	start := time.Now()
+	resp, err := s.api().CreateChatCompletion(ctx, req)
+	usage.Record(usage.Entry{
+		Operation:        op,
+		Model:            req.Model,
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+		Latency:          time.Since(start),
+		Err:              err,
+		NewsIDs:          newsIDs,
+	})
+	return resp, err
+}
+
+// newsIDs 新闻列表的 ID
+func newsIDs(newsList []models.News) []string {
+	ids := make([]string, len(newsList))
+	for i, n := range newsList {
+		ids[i] = n.ID
+	}
+	return ids
+}
+
 // Translate 翻译文本
 func (s *AIService) Translate(text, targetLang string) (string, error) {
+	return s.translate(context.Background(), text, targetLang, nil, nil)
+}
+
+// TranslateStream 流式翻译文本，每收到一段译文回调 onDelta（命中缓存时一次性回调完整译文）
+func (s *AIService) TranslateStream(ctx context.Context, text, targetLang string, onDelta func(string) error) (string, error) {
+	return s.translate(ctx, text, targetLang, nil, onDelta)
+}
+
+func (s *AIService) translate(ctx context.Context, text, targetLang string, newsIDs []string, onDelta func(string) error) (string, error) {
 	if text == "" {
 		return "", nil
 	}
 
-	var prompt string
-	switch targetLang {
-	case "zh-ug":
-		// 双语翻译：中文 + 维吾尔语
-		prompt = fmt.Sprintf(`将以下文本翻译成中文和维吾尔语两种语言，格式如下：
-【中文】翻译内容
-【ئۇيغۇرچە】翻译内容
-
-只返回翻译结果，不要添加任何其他解释：
+	terms := loadGlossary(targetLang).Match(text)
+	instruction := glossary.Instruction(terms)
+	key := transcache.Key(OpTranslate, s.cfg().Model, targetLang, strconv.Itoa(prompts.Version(prompts.Translate)), instruction, text)
+	if cached, ok := transcache.Get(key); ok {
+		return cached, emitCached(cached, onDelta)
+	}
 
-%s`, text)
-	case "ug":
-		prompt = fmt.Sprintf("将以下文本翻译成维吾尔语(Uyghur)，只返回翻译结果，不要添加任何解释：\n\n%s", text)
-	default:
-		prompt = fmt.Sprintf("将以下文本翻译成%s，只返回翻译结果，不要添加任何解释：\n\n%s", targetLang, text)
+	prompt, err := prompts.Render(prompts.Translate, prompts.Data{Text: text, TargetLang: targetLang, Glossary: instruction})
+	if err != nil {
+		return "", err
 	}
 
-	resp, err := s.client.CreateChatCompletion(
-		context.Background(),
+	output, err := s.complete(
+		ctx, OpTranslate, newsIDs,
 		openai.ChatCompletionRequest{
-			Model: s.config.Model,
+			Model: s.cfg().Model,
 			Messages: []openai.ChatCompletionMessage{
 				{Role: openai.ChatMessageRoleUser, Content: prompt},
 			},
 			Temperature: 0.3,
 		},
+		onDelta,
 	)
 	if err != nil {
 		return "", err
 	}
 
-	if len(resp.Choices) > 0 {
-		return resp.Choices[0].Message.Content, nil
-	}
-	return "", fmt.Errorf("no response from AI")
+	glossary.Flag(strings.Join(newsIDs, ","), targetLang, OpTranslate, output, glossary.Check(terms, text, output))
+	transcache.Put(key, OpTranslate, s.cfg().Model, output)
+	return output, nil
 }
 
 // Summarize 生成摘要（支持多语言）
 func (s *AIService) Summarize(text string, targetLang string) (string, error) {
+	return s.summarize(context.Background(), text, targetLang, nil, nil)
+}
+
+// SummarizeStream 流式生成摘要，每收到一段内容回调 onDelta（命中缓存时一次性回调完整摘要）
+func (s *AIService) SummarizeStream(ctx context.Context, text, targetLang string, onDelta func(string) error) (string, error) {
+	return s.summarize(ctx, text, targetLang, nil, onDelta)
+}
+
+func (s *AIService) summarize(ctx context.Context, text, targetLang string, newsIDs []string, onDelta func(string) error) (string, error) {
 	if text == "" {
 		return "", nil
 	}
 
-	var prompt string
-	switch targetLang {
-	case "zh-ug":
-		// 双语摘要：中文 + 维吾尔语
-		prompt = fmt.Sprintf(`为以下新闻生成双语摘要，格式如下：
-【中文】中文摘要（不超过100字）
-【ئۇيغۇرچە】维吾尔语摘要（不超过100字）
+	// 摘要不一定提到原文中的每个术语，只注入术语表，不做译后检查
+	instruction := glossary.Instruction(loadGlossary(targetLang).Match(text))
+	key := transcache.Key(OpSummarize, s.cfg().Model, targetLang, strconv.Itoa(prompts.Version(prompts.Summarize)), instruction, text)
+	if cached, ok := transcache.Get(key); ok {
+		return cached, emitCached(cached, onDelta)
+	}
 
-只返回摘要内容，不要添加任何其他解释：
+	// 长文先分段概括再合并，避免截断或超出上下文
+	if isLongText(text) {
+		output, err := s.summarizeLong(ctx, "", text, targetLang, instruction, newsIDs, onDelta)
+		if err != nil {
+			return "", err
+		}
+		transcache.Put(key, OpSummarize, s.cfg().Model, output)
+		return output, nil
+	}
 
-%s`, text)
-	case "ug":
-		prompt = fmt.Sprintf("为以下新闻生成一个简洁的维吾尔语(Uyghur)摘要（不超过100字），只返回摘要内容：\n\n%s", text)
-	default:
-		prompt = fmt.Sprintf("为以下新闻生成一个简洁的中文摘要（不超过100字）：\n\n%s", text)
+	prompt, err := prompts.Render(prompts.Summarize, prompts.Data{Text: text, TargetLang: targetLang, Glossary: instruction})
+	if err != nil {
+		return "", err
 	}
 
-	resp, err := s.client.CreateChatCompletion(
-		context.Background(),
+	output, err := s.complete(
+		ctx, OpSummarize, newsIDs,
 		openai.ChatCompletionRequest{
-			Model: s.config.Model,
+			Model: s.cfg().Model,
 			Messages: []openai.ChatCompletionMessage{
 				{Role: openai.ChatMessageRoleUser, Content: prompt},
 			},
 			Temperature: 0.5,
 		},
+		onDelta,
 	)
 	if err != nil {
 		return "", err
 	}
 
-	if len(resp.Choices) > 0 {
-		return resp.Choices[0].Message.Content, nil
+	transcache.Put(key, OpSummarize, s.cfg().Model, output)
+	return output, nil
+}
+
+// emitCached 流式调用命中缓存时，把缓存结果作为一段内容回调
+func emitCached(cached string, onDelta func(string) error) error {
+	if onDelta == nil || cached == "" {
+		return nil
+	}
+	return onDelta(cached)
+}
+
+// SummarizeStory 为同一事件的多篇报道生成综合标题和多来源摘要
+func (s *AIService) SummarizeStory(newsList []models.News) (title, summary string, err error) {
+	if len(newsList) == 0 {
+		return "", "", nil
+	}
+
+	prompt, err := prompts.Render(prompts.StorySummary, prompts.Data{Items: storyItems(newsList), Count: len(newsList), TargetLang: s.cfg().TargetLang})
+	if err != nil {
+		return "", "", err
+	}
+
+	resp, err := s.chat(
+		OpStorySummary, newsIDs(newsList),
+		openai.ChatCompletionRequest{
+			Model: s.cfg().Model,
+			Messages: []openai.ChatCompletionMessage{
+				{Role: openai.ChatMessageRoleUser, Content: prompt},
+			},
+			Temperature: 0.3,
+		},
+	)
+	if err != nil {
+		return "", "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", "", fmt.Errorf("no response from AI")
+	}
+
+	var result struct {
+		Title   string `json:"title"`
+		Summary string `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(cleanJSONResponse(resp.Choices[0].Message.Content)), &result); err != nil {
+		return "", "", fmt.Errorf("failed to parse story summary: %v", err)
+	}
+	return result.Title, result.Summary, nil
+}
+
+// GenerateBriefing 为一批待推送新闻撰写综述：3 个要点、主题和值得关注的动向
+func (s *AIService) GenerateBriefing(newsList []models.News) (*models.Briefing, error) {
+	if len(newsList) == 0 {
+		return nil, nil
+	}
+
+	prompt, err := prompts.Render(prompts.Briefing, prompts.Data{Items: briefingItems(newsList), Count: len(newsList), TargetLang: s.cfg().TargetLang})
+	if err != nil {
+		return nil, err
 	}
-	return "", fmt.Errorf("no response from AI")
+
+	resp, err := s.chat(
+		OpBriefing, newsIDs(newsList),
+		openai.ChatCompletionRequest{
+			Model: s.cfg().Model,
+			Messages: []openai.ChatCompletionMessage{
+				{Role: openai.ChatMessageRoleUser, Content: prompt},
+			},
+			Temperature: 0.4,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from AI")
+	}
+
+	var briefing models.Briefing
+	if err := json.Unmarshal([]byte(cleanJSONResponse(resp.Choices[0].Message.Content)), &briefing); err != nil {
+		return nil, fmt.Errorf("failed to parse briefing: %v", err)
+	}
+	return &briefing, nil
 }
 
 // FilterNews 筛选新闻（判断是否值得推送）
 func (s *AIService) FilterNews(news *models.News) (bool, error) {
-	prompt := fmt.Sprintf(`判断以下新闻是否有价值推送给用户。
-标题: %s
-内容: %s
-
-请返回JSON格式: {"valuable": true/false, "reason": "原因"}`, news.Title, news.Content)
+	prompt, err := prompts.Render(prompts.Filter, prompts.Data{Title: news.Title, Content: news.Content})
+	if err != nil {
+		return true, err
+	}
 
-	resp, err := s.client.CreateChatCompletion(
-		context.Background(),
+	resp, err := s.chat(
+		OpFilter, []string{news.ID},
 		openai.ChatCompletionRequest{
-			Model: s.config.Model,
+			Model: s.cfg().Model,
 			Messages: []openai.ChatCompletionMessage{
 				{Role: openai.ChatMessageRoleUser, Content: prompt},
 			},
@@ -178,9 +550,13 @@ func (s *AIService) FilterNews(news *models.News) (bool, error) {
 
 // ProcessNews 处理单条新闻（翻译+摘要）- 保留用于单条处理
 func (s *AIService) ProcessNews(news *models.News) error {
+	if err := usage.Allow(s.cfg().Model); err != nil {
+		return err
+	}
+
 	// 翻译标题（支持双语：中文+维语）
 	if news.Title != "" {
-		transTitle, err := s.Translate(news.Title, s.config.TargetLang)
+		transTitle, err := s.translate(context.Background(), news.Title, s.cfg().TargetLang, []string{news.ID}, nil)
 		if err != nil {
 			log.Printf("Failed to translate title: %v", err)
 		} else {
@@ -193,135 +569,148 @@ func (s *AIService) ProcessNews(news *models.News) error {
 	if content == "" {
 		content = news.Title
 	}
-	summary, err := s.Summarize(content, s.config.TargetLang)
+	summary, err := s.summarize(context.Background(), content, s.cfg().TargetLang, []string{news.ID}, nil)
 	if err != nil {
 		log.Printf("Failed to summarize: %v", err)
 	} else {
 		news.TransSummary = summary
 	}
 
+	// 打标签
+	if tags, err := s.SuggestTags(news); err != nil {
+		log.Printf("Failed to suggest tags: %v", err)
+	} else {
+		news.Tags = strings.Join(tags, ",")
+	}
+
+	// 提取实体
+	if mentions, err := s.ExtractEntities(news); err != nil {
+		log.Printf("Failed to extract entities: %v", err)
+	} else {
+		news.Entities = mentions
+	}
+
 	news.Translated = true
 	return nil
 }
 
-// BatchTranslateNews 批量翻译新闻（节省 API 调用）
-func (s *AIService) BatchTranslateNews(newsList []models.News) ([]models.News, error) {
-	if len(newsList) == 0 {
-		return newsList, nil
+// loadTaxonomy 加载标签词表；未启用打标签或词表为空且不允许新标签时返回 nil
+func (s *AIService) loadTaxonomy() *taxonomy.Taxonomy {
+	if !s.cfg().EnableTags {
+		return nil
+	}
+	tax, err := taxonomy.Load()
+	if err != nil {
+		log.Printf("Failed to load tag taxonomy: %v", err)
+		return nil
 	}
+	if tax.Empty() && !s.cfg().AllowNewTags {
+		return nil
+	}
+	return tax
+}
 
-	// 构建批量翻译的 prompt
-	var newsItems string
-	for i, news := range newsList {
-		content := news.Content
-		if content == "" {
-			content = news.Title
-		}
-		// 限制内容长度，避免 token 超限
-		if len(content) > 500 {
-			content = content[:500] + "..."
-		}
-		newsItems += fmt.Sprintf("\n[新闻%d]\n标题: %s\n内容: %s\n", i+1, news.Title, content)
-	}
-
-	var prompt string
-	switch s.config.TargetLang {
-	case "zh-ug":
-		prompt = fmt.Sprintf(`请批量翻译以下新闻的标题，并为每条新闻生成摘要。要求双语输出（中文+维吾尔语）。
-
-请严格按照以下 JSON 格式返回，不要添加任何其他内容：
-[
-  {
-    "index": 1,
-    "trans_title": "【中文】中文标题\n【ئۇيغۇرچە】维吾尔语标题",
-    "trans_summary": "【中文】中文摘要（不超过100字）\n【ئۇيغۇرچە】维吾尔语摘要（不超过100字）"
-  }
-]
-
-新闻列表：%s`, newsItems)
-	case "ug":
-		prompt = fmt.Sprintf(`请批量翻译以下新闻的标题为维吾尔语，并为每条新闻生成维吾尔语摘要。
-
-请严格按照以下 JSON 格式返回，不要添加任何其他内容：
-[
-  {
-    "index": 1,
-    "trans_title": "维吾尔语标题",
-    "trans_summary": "维吾尔语摘要（不超过100字）"
-  }
-]
-
-新闻列表：%s`, newsItems)
-	default:
-		prompt = fmt.Sprintf(`请批量翻译以下新闻的标题为中文，并为每条新闻生成中文摘要。
-
-请严格按照以下 JSON 格式返回，不要添加任何其他内容：
-[
-  {
-    "index": 1,
-    "trans_title": "中文标题",
-    "trans_summary": "中文摘要（不超过100字）"
-  }
-]
-
-新闻列表：%s`, newsItems)
-	}
-
-	resp, err := s.client.CreateChatCompletion(
-		context.Background(),
+// loadGlossary 加载目标语言的术语表；加载失败时不使用术语表
+func loadGlossary(lang string) *glossary.Glossary {
+	g, err := glossary.Load(lang)
+	if err != nil {
+		log.Printf("Failed to load glossary for %s: %v", lang, err)
+		return nil
+	}
+	return g
+}
+
+// tagInstruction 生成打标签的 prompt 说明
+func (s *AIService) tagInstruction(tax *taxonomy.Taxonomy) string {
+	var sb strings.Builder
+	if !tax.Empty() {
+		sb.WriteString("从以下标签词表中选择 0-3 个最相关的标签，只能使用词表中的标签名：\n")
+		sb.WriteString(tax.PromptList())
+	}
+	if s.cfg().AllowNewTags {
+		sb.WriteString("如果没有合适的标签，可以提议新的简短标签（会进入待审核状态）。\n")
+	}
+	return sb.String()
+}
+
+// SuggestTags 为单条新闻生成标签
+func (s *AIService) SuggestTags(news *models.News) ([]string, error) {
+	tax := s.loadTaxonomy()
+	if tax == nil {
+		return nil, nil
+	}
+
+	prompt, err := prompts.Render(prompts.SuggestTags, prompts.Data{Extra: s.tagInstruction(tax), Title: news.Title, Content: news.Content})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.chat(
+		OpSuggestTags, []string{news.ID},
 		openai.ChatCompletionRequest{
-			Model: s.config.Model,
+			Model: s.cfg().Model,
 			Messages: []openai.ChatCompletionMessage{
 				{Role: openai.ChatMessageRoleUser, Content: prompt},
 			},
-			Temperature: 0.3,
+			Temperature: 0.2,
 		},
 	)
 	if err != nil {
-		return nil, fmt.Errorf("batch translate API error: %v", err)
+		return nil, err
 	}
-
 	if len(resp.Choices) == 0 {
 		return nil, fmt.Errorf("no response from AI")
 	}
 
-	// 解析 JSON 响应
-	content := resp.Choices[0].Message.Content
-	// 清理可能的 markdown 代码块
-	content = cleanJSONResponse(content)
+	var tags []string
+	if err := json.Unmarshal([]byte(cleanJSONResponse(resp.Choices[0].Message.Content)), &tags); err != nil {
+		return nil, fmt.Errorf("failed to parse tags: %v", err)
+	}
+	return tags, nil
+}
+
+// entityInstruction 实体提取的 prompt 说明
+const entityInstruction = `提取新闻中提到的组织(organization)、人物(person)、产品(product)、地点(location)，` +
+	`格式为 [{"name": "Anthropic", "type": "organization"}]，name 使用原文中最常见的规范写法，没有则返回空数组`
+
+// sentimentInstruction 情感倾向的 prompt 说明
+const sentimentInstruction = `判断报道对其主要对象（公司、产品、人物或事件）的情感倾向，` +
+	`sentiment 为 positive、negative 或 neutral 之一，sentiment_score 为 -1 到 1 之间的数字（-1 最负面，0 中性，1 最正面）；` +
+	`只根据报道内容的基调判断，事实陈述为主时为 neutral`
 
-	var results []struct {
-		Index        int    `json:"index"`
-		TransTitle   string `json:"trans_title"`
-		TransSummary string `json:"trans_summary"`
+// ExtractEntities 提取单条新闻中的实体
+func (s *AIService) ExtractEntities(news *models.News) ([]models.EntityMention, error) {
+	if !s.cfg().EnableEntities {
+		return nil, nil
 	}
 
-	if err := json.Unmarshal([]byte(content), &results); err != nil {
-		log.Printf("Failed to parse batch translate response: %v, content: %s", err, content)
-		return nil, fmt.Errorf("failed to parse response: %v", err)
+	prompt, err := prompts.Render(prompts.ExtractEntities, prompts.Data{Title: news.Title, Content: news.Content})
+	if err != nil {
+		return nil, err
 	}
 
-	// 将翻译结果填充回新闻列表
-	resultMap := make(map[int]struct {
-		TransTitle   string
-		TransSummary string
-	})
-	for _, r := range results {
-		resultMap[r.Index] = struct {
-			TransTitle   string
-			TransSummary string
-		}{r.TransTitle, r.TransSummary}
-	}
-
-	for i := range newsList {
-		if r, ok := resultMap[i+1]; ok {
-			newsList[i].TransTitle = r.TransTitle
-			newsList[i].TransSummary = r.TransSummary
-			newsList[i].Translated = true
-		}
+	resp, err := s.chat(
+		OpExtractEntities, []string{news.ID},
+		openai.ChatCompletionRequest{
+			Model: s.cfg().Model,
+			Messages: []openai.ChatCompletionMessage{
+				{Role: openai.ChatMessageRoleUser, Content: prompt},
+			},
+			Temperature: 0.1,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from AI")
 	}
 
-	return newsList, nil
+	var mentions []models.EntityMention
+	if err := json.Unmarshal([]byte(cleanJSONResponse(resp.Choices[0].Message.Content)), &mentions); err != nil {
+		return nil, fmt.Errorf("failed to parse entities: %v", err)
+	}
+	return mentions, nil
 }
 
 // cleanJSONResponse 清理 AI 返回的 JSON
@@ -348,79 +737,134 @@ func cleanJSONResponse(content string) string {
 // ProcessUnprocessedNews 处理未处理的新闻
 func (s *AIService) ProcessUnprocessedNews(limit int) error {
 	rows, err := database.DB.Query(`
-		SELECT id, title, content FROM news 
+		SELECT id, title, content, COALESCE(source, ''), COALESCE(category, '') FROM news 
 		WHERE translated = 0 AND is_filtered = 0 
 		ORDER BY created_at DESC LIMIT ?
 	`, limit)
 	if err != nil {
 		return err
 	}
-	defer rows.Close()
-
+	var list []models.News
 	for rows.Next() {
 		var news models.News
-		if err := rows.Scan(&news.ID, &news.Title, &news.Content); err != nil {
+		if err := rows.Scan(&news.ID, &news.Title, &news.Content, &news.Source, &news.Category); err != nil {
 			continue
 		}
+		list = append(list, news)
+	}
+	rows.Close()
 
+	// 与批量翻译一样先经过偏好模型筛选
+	for _, news := range preference.Screen(list) {
 		if err := s.ProcessNews(&news); err != nil {
 			log.Printf("Failed to process news %s: %v", news.ID, err)
 			continue
 		}
 
 		// 更新数据库，同时移入阅读窗口
-		_, err := database.DB.Exec(`
-			UPDATE news SET translated = 1, trans_title = ?, trans_summary = ?, in_reading = 1, reading_at = ? WHERE id = ?
-		`, news.TransTitle, news.TransSummary, time.Now(), news.ID)
-		if err != nil {
-			log.Printf("Failed to update news %s: %v", news.ID, err)
+		s.saveNewsToReading(&news)
+		s.translateExtraLangs([]models.News{news}, s.cfg().Model)
+		s.translateFullContent([]models.News{news}, s.cfg().Model)
+		if _, err := preference.Rescore("id = ?", news.ID); err != nil {
+			log.Printf("Failed to rescore news %s: %v", news.ID, err)
 		}
 	}
 
 	return nil
 }
 
-// ProcessAndMoveToReading 批量处理新闻列表并移入阅读窗口
+// ProcessAndMoveToReading 批量处理新闻列表并移入阅读窗口。
+// 按估算的 token 数分批，最多同时进行 BatchConcurrency 个批量请求
 func (s *AIService) ProcessAndMoveToReading(newsList []models.News) error {
 	if len(newsList) == 0 {
 		return nil
 	}
 
-	log.Printf("Batch translating %d news...", len(newsList))
+	batches := s.packBatches(newsList, s.cfg().Model, true)
+	concurrency := s.cfg().BatchConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	log.Printf("Batch translating %d news in %d batches (concurrency %d)...", len(newsList), len(batches), concurrency)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, batch := range batches {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(n int, batch []models.News) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.processBatch(batch)
+			log.Printf("Batch %d/%d done (%d news)", n, len(batches), len(batch))
+		}(i+1, batch)
+	}
+	wg.Wait()
 
-	// 分批处理，每批最多 5 条（避免 token 超限）
-	batchSize := 5
-	for i := 0; i < len(newsList); i += batchSize {
-		end := i + batchSize
-		if end > len(newsList) {
-			end = len(newsList)
-		}
-		batch := newsList[i:end]
+	return nil
+}
 
-		// 批量翻译
-		translatedBatch, err := s.BatchTranslateNews(batch)
-		if err != nil {
-			log.Printf("Batch translate failed, falling back to single mode: %v", err)
-			// 批量翻译失败，回退到逐条翻译
+// processBatch 翻译一批新闻并移入阅读窗口
+func (s *AIService) processBatch(batch []models.News) {
+	// 预算用尽时使用降级模型；未配置降级模型或降级模型也已用尽则不再调用 AI
+	cfg := s.cfg()
+	model := cfg.Model
+	if err := usage.Allow(model); err != nil {
+		model = usage.GetBudget().FallbackModel
+		if model == "" || usage.Allow(model) != nil {
+			log.Printf("AI budget exceeded, moving %d news to reading untranslated", len(batch))
 			for j := range batch {
-				if err := s.ProcessNews(&batch[j]); err != nil {
-					log.Printf("Failed to process news %s: %v", batch[j].ID, err)
-					continue
+				s.saveUntranslatedToReading(&batch[j])
+			}
+			return
+		}
+	}
+
+	// 批量翻译
+	translatedBatch, err := s.batchTranslate(batch, model, cfg.TargetLang, true)
+	if err != nil && (model != cfg.Model || errors.Is(err, usage.ErrBudgetExceeded) || isRateLimited(err)) {
+		// 降级模式或重试后仍被限流时不再逐条调用 AI（逐条调用只会加重限流）
+		log.Printf("Batch translate failed, moving to reading untranslated: %v", err)
+		for j := range batch {
+			s.saveUntranslatedToReading(&batch[j])
+		}
+		return
+	}
+	if err != nil {
+		log.Printf("Batch translate failed, falling back to single mode: %v", err)
+		// 批量翻译失败，回退到逐条翻译
+		var done []models.News
+		for j := range batch {
+			if err := s.ProcessNews(&batch[j]); err != nil {
+				log.Printf("Failed to process news %s: %v", batch[j].ID, err)
+				if errors.Is(err, usage.ErrBudgetExceeded) {
+					s.saveUntranslatedToReading(&batch[j])
 				}
-				s.saveNewsToReading(&batch[j])
+				continue
 			}
-			continue
+			s.saveNewsToReading(&batch[j])
+			done = append(done, batch[j])
 		}
+		s.translateExtraLangs(done, model)
+		s.translateFullContent(done, model)
+		return
+	}
+
+	// 开启全文处理的长文改用分段摘要，替换按截断内容生成的摘要
+	s.summarizeLongArticles(translatedBatch, model)
 
-		// 保存翻译结果到数据库
-		for j := range translatedBatch {
+	// 保存翻译结果到数据库；多轮重新请求后仍缺失的条目不经翻译移入阅读窗口
+	var done []models.News
+	for j := range translatedBatch {
+		if translatedBatch[j].Translated {
 			s.saveNewsToReading(&translatedBatch[j])
+			done = append(done, translatedBatch[j])
+		} else {
+			s.saveUntranslatedToReading(&translatedBatch[j])
 		}
-
-		log.Printf("Batch translated %d news (batch %d/%d)", len(translatedBatch), (i/batchSize)+1, (len(newsList)+batchSize-1)/batchSize)
 	}
-
-	return nil
+	s.translateExtraLangs(done, model)
+	s.translateFullContent(done, model)
 }
 
 // saveNewsToReading 保存单条新闻到阅读窗口
@@ -430,84 +874,120 @@ func (s *AIService) saveNewsToReading(news *models.News) {
 	`, news.TransTitle, news.TransSummary, time.Now(), news.ID)
 	if err != nil {
 		log.Printf("Failed to update news %s: %v", news.ID, err)
-	} else {
-		log.Printf("Translated: %s", news.Title)
+		return
 	}
-}
+	log.Printf("Translated: %s", news.Title)
 
-// GenerateEmailTemplate 根据用户描述生成邮件模板
-func (s *AIService) GenerateEmailTemplate(description string, currentTemplate string) (string, error) {
-	var prompt string
-	
-	if currentTemplate != "" {
-		// 修改现有模板
-		prompt = fmt.Sprintf(`你是一个专业的邮件模板设计师。用户希望修改现有的邮件模板。
+	// 主语言译文同时写入 news_translations
+	if err := translations.Save(news.ID, models.Translation{Lang: s.cfg().TargetLang, Title: news.TransTitle, Summary: news.TransSummary}); err != nil {
+		log.Printf("Failed to save translation for news %s: %v", news.ID, err)
+	}
 
-用户需求：%s
+	// 写入情感倾向
+	if news.Sentiment != "" {
+		if _, err := database.DB.Exec("UPDATE news SET sentiment = ?, sentiment_score = ? WHERE id = ?", news.Sentiment, news.SentimentScore, news.ID); err != nil {
+			log.Printf("Failed to save sentiment for news %s: %v", news.ID, err)
+		}
+	}
 
-当前模板：
-%s
+	// 写入自定义字段
+	if len(news.Fields) > 0 {
+		if _, err := database.DB.Exec("UPDATE news SET fields = ? WHERE id = ?", enrichment.Encode(news.Fields), news.ID); err != nil {
+			log.Printf("Failed to save fields for news %s: %v", news.ID, err)
+		}
+	}
 
-请根据用户需求修改模板。要求：
-1. 生成完整的 HTML 邮件模板
-2. 必须包含以下 Go 模板变量（保持原样不变）：
-   - {{.Date}} 日期
-   - {{.Count}} 新闻数量
-   - {{.Generated}} 生成时间
-   - {{range .News}}...{{end}} 遍历新闻
-   - {{.Title}} 原标题
-   - {{.TransTitle}} 翻译后标题
-   - {{.TransSummary}} 翻译后摘要
-   - {{.URL}} 链接
-   - {{.Source}} 来源
-   - {{.Category}} 分类
-3. 样式要美观、现代、响应式
-4. 只返回 HTML 代码，不要任何解释
-
-直接输出完整的 HTML 模板：`, description, currentTemplate)
-	} else {
-		// 创建新模板
-		prompt = fmt.Sprintf(`你是一个专业的邮件模板设计师。请根据用户的描述创建一个新闻邮件模板。
+	// 写入实体
+	if len(news.Entities) > 0 {
+		if err := entities.SaveNewsEntities(news.ID, news.Entities); err != nil {
+			log.Printf("Failed to save entities for news %s: %v", news.ID, err)
+		}
+	}
 
-用户需求：%s
+	// 写入标签
+	if news.Tags == "" {
+		return
+	}
+	if tax := s.loadTaxonomy(); tax != nil {
+		names, err := tax.AssignTags(news.ID, textutil.SplitList(news.Tags), s.cfg().AllowNewTags)
+		if err != nil {
+			log.Printf("Failed to assign tags for news %s: %v", news.ID, err)
+			return
+		}
+		news.Tags = strings.Join(names, ",")
+	}
+}
 
-要求：
-1. 生成完整的 HTML 邮件模板
-2. 必须包含以下 Go 模板变量：
-   - {{.Date}} 日期
+// saveUntranslatedToReading 不经翻译直接移入阅读窗口（translated 保持为 0，之后可重新处理）
+func (s *AIService) saveUntranslatedToReading(news *models.News) {
+	_, err := database.DB.Exec("UPDATE news SET in_reading = 1, reading_at = ? WHERE id = ?", time.Now(), news.ID)
+	if err != nil {
+		log.Printf("Failed to move news %s to reading: %v", news.ID, err)
+	}
+}
+
+// emailTemplateVariables 邮件模板可用的变量说明（与 pusher.RenderTemplate 的数据一致）
+const emailTemplateVariables = `   - {{.Date}} 日期
    - {{.Count}} 新闻数量
    - {{.Generated}} 生成时间
    - {{range .News}}...{{end}} 遍历新闻列表
-   - 在循环内使用：{{.Title}}、{{.TransTitle}}、{{.TransSummary}}、{{.URL}}、{{.Source}}、{{.Category}}
-3. 使用 {{if .TransTitle}}{{.TransTitle}}{{else}}{{.Title}}{{end}} 来优先显示翻译标题
-4. 样式要美观、现代、响应式
-5. 颜色搭配协调，排版清晰
-6. 只返回 HTML 代码，不要任何解释
+   - 在循环内使用：{{.Title}} 原标题、{{.TransTitle}} 翻译后标题、{{.TransSummary}} 翻译后摘要、{{.URL}} 链接、{{.Source}} 来源、{{.Category}} 分类
+   - 使用 {{if .TransTitle}}{{.TransTitle}}{{else}}{{.Title}}{{end}} 来优先显示翻译标题
+   - 推送任务选择了摘要样式时 {{.TransSummary}} 为该样式的摘要，{{.SummaryStyle}} 为样式名称；要点格式的摘要以换行分隔，摘要元素应使用 white-space: pre-line
+   - 配置了多个目标语言时，用 {{with .T "ug"}}{{.Title}} {{.Summary}}{{end}} 显示指定语言的译文（没有该语言译文时标题为原标题）
+   - 推荐先用 {{range .Stories}}...{{end}} 渲染故事块（同一事件的多源报道，循环内有 {{.Title}}、{{.Summary}}，并用 {{range .News}} 列出所有来源链接），再用 {{range .Singles}}...{{end}} 渲染其余新闻
+   - 在新闻列表之前用 {{if .Briefing}}...{{end}} 渲染 AI 综述（要点 {{range .Briefing.Takeaways}}、主题 {{range .Briefing.Themes}}、关注 {{range .Briefing.Watch}}）`
+
+// templateVariables 邮件模板变量说明，附上已定义的自定义字段
+func templateVariables() string {
+	fields := enrichment.GetFields()
+	if len(fields) == 0 {
+		return emailTemplateVariables
+	}
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = "{{.Fields." + f.Name + "}}"
+	}
+	return emailTemplateVariables + "\n   - 在新闻循环内用 " + strings.Join(names, "、") + " 显示 AI 提取的自定义字段（没有值时为空，列表字段用 {{range}} 遍历）"
+}
+
+// GenerateEmailTemplate 根据用户描述生成邮件模板
+func (s *AIService) GenerateEmailTemplate(description string, currentTemplate string) (string, error) {
+	return s.generateEmailTemplate(context.Background(), description, currentTemplate, nil)
+}
+
+// GenerateEmailTemplateStream 流式生成邮件模板，onDelta 收到的是未清理的原始输出，返回值为清理后的模板
+func (s *AIService) GenerateEmailTemplateStream(ctx context.Context, description, currentTemplate string, onDelta func(string) error) (string, error) {
+	return s.generateEmailTemplate(ctx, description, currentTemplate, onDelta)
+}
 
-直接输出完整的 HTML 模板：`, description)
+func (s *AIService) generateEmailTemplate(ctx context.Context, description, currentTemplate string, onDelta func(string) error) (string, error) {
+	prompt, err := prompts.Render(prompts.EmailTemplate, prompts.Data{
+		Description:     description,
+		CurrentTemplate: currentTemplate,
+		Variables:       templateVariables(),
+	})
+	if err != nil {
+		return "", err
 	}
 
-	resp, err := s.client.CreateChatCompletion(
-		context.Background(),
+	content, err := s.complete(
+		ctx, OpEmailTemplate, nil,
 		openai.ChatCompletionRequest{
-			Model: s.config.Model,
+			Model: s.cfg().Model,
 			Messages: []openai.ChatCompletionMessage{
 				{Role: openai.ChatMessageRoleUser, Content: prompt},
 			},
 			Temperature: 0.7,
 		},
+		onDelta,
 	)
 	if err != nil {
 		return "", err
 	}
 
-	if len(resp.Choices) > 0 {
-		content := resp.Choices[0].Message.Content
-		// 清理可能的 markdown 代码块标记
-		content = cleanHTMLResponse(content)
-		return content, nil
-	}
-	return "", fmt.Errorf("no response from AI")
+	// 清理可能的 markdown 代码块标记
+	return cleanHTMLResponse(content), nil
 }
 
 // cleanHTMLResponse 清理 AI 返回的 HTML 代码
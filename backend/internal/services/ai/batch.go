@@ -0,0 +1,361 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/enrichment"
+	"news-intel-app/internal/services/glossary"
+	"news-intel-app/internal/services/prompts"
+	"news-intel-app/internal/services/sentiment"
+	"news-intel-app/internal/services/transcache"
+	"news-intel-app/internal/services/translations"
+	"news-intel-app/internal/services/usage"
+	"news-intel-app/internal/textutil"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+// batchRepairRounds 批量翻译结果缺失或无效时，只针对这些条目重新请求的最多轮数
+const batchRepairRounds = 2
+
+// 批量翻译分批参数（token 数均为估算值）
+const (
+	DefaultBatchConcurrency = 2
+	maxBatchItems           = 20  // 单批最多条数，序号过多时模型容易错位
+	batchContentRunes       = 500 // 每条新闻发送的内容长度
+	batchPromptTokens       = 600 // 提示词模板本身
+	itemOutputTokens        = 250 // 每条结果的标题和摘要
+	itemExtraOutputTokens   = 150 // 同时打标签、提取实体和判断情感时每条额外的输出
+)
+
+// batchResult 批量翻译返回的单条结果
+type batchResult struct {
+	Index          int                    `json:"index"`
+	TransTitle     string                 `json:"trans_title"`
+	TransSummary   string                 `json:"trans_summary"`
+	Tags           []string               `json:"tags"`
+	Entities       []models.EntityMention `json:"entities"`
+	Sentiment      string                 `json:"sentiment,omitempty"`
+	SentimentScore *float64               `json:"sentiment_score,omitempty"`
+	Fields         map[string]interface{} `json:"fields,omitempty"`
+}
+
+// apply 将批量翻译结果填充到新闻，自定义字段按 fields 的定义转换
+func (r batchResult) apply(news *models.News, fields []models.EnrichmentField) {
+	news.TransTitle = r.TransTitle
+	news.TransSummary = r.TransSummary
+	news.Tags = strings.Join(r.Tags, ",")
+	news.Entities = r.Entities
+	news.Sentiment, news.SentimentScore = sentiment.Normalize(r.Sentiment, r.SentimentScore)
+	news.Fields = enrichment.Normalize(fields, r.Fields)
+	news.Translated = true
+}
+
+// BatchTranslateNews 批量翻译新闻（节省 API 调用）。
+// 只有请求本身失败时返回错误；多轮重新请求后仍缺失的条目保持 Translated = false
+func (s *AIService) BatchTranslateNews(newsList []models.News) ([]models.News, error) {
+	return s.batchTranslate(newsList, s.cfg().Model, s.cfg().TargetLang, true)
+}
+
+// packBatches 按估算的 token 数分批：每批的输入不超过模型上下文减去输出预留，输出不超过模型单次输出上限
+func (s *AIService) packBatches(newsList []models.News, model string, withExtra bool) [][]models.News {
+	contextTokens, maxOutput := usage.Limits(model)
+	perItemOutput := itemOutputTokens
+	var extra string
+	if withExtra {
+		extra = s.batchExtra()
+		if extra != "" {
+			perItemOutput += itemExtraOutputTokens
+		}
+	}
+	inputBudget := contextTokens - maxOutput - batchPromptTokens - textutil.EstimateTokens(extra)
+
+	var batches [][]models.News
+	var current []models.News
+	inputTokens, outputTokens := 0, 0
+	for _, news := range newsList {
+		itemTokens := textutil.EstimateTokens(news.Title) + textutil.EstimateTokens(batchContent(news)) + 10
+		if len(current) > 0 && (len(current) >= maxBatchItems ||
+			inputTokens+itemTokens > inputBudget || outputTokens+perItemOutput > maxOutput) {
+			batches = append(batches, current)
+			current, inputTokens, outputTokens = nil, 0, 0
+		}
+		current = append(current, news)
+		inputTokens += itemTokens
+		outputTokens += perItemOutput
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// batchTranslate 使用指定模型将新闻批量翻译为 lang；withExtra 为 true 时同时打标签、提取实体和判断情感（只在主语言上做）
+func (s *AIService) batchTranslate(newsList []models.News, model, lang string, withExtra bool) ([]models.News, error) {
+	if len(newsList) == 0 {
+		return newsList, nil
+	}
+
+	// 先查缓存，只把未命中的新闻发给 AI
+	var extra string
+	var fields []models.EnrichmentField
+	if withExtra {
+		extra = s.batchExtra()
+		fields = enrichment.GetFields()
+	}
+	version := strconv.Itoa(prompts.Version(prompts.BatchTranslate))
+	g := loadGlossary(lang)
+	keys := make([]string, len(newsList))
+	terms := make([][]models.GlossaryTerm, len(newsList))
+	var pending []int
+	for i, news := range newsList {
+		terms[i] = g.Match(news.Title, batchContent(news))
+		keys[i] = transcache.Key(OpBatchTranslate, model, lang, version, extra, glossary.Instruction(terms[i]), news.Title, batchContent(news))
+		var r batchResult
+		if transcache.GetJSON(keys[i], &r) {
+			r.apply(&newsList[i], fields)
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	for round := 0; len(pending) > 0 && round <= batchRepairRounds; round++ {
+		if round > 0 {
+			log.Printf("Re-requesting %d missing or invalid batch items (round %d)", len(pending), round)
+		}
+		items := make([]models.News, len(pending))
+		texts := make([]string, 0, 2*len(pending))
+		for j, i := range pending {
+			items[j] = newsList[i]
+			texts = append(texts, newsList[i].Title, batchContent(newsList[i]))
+		}
+
+		results, err := s.requestBatch(items, extra, glossary.Instruction(g.Match(texts...)), model, lang, withExtra, fields)
+		if err != nil {
+			if round == 0 {
+				return nil, err
+			}
+			log.Printf("Batch re-request failed: %v", err)
+			break
+		}
+
+		var missing []int
+		for j, i := range pending {
+			r, ok := results[j+1]
+			if !ok {
+				missing = append(missing, i)
+				continue
+			}
+			r.apply(&newsList[i], fields)
+			// 标题完整翻译，按术语表检查；摘要可能省略术语，不做检查
+			glossary.Flag(newsList[i].ID, lang, OpBatchTranslate, r.TransTitle, glossary.Check(terms[i], newsList[i].Title, r.TransTitle))
+			r.Index = 0
+			transcache.PutJSON(keys[i], OpBatchTranslate, model, r)
+		}
+		pending = missing
+	}
+
+	if len(pending) > 0 {
+		log.Printf("Batch translate: %d of %d items still missing after %d re-requests", len(pending), len(newsList), batchRepairRounds)
+	}
+	return newsList, nil
+}
+
+// requestBatch 请求一批翻译，返回按序号（从 1 开始）校验通过的结果。
+// 响应无法解析时视为全部缺失，不返回错误
+func (s *AIService) requestBatch(items []models.News, extra, terms, model, lang string, withExtra bool, fields []models.EnrichmentField) (map[int]batchResult, error) {
+	prompt, err := prompts.Render(prompts.BatchTranslate, prompts.Data{Items: batchItems(items), Extra: extra, Glossary: terms, TargetLang: lang})
+	if err != nil {
+		return nil, err
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model: model,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleUser, Content: prompt},
+		},
+		Temperature:    0.3,
+		ResponseFormat: s.batchResponseFormat(withExtra, fields),
+	}
+	resp, err := s.chat(OpBatchTranslate, newsIDs(items), req)
+	if err != nil && req.ResponseFormat != nil && isBadRequest(err) {
+		// 服务商不支持 json_schema 时退回普通模式，直到重新加载配置
+		log.Printf("Structured output not supported by provider, falling back to plain JSON: %v", err)
+		s.schemaUnsupported.Store(true)
+		req.ResponseFormat = nil
+		resp, err = s.chat(OpBatchTranslate, newsIDs(items), req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("batch translate API error: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from AI")
+	}
+
+	content := resp.Choices[0].Message.Content
+	results, err := parseBatchResults(content)
+	if err != nil {
+		log.Printf("Failed to parse batch translate response: %v, content: %s", err, content)
+		return map[int]batchResult{}, nil
+	}
+	return validateBatchResults(results, len(items)), nil
+}
+
+// parseBatchResults 解析批量翻译响应：结构化输出为 {"items": [...]}，普通模式为 JSON 数组
+func parseBatchResults(content string) ([]batchResult, error) {
+	content = cleanJSONResponse(content)
+	if strings.HasPrefix(content, "{") {
+		var wrapped struct {
+			Items []batchResult `json:"items"`
+		}
+		if err := json.Unmarshal([]byte(content), &wrapped); err != nil {
+			return nil, err
+		}
+		return wrapped.Items, nil
+	}
+	var results []batchResult
+	if err := json.Unmarshal([]byte(content), &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// validateBatchResults 只保留序号在范围内、未重复且标题不为空的结果
+func validateBatchResults(results []batchResult, count int) map[int]batchResult {
+	valid := make(map[int]batchResult, len(results))
+	for _, r := range results {
+		if r.Index < 1 || r.Index > count {
+			continue
+		}
+		if _, dup := valid[r.Index]; dup {
+			continue
+		}
+		if strings.TrimSpace(r.TransTitle) == "" {
+			continue
+		}
+		valid[r.Index] = r
+	}
+	return valid
+}
+
+// translateExtraLangs 将已完成主语言翻译的新闻翻译为其余目标语言，写入 news_translations
+func (s *AIService) translateExtraLangs(newsList []models.News, model string) {
+	langs := s.targetLangs()
+	if len(langs) < 2 || len(newsList) == 0 {
+		return
+	}
+	for _, lang := range langs[1:] {
+		items := make([]models.News, len(newsList))
+		for i, n := range newsList {
+			items[i] = models.News{ID: n.ID, Title: n.Title, Content: n.Content}
+		}
+		translated, err := s.batchTranslate(items, model, lang, false)
+		if err != nil {
+			log.Printf("Failed to translate %d news to %s: %v", len(items), lang, err)
+			continue
+		}
+		for _, n := range translated {
+			if !n.Translated {
+				continue
+			}
+			if err := translations.Save(n.ID, models.Translation{Lang: lang, Title: n.TransTitle, Summary: n.TransSummary}); err != nil {
+				log.Printf("Failed to save %s translation for news %s: %v", lang, n.ID, err)
+			}
+		}
+	}
+}
+
+// batchResponseFormat 批量翻译的 JSON Schema；服务商不支持结构化输出时返回 nil
+func (s *AIService) batchResponseFormat(withExtra bool, fields []models.EnrichmentField) *openai.ChatCompletionResponseFormat {
+	// 模拟服务商按 schema 生成结果（标签、实体、情感和自定义字段），同样需要返回 schema
+	if provider := s.cfg().Provider; s.schemaUnsupported.Load() || (provider != "" && provider != "openai" && provider != ProviderMock) {
+		return nil
+	}
+
+	item := jsonschema.Definition{
+		Type: jsonschema.Object,
+		Properties: map[string]jsonschema.Definition{
+			"index":         {Type: jsonschema.Integer, Description: "新闻序号，与输入的 [新闻N] 对应"},
+			"trans_title":   {Type: jsonschema.String},
+			"trans_summary": {Type: jsonschema.String},
+		},
+		Required:             []string{"index", "trans_title", "trans_summary"},
+		AdditionalProperties: false,
+	}
+	if withExtra && s.loadTaxonomy() != nil {
+		item.Properties["tags"] = jsonschema.Definition{Type: jsonschema.Array, Items: &jsonschema.Definition{Type: jsonschema.String}}
+		item.Required = append(item.Required, "tags")
+	}
+	if withExtra && s.cfg().EnableEntities {
+		item.Properties["entities"] = jsonschema.Definition{
+			Type: jsonschema.Array,
+			Items: &jsonschema.Definition{
+				Type: jsonschema.Object,
+				Properties: map[string]jsonschema.Definition{
+					"name": {Type: jsonschema.String},
+					"type": {Type: jsonschema.String, Enum: []string{"organization", "person", "product", "location"}},
+				},
+				Required:             []string{"name", "type"},
+				AdditionalProperties: false,
+			},
+		}
+		item.Required = append(item.Required, "entities")
+	}
+	if withExtra && s.cfg().EnableSentiment {
+		item.Properties["sentiment"] = jsonschema.Definition{Type: jsonschema.String, Enum: sentiment.Labels}
+		item.Properties["sentiment_score"] = jsonschema.Definition{Type: jsonschema.Number, Description: "-1 最负面，0 中性，1 最正面"}
+		item.Required = append(item.Required, "sentiment", "sentiment_score")
+	}
+	if withExtra && len(fields) > 0 {
+		item.Properties["fields"] = fieldsSchema(fields)
+		item.Required = append(item.Required, "fields")
+	}
+
+	return &openai.ChatCompletionResponseFormat{
+		Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+		JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+			Name: "batch_translation",
+			Schema: &jsonschema.Definition{
+				Type:                 jsonschema.Object,
+				Properties:           map[string]jsonschema.Definition{"items": {Type: jsonschema.Array, Items: &item}},
+				Required:             []string{"items"},
+				AdditionalProperties: false,
+			},
+			Strict: true,
+		},
+	}
+}
+
+// fieldsSchema 自定义字段的 JSON Schema：每个字段都必须返回，没有相关信息时为 null
+func fieldsSchema(fields []models.EnrichmentField) jsonschema.Definition {
+	def := jsonschema.Definition{
+		Type:                 jsonschema.Object,
+		Properties:           map[string]jsonschema.Definition{},
+		AdditionalProperties: false,
+	}
+	for _, f := range fields {
+		var value jsonschema.Definition
+		switch f.Type {
+		case enrichment.TypeNumber:
+			value = jsonschema.Definition{Type: jsonschema.Number}
+		case enrichment.TypeBoolean:
+			value = jsonschema.Definition{Type: jsonschema.Boolean}
+		case enrichment.TypeList:
+			value = jsonschema.Definition{Type: jsonschema.Array, Items: &jsonschema.Definition{Type: jsonschema.String}}
+		default:
+			value = jsonschema.Definition{Type: jsonschema.String}
+		}
+		def.Properties[f.Name] = jsonschema.Definition{
+			Description: f.Instruction,
+			AnyOf:       []jsonschema.Definition{value, {Type: jsonschema.Null}},
+		}
+		def.Required = append(def.Required, f.Name)
+	}
+	return def
+}
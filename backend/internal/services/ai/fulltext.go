@@ -0,0 +1,120 @@
+package ai
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/glossary"
+	"news-intel-app/internal/services/prompts"
+	"news-intel-app/internal/services/transcache"
+	"news-intel-app/internal/services/translations"
+	"news-intel-app/internal/textutil"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// fullTextChunkRunes 全文翻译每个片段的最大字符数（按段落边界切分）
+const fullTextChunkRunes = 2000
+
+// TranslateContent 将新闻全文翻译为 lang（为空时为主语言）。
+// 长文按段落分片翻译后拼接，保留段落、小标题和列表格式；结果写入 news_translations，主语言同时写入 trans_content
+func (s *AIService) TranslateContent(news *models.News, lang string) (string, error) {
+	if lang == "" {
+		lang = s.cfg().TargetLang
+	}
+	text := news.Content
+	if strings.TrimSpace(textutil.StripHTML(text)) == "" {
+		text = news.Summary
+	}
+	chunks := textutil.ChunkParagraphs(textutil.Paragraphs(text), fullTextChunkRunes)
+	if len(chunks) == 0 {
+		return "", fmt.Errorf("news has no content to translate")
+	}
+
+	version := strconv.Itoa(prompts.Version(prompts.TranslateContent))
+	g := loadGlossary(lang)
+	parts := make([]string, 0, len(chunks))
+	for i, chunk := range chunks {
+		terms := g.Match(chunk)
+		instruction := glossary.Instruction(terms)
+		key := transcache.Key(OpTranslateContent, s.cfg().Model, lang, version, instruction, chunk)
+		if cached, ok := transcache.Get(key); ok {
+			parts = append(parts, cached)
+			continue
+		}
+
+		prompt, err := prompts.Render(prompts.TranslateContent, prompts.Data{Text: chunk, TargetLang: lang, Glossary: instruction})
+		if err != nil {
+			return "", err
+		}
+		resp, err := s.chat(
+			OpTranslateContent, []string{news.ID},
+			openai.ChatCompletionRequest{
+				Model: s.cfg().Model,
+				Messages: []openai.ChatCompletionMessage{
+					{Role: openai.ChatMessageRoleUser, Content: prompt},
+				},
+				Temperature: 0.3,
+			},
+		)
+		if err != nil {
+			return "", fmt.Errorf("chunk %d/%d: %w", i+1, len(chunks), err)
+		}
+		if len(resp.Choices) == 0 {
+			return "", fmt.Errorf("no response from AI")
+		}
+		part := strings.TrimSpace(resp.Choices[0].Message.Content)
+		glossary.Flag(news.ID, lang, OpTranslateContent, part, glossary.Check(terms, chunk, part))
+		transcache.Put(key, OpTranslateContent, s.cfg().Model, part)
+		parts = append(parts, part)
+	}
+
+	content := strings.Join(parts, "\n\n")
+	if lang == s.cfg().TargetLang {
+		if _, err := database.DB.Exec("UPDATE news SET trans_content = ? WHERE id = ?", content, news.ID); err != nil {
+			return "", err
+		}
+		news.TransContent = content
+	}
+	if err := translations.SaveContent(news.ID, lang, content); err != nil {
+		return "", err
+	}
+	return content, nil
+}
+
+// wantsFullTranslation 新闻所属分类或来源是否开启了自动全文翻译
+func (s *AIService) wantsFullTranslation(news *models.News) bool {
+	for _, c := range s.cfg().FullTransCategories {
+		if c == news.Category {
+			return true
+		}
+	}
+	var enabled bool
+	err := database.DB.QueryRow(`
+		SELECT s.full_translate FROM news n JOIN news_sources s ON s.id = n.source_id WHERE n.id = ?
+	`, news.ID).Scan(&enabled)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Failed to check full translation for news %s: %v", news.ID, err)
+	}
+	return enabled
+}
+
+// translateFullContent 对开启了自动全文翻译的新闻翻译全文（只翻译主语言，降级模型不做全文翻译）
+func (s *AIService) translateFullContent(newsList []models.News, model string) {
+	if model != s.cfg().Model {
+		return
+	}
+	for i := range newsList {
+		if !s.wantsFullTranslation(&newsList[i]) {
+			continue
+		}
+		if _, err := s.TranslateContent(&newsList[i], ""); err != nil {
+			log.Printf("Failed to translate full content of news %s: %v", newsList[i].ID, err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/prompts"
+	"news-intel-app/internal/services/transcache"
+	"news-intel-app/internal/textutil"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+const (
+	longTextRunes     = 4000 // 纯文本超过该长度时按长文分段摘要
+	summaryChunkRunes = 3000 // 分段摘要每个片段的最大字符数
+	maxReduceRounds   = 3    // 各部分要点仍然过长时最多再合并的轮数
+)
+
+// isLongText 去除 HTML 后的文本是否需要分段摘要
+func isLongText(text string) bool {
+	return utf8.RuneCountInString(textutil.StripHTML(text)) > longTextRunes
+}
+
+// summarizeLong 长文摘要（map-reduce）：按段落切分后逐段概括，再根据各部分要点生成全文摘要。
+// onDelta 不为空时合并阶段流式输出
+func (s *AIService) summarizeLong(ctx context.Context, title, text, lang, glossaryInstruction string, newsIDs []string, onDelta func(string) error) (string, error) {
+	notes, err := s.sectionNotes(ctx, title, text, lang, newsIDs)
+	if err != nil {
+		return "", err
+	}
+
+	var items strings.Builder
+	for i, note := range notes {
+		items.WriteString(fmt.Sprintf("\n[%d] %s\n", i+1, note))
+	}
+	prompt, err := prompts.Render(prompts.SummarizeMerge, prompts.Data{
+		Items:      items.String(),
+		Count:      len(notes),
+		TargetLang: lang,
+		Glossary:   glossaryInstruction,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return s.complete(
+		ctx, OpSummarize, newsIDs,
+		openai.ChatCompletionRequest{
+			Model: s.cfg().Model,
+			Messages: []openai.ChatCompletionMessage{
+				{Role: openai.ChatMessageRoleUser, Content: prompt},
+			},
+			Temperature: 0.5,
+		},
+		onDelta,
+	)
+}
+
+// sectionNotes 分段概括长文，返回按原文顺序排列的各部分要点；
+// 要点合计仍超过一个片段时继续合并概括（最多 maxReduceRounds 轮）
+func (s *AIService) sectionNotes(ctx context.Context, title, text, lang string, newsIDs []string) ([]string, error) {
+	parts := textutil.ChunkParagraphs(textutil.Paragraphs(text), summaryChunkRunes)
+	for round := 0; len(parts) > 1 && round < maxReduceRounds; round++ {
+		notes, err := s.summarizeSections(ctx, title, parts, lang, newsIDs)
+		if err != nil {
+			return nil, err
+		}
+		if utf8.RuneCountInString(strings.Join(notes, "\n\n")) <= summaryChunkRunes {
+			return notes, nil
+		}
+		parts = textutil.ChunkParagraphs(notes, summaryChunkRunes)
+	}
+	return parts, nil
+}
+
+// inBatchKey 标记调用来自批量处理：批量请求已占用一个并发名额，分段摘要在该名额内串行执行，
+// 总并发不超过 BatchConcurrency
+type inBatchKey struct{}
+
+// summarizeSections 并发概括各片段（并发数与批量翻译相同，批量处理中串行），结果按片段顺序返回；每段结果单独缓存
+func (s *AIService) summarizeSections(ctx context.Context, title string, parts []string, lang string, newsIDs []string) ([]string, error) {
+	version := strconv.Itoa(prompts.Version(prompts.SummarizeSection))
+	concurrency := s.cfg().BatchConcurrency
+	if concurrency < 1 || ctx.Value(inBatchKey{}) != nil {
+		concurrency = 1
+	}
+
+	notes := make([]string, len(parts))
+	errs := make([]error, len(parts))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, part := range parts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, part string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			key := transcache.Key(OpSummarizeSection, s.cfg().Model, lang, version, title, part)
+			if cached, ok := transcache.Get(key); ok {
+				notes[i] = cached
+				return
+			}
+			prompt, err := prompts.Render(prompts.SummarizeSection, prompts.Data{Title: title, Text: part, Count: i + 1, TargetLang: lang})
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			note, err := s.complete(
+				ctx, OpSummarizeSection, newsIDs,
+				openai.ChatCompletionRequest{
+					Model: s.cfg().Model,
+					Messages: []openai.ChatCompletionMessage{
+						{Role: openai.ChatMessageRoleUser, Content: prompt},
+					},
+					Temperature: 0.3,
+				},
+				nil,
+			)
+			if err != nil {
+				errs[i] = fmt.Errorf("section %d/%d: %w", i+1, len(parts), err)
+				return
+			}
+			notes[i] = strings.TrimSpace(note)
+			transcache.Put(key, OpSummarizeSection, s.cfg().Model, notes[i])
+		}(i, part)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return notes, nil
+}
+
+// summarizeLongArticles 开启了全文处理（来源或分类的全文翻译开关）的长文改用分段摘要，
+// 替换批量翻译中按截断内容生成的摘要（降级模型不做分段摘要）
+func (s *AIService) summarizeLongArticles(newsList []models.News, model string) {
+	if model != s.cfg().Model {
+		return
+	}
+	ctx := context.WithValue(context.Background(), inBatchKey{}, true)
+	for i := range newsList {
+		n := &newsList[i]
+		if !n.Translated || !isLongText(n.Content) || !s.wantsFullTranslation(n) {
+			continue
+		}
+		summary, err := s.summarize(ctx, n.Content, s.cfg().TargetLang, []string{n.ID}, nil)
+		if err != nil {
+			log.Printf("Failed to summarize long article %s: %v", n.ID, err)
+			continue
+		}
+		n.TransSummary = summary
+	}
+}
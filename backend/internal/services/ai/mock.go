@@ -0,0 +1,376 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/usage"
+	"news-intel-app/internal/textutil"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+// ProviderMock 内置的离线模拟服务商：不发送任何网络请求，按操作类型返回确定的结果，
+// 用于本地开发、演示和 CI（翻译为带前缀的原文，摘要为截断的原文）
+const ProviderMock = "mock"
+
+const (
+	mockEmbeddingDims = 256 // 模拟向量的维度
+	mockSummaryRunes  = 100 // 模拟摘要截取的原文长度
+	mockDeltaRunes    = 16  // 模拟流式输出每段的字符数
+)
+
+var (
+	// mockBatchItem 批量翻译提示词中的一条新闻（格式见 batchItems）
+	mockBatchItem = regexp.MustCompile(`\[新闻(\d+)\]\n标题: ([^\n]*)\n内容: `)
+	// mockListItem 综述和问答资料中的一条（格式见 briefingItems 和问答资料）
+	mockListItem = regexp.MustCompile(`\n\[\d+\][^\n]*`)
+	// mockStoryTitle 故事摘要报道列表中的标题（格式见 storyItems）
+	mockStoryTitle = regexp.MustCompile(`\n标题: ([^\n]*)`)
+)
+
+// IsMock 当前是否使用离线模拟服务商
+func (s *AIService) IsMock() bool {
+	return s.cfg().Provider == ProviderMock
+}
+
+// mockChat 模拟对话接口并按估算的 tokens 记录用量（模型记为 mock，不计费）
+func (s *AIService) mockChat(op string, newsIDs []string, req openai.ChatCompletionRequest) openai.ChatCompletionResponse {
+	start := time.Now()
+	var prompt strings.Builder
+	for _, m := range req.Messages {
+		prompt.WriteString(m.Content)
+		prompt.WriteString("\n")
+	}
+	content := mockReply(op, req)
+	tokens := openai.Usage{
+		PromptTokens:     textutil.EstimateTokens(prompt.String()),
+		CompletionTokens: textutil.EstimateTokens(content),
+	}
+	tokens.TotalTokens = tokens.PromptTokens + tokens.CompletionTokens
+	usage.Record(usage.Entry{
+		Operation:        op,
+		Model:            ProviderMock,
+		PromptTokens:     tokens.PromptTokens,
+		CompletionTokens: tokens.CompletionTokens,
+		Latency:          time.Since(start),
+		NewsIDs:          newsIDs,
+	})
+	return openai.ChatCompletionResponse{
+		Model: ProviderMock,
+		Choices: []openai.ChatCompletionChoice{{
+			Message:      openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: content},
+			FinishReason: openai.FinishReasonStop,
+		}},
+		Usage: tokens,
+	}
+}
+
+// mockStream 把模拟回复分段回调 onDelta，模拟流式输出
+func (s *AIService) mockStream(op string, newsIDs []string, req openai.ChatCompletionRequest, onDelta func(string) error) (string, error) {
+	content := s.mockChat(op, newsIDs, req).Choices[0].Message.Content
+	runes := []rune(content)
+	for i := 0; i < len(runes); i += mockDeltaRunes {
+		end := i + mockDeltaRunes
+		if end > len(runes) {
+			end = len(runes)
+		}
+		if onDelta != nil {
+			if err := onDelta(string(runes[i:end])); err != nil {
+				return string(runes[:end]), err
+			}
+		}
+	}
+	return content, nil
+}
+
+// mockReply 按操作类型生成确定的回复；输入从代码生成的列表（batchItems 等）和内置提示词的结构中提取，
+// 批量翻译的附加字段按请求的 JSON Schema 生成，自定义提示词时尽量兼容
+func mockReply(op string, req openai.ChatCompletionRequest) string {
+	messages := req.Messages
+	if len(messages) == 0 {
+		return ""
+	}
+	prompt := messages[len(messages)-1].Content
+
+	switch op {
+	case OpTranslate, OpTranslateContent:
+		return "[mock] " + mockSource(prompt)
+	case OpSummarize, OpSummarizeSection, OpStyledSummary:
+		return mockSummary(mockSource(prompt))
+	case OpBatchTranslate:
+		return mockBatch(prompt, batchItemSchema(req.ResponseFormat))
+	case OpFilter:
+		return `{"valuable": true, "reason": "mock"}`
+	case OpSuggestTags, OpExtractEntities:
+		return "[]"
+	case OpStorySummary:
+		var titles []string
+		for _, m := range mockStoryTitle.FindAllStringSubmatch(prompt, -1) {
+			titles = append(titles, strings.TrimSpace(m[1]))
+		}
+		return mockJSON(map[string]string{
+			"title":   "[mock] " + firstOr(titles, "story"),
+			"summary": fmt.Sprintf("[mock summary] %d 篇报道：%s", len(titles), strings.Join(titles, "；")),
+		})
+	case OpBriefing:
+		titles := mockTitles(prompt)
+		if len(titles) > 3 {
+			titles = titles[:3]
+		}
+		return mockJSON(models.Briefing{Takeaways: titles, Themes: []string{"mock"}, Watch: []string{}})
+	case OpEmailTemplate:
+		return mockEmailTemplate
+	case OpAsk:
+		// 资料在系统提示词中，问题为最后一条消息
+		refs := mockTitles(messages[0].Content)
+		if messages[0].Role != openai.ChatMessageRoleSystem || len(refs) == 0 {
+			return "[mock] 没有检索到相关新闻，无法回答：" + prompt
+		}
+		return fmt.Sprintf("[mock] 关于“%s”，最相关的资料是：%s [1]", prompt, refs[0])
+	default:
+		return "[mock] " + textutil.Truncate(prompt, 500)
+	}
+}
+
+// mockSource 提取提示词末尾的待处理文本：内置提示词中文本位于“只返回……”说明之后的空行后，
+// 没有该说明时取第一个空行之后的内容；开头的术语表要求会被去掉
+func mockSource(prompt string) string {
+	rest := prompt
+	if i := strings.Index(rest, "只返回"); i >= 0 {
+		rest = rest[i:]
+	}
+	if i := strings.Index(rest, "\n\n"); i >= 0 && strings.TrimSpace(rest[i:]) != "" {
+		rest = rest[i+2:]
+	} else if i := strings.Index(prompt, "\n\n"); i >= 0 {
+		rest = prompt[i+2:]
+	} else {
+		rest = prompt
+	}
+	if strings.HasPrefix(rest, "术语表") {
+		if i := strings.Index(rest, "\n\n"); i >= 0 {
+			rest = rest[i+2:]
+		}
+	}
+	return strings.TrimSpace(rest)
+}
+
+// mockSummary 取原文开头作为模拟摘要
+func mockSummary(text string) string {
+	return "[mock summary] " + textutil.TruncateSentence(textutil.StripHTML(text), mockSummaryRunes)
+}
+
+// batchItemSchema 批量翻译响应中单条结果的 schema；没有结构化输出时返回 nil
+func batchItemSchema(format *openai.ChatCompletionResponseFormat) *jsonschema.Definition {
+	if format == nil || format.JSONSchema == nil {
+		return nil
+	}
+	schema, ok := format.JSONSchema.Schema.(*jsonschema.Definition)
+	if !ok {
+		return nil
+	}
+	return schema.Properties["items"].Items
+}
+
+// mockBatch 按批量翻译提示词中的新闻列表返回结构化结果，附加字段按 schema 生成
+func mockBatch(prompt string, schema *jsonschema.Definition) string {
+	type item struct {
+		Index          int                    `json:"index"`
+		TransTitle     string                 `json:"trans_title"`
+		TransSummary   string                 `json:"trans_summary"`
+		Tags           []string               `json:"tags"`
+		Entities       []models.EntityMention `json:"entities"`
+		Sentiment      string                 `json:"sentiment,omitempty"`
+		SentimentScore *float64               `json:"sentiment_score,omitempty"`
+		Fields         map[string]interface{} `json:"fields,omitempty"`
+	}
+	var properties map[string]jsonschema.Definition
+	if schema != nil {
+		properties = schema.Properties
+	}
+	_, withSentiment := properties["sentiment"]
+	fields, withFields := properties["fields"]
+	items := []item{}
+	matches := mockBatchItem.FindAllStringSubmatchIndex(prompt, -1)
+	for i, m := range matches {
+		// 内容到下一条新闻开始为止
+		end := len(prompt)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		var index int
+		fmt.Sscanf(prompt[m[2]:m[3]], "%d", &index)
+		title := strings.TrimSpace(prompt[m[4]:m[5]])
+		it := item{
+			Index:        index,
+			TransTitle:   "[mock] " + title,
+			TransSummary: mockSummary(prompt[m[1]:end]),
+			Tags:         []string{},
+			Entities:     []models.EntityMention{},
+		}
+		if withSentiment {
+			it.Sentiment, it.SentimentScore = mockSentiment(title)
+		}
+		if withFields {
+			it.Fields = mockFields(fields, title)
+		}
+		items = append(items, it)
+	}
+	return mockJSON(map[string]interface{}{"items": items})
+}
+
+// mockSentiment 按标题哈希确定的情感倾向，同一标题结果不变
+func mockSentiment(title string) (string, *float64) {
+	h := fnv.New32a()
+	h.Write([]byte(title))
+	labels := []string{"positive", "neutral", "negative"}
+	i := int(h.Sum32() % 3)
+	score := float64(1-i) * 0.6
+	return labels[i], &score
+}
+
+// mockFields 按字段 schema 的类型由标题生成确定的值：文本为第一个词，数字为词数，列表为第一个词，布尔为 false
+func mockFields(fields jsonschema.Definition, title string) map[string]interface{} {
+	words := strings.Fields(title)
+	first := ""
+	if len(words) > 0 {
+		first = words[0]
+	}
+	values := make(map[string]interface{})
+	for name, def := range fields.Properties {
+		// 字段可为 null，类型在 AnyOf 的第一项
+		if len(def.AnyOf) > 0 {
+			def = def.AnyOf[0]
+		}
+		switch def.Type {
+		case jsonschema.Number:
+			values[name] = len(words)
+		case jsonschema.Boolean:
+			values[name] = false
+		case jsonschema.Array:
+			values[name] = []string{first}
+		default:
+			values[name] = first
+		}
+	}
+	return values
+}
+
+// mockTitles 提取列表中每一项编号后的标题（去掉括号中的来源）
+func mockTitles(prompt string) []string {
+	var titles []string
+	for _, m := range mockListItem.FindAllString(prompt, -1) {
+		line := strings.TrimSpace(m[strings.Index(m, "]")+1:])
+		if i := strings.LastIndex(line, "（"); i > 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		titles = append(titles, textutil.Truncate(line, 80))
+	}
+	return titles
+}
+
+func firstOr(list []string, fallback string) string {
+	if len(list) > 0 {
+		return list[0]
+	}
+	return fallback
+}
+
+func mockJSON(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// mockEmbed 确定的词袋向量：按词（中日韩文字按字）哈希到固定维度并归一化，相同词越多余弦相似度越高
+func (s *AIService) mockEmbed(texts []string) [][]float32 {
+	start := time.Now()
+	vectors := make([][]float32, len(texts))
+	tokens := 0
+	for i, text := range texts {
+		tokens += textutil.EstimateTokens(text)
+		v := make([]float32, mockEmbeddingDims)
+		for _, word := range mockWords(text) {
+			h := fnv.New32a()
+			h.Write([]byte(word))
+			v[h.Sum32()%mockEmbeddingDims]++
+		}
+		var norm float64
+		for _, x := range v {
+			norm += float64(x * x)
+		}
+		if norm > 0 {
+			norm = math.Sqrt(norm)
+			for j := range v {
+				v[j] = float32(float64(v[j]) / norm)
+			}
+		}
+		vectors[i] = v
+	}
+	usage.Record(usage.Entry{
+		Operation:    OpEmbed,
+		Model:        ProviderMock,
+		PromptTokens: tokens,
+		Latency:      time.Since(start),
+	})
+	return vectors
+}
+
+// mockWords 小写的英文单词和逐个的中日韩字符
+func mockWords(text string) []string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r >= 0x2E80:
+			flush()
+			words = append(words, string(r))
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			word.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words
+}
+
+// mockEmailTemplate 模拟服务商返回的示例邮件模板
+const mockEmailTemplate = `<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', sans-serif; color: #333; max-width: 640px; margin: 0 auto; padding: 20px; }
+        .news-item { border-bottom: 1px solid #eee; padding: 12px 0; }
+        .news-item a { color: #1677ff; text-decoration: none; font-weight: 600; }
+        .news-summary { color: #555; margin-top: 6px; white-space: pre-line; }
+        .meta { color: #999; font-size: 12px; margin-top: 4px; }
+    </style>
+</head>
+<body>
+    <h1>新闻简报（mock）</h1>
+    <p>{{.Date}} · 共 {{.Count}} 条新闻</p>
+    {{if .Briefing}}<ul>{{range .Briefing.Takeaways}}<li>{{.}}</li>{{end}}</ul>{{end}}
+    {{range .News}}
+    <div class="news-item">
+        <a href="{{.URL}}" target="_blank">{{if .TransTitle}}{{.TransTitle}}{{else}}{{.Title}}{{end}}</a>
+        {{if .TransSummary}}<div class="news-summary">{{.TransSummary}}</div>{{end}}
+        <div class="meta">{{.Source}} · {{.Category}}</div>
+    </div>
+    {{end}}
+    <p class="meta">生成时间：{{.Generated}}</p>
+</body>
+</html>`
@@ -0,0 +1,97 @@
+package ai
+
+import (
+	"path/filepath"
+	"testing"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/enrichment"
+	"news-intel-app/internal/services/prompts"
+)
+
+func setupMock(t *testing.T) *AIService {
+	t.Helper()
+	if err := database.Init(filepath.Join(t.TempDir(), "news.db")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.DB.Close() })
+	if err := prompts.Init(); err != nil {
+		t.Fatal(err)
+	}
+	return New(ProviderMock, "", "", "mock")
+}
+
+// 模拟服务商从内置提示词中提取待处理文本，内置提示词改动后这里应能发现
+func TestMockSourceMatchesBuiltinPrompts(t *testing.T) {
+	setupMock(t)
+	const text = "Apple shares rose 5% after earnings beat expectations."
+	const terms = "术语表（必须严格遵守）：\n- Apple → 苹果\n\n"
+	for _, name := range []string{prompts.Translate, prompts.Summarize, prompts.TranslateContent, prompts.SummarizeSection} {
+		for _, lang := range []string{"zh-CN", "ug", "en"} {
+			prompt, err := prompts.Render(name, prompts.Data{Text: text, TargetLang: lang, Glossary: terms, Count: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := mockSource(prompt); got != text {
+				t.Errorf("mockSource(%s, %s) = %q, want %q", name, lang, got, text)
+			}
+		}
+	}
+}
+
+func TestMockBatchFollowsSchema(t *testing.T) {
+	s := setupMock(t)
+	s.config.EnableSentiment = true
+	fields := []models.EnrichmentField{
+		{Name: "ticker", Type: enrichment.TypeString},
+		{Name: "words", Type: enrichment.TypeNumber},
+		{Name: "names", Type: enrichment.TypeList},
+		{Name: "earnings", Type: enrichment.TypeBoolean},
+	}
+	news := []models.News{
+		{ID: "a", Title: "Apple beats estimates", Content: "Apple reported record revenue."},
+		{ID: "b", Title: "苹果发布新手机", Content: ""},
+	}
+
+	results, err := s.requestBatch(news, s.batchExtra(), "", "mock", "zh-CN", true, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(news) {
+		t.Fatalf("got %d results, want %d", len(results), len(news))
+	}
+	for i, n := range news {
+		r := results[i+1]
+		if r.TransTitle != "[mock] "+n.Title {
+			t.Errorf("item %d title = %q", i+1, r.TransTitle)
+		}
+		if r.Sentiment == "" || r.SentimentScore == nil {
+			t.Errorf("item %d has no sentiment", i+1)
+		}
+	}
+
+	var got models.News
+	results[1].apply(&got, fields)
+	want := map[string]interface{}{"ticker": "Apple", "words": float64(3), "names": []string{"Apple"}, "earnings": false}
+	for name, v := range want {
+		if name == "names" {
+			if list, ok := got.Fields[name].([]string); !ok || len(list) != 1 || list[0] != "Apple" {
+				t.Errorf("fields[%s] = %#v, want %v", name, got.Fields[name], v)
+			}
+			continue
+		}
+		if got.Fields[name] != v {
+			t.Errorf("fields[%s] = %#v, want %#v", name, got.Fields[name], v)
+		}
+	}
+
+	// 其余目标语言的翻译不请求附加字段
+	results, err = s.requestBatch(news, "", "", "mock", "ug", false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := results[1]; r.Sentiment != "" || r.Fields != nil {
+		t.Errorf("extras returned without being requested: %+v", r)
+	}
+}
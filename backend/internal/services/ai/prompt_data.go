@@ -0,0 +1,148 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/enrichment"
+	"news-intel-app/internal/services/prompts"
+	"news-intel-app/internal/services/summaries"
+	"news-intel-app/internal/textutil"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// batchItems 批量翻译提示词中的新闻列表
+func batchItems(newsList []models.News) string {
+	var sb strings.Builder
+	for i, news := range newsList {
+		sb.WriteString(fmt.Sprintf("\n[新闻%d]\n标题: %s\n内容: %s\n", i+1, news.Title, batchContent(news)))
+	}
+	return sb.String()
+}
+
+// batchContent 批量翻译时发送的新闻内容
+func batchContent(news models.News) string {
+	content := news.Content
+	if content == "" {
+		content = news.Title
+	}
+	// 限制内容长度，避免 token 超限；按字符和句子边界截断，不会切开多字节字符
+	return textutil.TruncateSentence(content, batchContentRunes)
+}
+
+// batchExtra 批量翻译的附加要求（打标签、实体提取、情感倾向、自定义字段），放在新闻列表之前
+func (s *AIService) batchExtra() string {
+	var extra string
+	if tax := s.loadTaxonomy(); tax != nil {
+		extra += "另外，请为每条新闻增加 \"tags\" 字段（字符串数组），" + s.tagInstruction(tax) + "\n"
+	}
+	if s.cfg().EnableEntities {
+		extra += "另外，请为每条新闻增加 \"entities\" 字段：" + entityInstruction + "。\n"
+	}
+	if s.cfg().EnableSentiment {
+		extra += "另外，请为每条新闻增加 \"sentiment\" 和 \"sentiment_score\" 字段：" + sentimentInstruction + "。\n"
+	}
+	extra += enrichment.Instruction(enrichment.GetFields())
+	return extra
+}
+
+// storyItems 故事摘要提示词中的报道列表
+func storyItems(newsList []models.News) string {
+	var sb strings.Builder
+	for i, n := range newsList {
+		content := textutil.Truncate(textutil.StripHTML(n.Content), 300)
+		sb.WriteString(fmt.Sprintf("\n[报道%d] 来源: %s\n标题: %s\n内容: %s\n", i+1, n.Source, n.Title, content))
+	}
+	return sb.String()
+}
+
+// briefingItems 推送综述提示词中的新闻列表
+func briefingItems(newsList []models.News) string {
+	var sb strings.Builder
+	for i, n := range newsList {
+		title := n.TransTitle
+		if title == "" {
+			title = n.Title
+		}
+		summary := n.TransSummary
+		if summary == "" {
+			summary = textutil.Truncate(textutil.StripHTML(n.Content), 200)
+		}
+		sb.WriteString(fmt.Sprintf("\n[%d] %s（%s）\n%s\n", i+1, title, n.Source, summary))
+	}
+	return sb.String()
+}
+
+// sampleData 用示例新闻构造提示词变量，与实际调用时的数据格式一致
+func (s *AIService) sampleData(name string, sample []models.News) prompts.Data {
+	data := prompts.Data{TargetLang: s.cfg().TargetLang, Count: len(sample)}
+	if len(sample) > 0 {
+		data.Title = sample[0].Title
+		data.Content = sample[0].Content
+		data.Text = sample[0].Content
+		if data.Text == "" {
+			data.Text = sample[0].Title
+		}
+	}
+
+	switch name {
+	case prompts.Translate:
+		data.Text = data.Title
+	case prompts.BatchTranslate:
+		data.Items = batchItems(sample)
+		data.Extra = s.batchExtra()
+	case prompts.StorySummary:
+		data.Items = storyItems(sample)
+	case prompts.SuggestTags:
+		if tax := s.loadTaxonomy(); tax != nil {
+			data.Extra = s.tagInstruction(tax)
+		}
+	case prompts.StyledSummary:
+		if st, err := summaries.Get("bullets"); err == nil {
+			data.Extra = summaries.Instruction(st)
+		}
+	case prompts.EmailTemplate:
+		data.Description = "简洁的新闻日报，顶部显示日期和新闻数量"
+		data.Variables = templateVariables()
+	default:
+		data.Items = briefingItems(sample)
+	}
+	return data
+}
+
+// TestPrompt 用示例新闻渲染提示词并调用 AI，返回渲染后的提示词和原始输出。
+// content 为空时测试当前版本，否则测试传入的草稿
+func (s *AIService) TestPrompt(name, content string, sample []models.News) (string, string, error) {
+	data := s.sampleData(name, sample)
+
+	var prompt string
+	var err error
+	if content == "" {
+		prompt, err = prompts.Render(name, data)
+	} else {
+		prompt, err = prompts.RenderContent(content, data)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	resp, err := s.chat(
+		OpPromptTest, newsIDs(sample),
+		openai.ChatCompletionRequest{
+			Model: s.cfg().Model,
+			Messages: []openai.ChatCompletionMessage{
+				{Role: openai.ChatMessageRoleUser, Content: prompt},
+			},
+			Temperature: 0.3,
+		},
+	)
+	if err != nil {
+		return prompt, "", err
+	}
+	if len(resp.Choices) == 0 {
+		return prompt, "", fmt.Errorf("no response from AI")
+	}
+	return prompt, resp.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,128 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/glossary"
+	"news-intel-app/internal/services/prompts"
+	"news-intel-app/internal/services/summaries"
+	"news-intel-app/internal/textutil"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// styledSummaryRunes 按样式生成摘要时原文的最大字符数
+const styledSummaryRunes = 6000
+
+// StyledSummary 按摘要样式为新闻生成 lang 摘要（为空时为主语言）并保存
+func (s *AIService) StyledSummary(news *models.News, style *models.SummaryStyle, lang string) (string, error) {
+	return s.styledSummary(context.Background(), news, style, lang)
+}
+
+func (s *AIService) styledSummary(ctx context.Context, news *models.News, style *models.SummaryStyle, lang string) (string, error) {
+	if lang == "" {
+		lang = s.cfg().TargetLang
+	}
+	text := strings.TrimSpace(textutil.StripHTML(news.Content))
+	if text == "" {
+		text = news.Summary
+	}
+	if text == "" {
+		text = news.Title
+	}
+	if isLongText(text) {
+		// 长文先分段概括，再按样式根据各部分要点生成摘要
+		notes, err := s.sectionNotes(ctx, news.Title, text, lang, []string{news.ID})
+		if err != nil {
+			return "", err
+		}
+		text = strings.Join(notes, "\n\n")
+	}
+	text = textutil.TruncateSentence(text, styledSummaryRunes)
+
+	instruction := glossary.Instruction(loadGlossary(lang).Match(news.Title, text))
+	prompt, err := prompts.Render(prompts.StyledSummary, prompts.Data{
+		Title:      news.Title,
+		Text:       text,
+		TargetLang: lang,
+		Extra:      summaries.Instruction(style),
+		Glossary:   instruction,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.chat(
+		OpStyledSummary, []string{news.ID},
+		openai.ChatCompletionRequest{
+			Model: s.cfg().Model,
+			Messages: []openai.ChatCompletionMessage{
+				{Role: openai.ChatMessageRoleUser, Content: prompt},
+			},
+			Temperature: 0.5,
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("no response from AI")
+	}
+
+	summary := strings.TrimSpace(resp.Choices[0].Message.Content)
+	if err := summaries.Save(news.ID, style.Name, lang, summary); err != nil {
+		return "", err
+	}
+	return summary, nil
+}
+
+// ApplySummaryStyle 把新闻列表的 TransSummary 替换为指定样式的主语言摘要，供推送模板渲染。
+// 已生成的摘要直接使用，缺失的即时并发生成（并发数与批量翻译相同）并保存；生成失败的新闻保留默认摘要
+func (s *AIService) ApplySummaryStyle(newsList []models.News, name string) error {
+	style, err := summaries.Get(name)
+	if err != nil {
+		return err
+	}
+	lang := s.cfg().TargetLang
+	stored, err := summaries.Load(newsIDs(newsList), style.Name, lang)
+	if err != nil {
+		return err
+	}
+
+	concurrency := s.cfg().BatchConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	ctx := context.WithValue(context.Background(), inBatchKey{}, true)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i := range newsList {
+		n := &newsList[i]
+		if summary, ok := stored[n.ID]; ok {
+			n.TransSummary = summary
+			n.SummaryStyle = style.Name
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(n *models.News) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			summary, err := s.styledSummary(ctx, n, style, lang)
+			if err != nil {
+				log.Printf("Failed to generate %s summary for news %s: %v", style.Name, n.ID, err)
+				return
+			}
+			n.TransSummary = summary
+			n.SummaryStyle = style.Name
+		}(n)
+	}
+	wg.Wait()
+	return nil
+}
@@ -0,0 +1,291 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// 默认超时和重试次数
+const (
+	DefaultTimeoutSeconds = 60
+	DefaultMaxRetries     = 3
+
+	maxBackoff = 60 * time.Second
+)
+
+// errAttemptTimeout 单次请求超时（可重试）
+var errAttemptTimeout = errors.New("AI request timed out")
+
+// bucket 令牌桶，容量为每分钟上限，按秒匀速补充
+type bucket struct {
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+func newBucket(perMinute int) *bucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &bucket{capacity: float64(perMinute), tokens: float64(perMinute), last: time.Now()}
+}
+
+// refill 按流逝时间补充令牌
+func (b *bucket) refill(now time.Time) {
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.capacity/60)
+	b.last = now
+}
+
+// delay 获取 n 个令牌还需等待的时间
+func (b *bucket) delay(n float64) time.Duration {
+	if b.tokens >= n {
+		return 0
+	}
+	return time.Duration((n - b.tokens) / b.capacity * 60 * float64(time.Second))
+}
+
+// limitedTransport 所有 AI 请求共用的 HTTP 中间件：
+// 按每分钟请求数和 tokens 数限流，对 429 / 5xx / 网络错误 / 超时按指数退避（带抖动）重试，并遵循 Retry-After。
+// 超时按每次尝试计算（排队等待令牌和退避的时间不计入）：等待响应头或两次读到响应体数据之间超过 timeout 即中止，
+// 因此同样适用于流式响应；请求自身的 context 只用于取消
+type limitedTransport struct {
+	base http.RoundTripper
+
+	mu         sync.Mutex
+	requests   *bucket
+	tokens     *bucket
+	maxRetries int
+	timeout    time.Duration
+}
+
+func newLimitedTransport() *limitedTransport {
+	return &limitedTransport{base: http.DefaultTransport, maxRetries: DefaultMaxRetries, timeout: DefaultTimeoutSeconds * time.Second}
+}
+
+// configure 更新限流、重试和超时设置（保存 AI 配置后调用）
+func (t *limitedTransport) configure(rpm, tpm, maxRetries, timeoutSeconds int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.requests = newBucket(rpm)
+	t.tokens = newBucket(tpm)
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	t.maxRetries = maxRetries
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = DefaultTimeoutSeconds
+	}
+	t.timeout = time.Duration(timeoutSeconds) * time.Second
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+
+	ctx := req.Context()
+	t.mu.Lock()
+	maxRetries, timeout := t.maxRetries, t.timeout
+	t.mu.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		if err := t.wait(ctx, estimateRequestTokens(body)); err != nil {
+			return nil, err
+		}
+
+		attemptCtx, cancel := context.WithCancelCause(ctx)
+		timer := time.AfterFunc(timeout, func() { cancel(errAttemptTimeout) })
+		r := req.Clone(attemptCtx)
+		if body != nil {
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+		}
+		resp, err := t.base.RoundTrip(r)
+		if err != nil && errors.Is(context.Cause(attemptCtx), errAttemptTimeout) {
+			err = fmt.Errorf("%w after %s", errAttemptTimeout, timeout)
+		}
+
+		if !retryable(resp, err) || attempt >= maxRetries || ctx.Err() != nil {
+			if err != nil {
+				timer.Stop()
+				cancel(nil)
+				return resp, err
+			}
+			resp.Body = &idleTimeoutBody{ReadCloser: resp.Body, ctx: attemptCtx, cancel: cancel, timer: timer, timeout: timeout}
+			return resp, nil
+		}
+
+		delay := backoff(attempt)
+		if resp != nil {
+			if d, ok := retryAfter(resp.Header); ok {
+				delay = d
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			log.Printf("AI request to %s returned %d, retrying in %s (%d/%d)", req.URL.Path, resp.StatusCode, delay.Round(time.Millisecond), attempt+1, maxRetries)
+		} else {
+			log.Printf("AI request to %s failed: %v, retrying in %s (%d/%d)", req.URL.Path, err, delay.Round(time.Millisecond), attempt+1, maxRetries)
+		}
+		timer.Stop()
+		cancel(nil)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// idleTimeoutBody 读取响应体时每读到数据就重新计时，超过 timeout 没有新数据则中止请求；关闭时释放计时器
+type idleTimeoutBody struct {
+	io.ReadCloser
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func (b *idleTimeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.timer.Reset(b.timeout)
+	}
+	if err != nil && err != io.EOF && errors.Is(context.Cause(b.ctx), errAttemptTimeout) {
+		err = fmt.Errorf("%w: no data for %s", errAttemptTimeout, b.timeout)
+	}
+	return n, err
+}
+
+func (b *idleTimeoutBody) Close() error {
+	b.timer.Stop()
+	err := b.ReadCloser.Close()
+	b.cancel(nil)
+	return err
+}
+
+// wait 等待请求数和 tokens 令牌都足够后一起扣除
+func (t *limitedTransport) wait(ctx context.Context, tokens int) error {
+	for {
+		t.mu.Lock()
+		now := time.Now()
+		var delay time.Duration
+		if t.requests != nil {
+			t.requests.refill(now)
+			delay = t.requests.delay(1)
+		}
+		if t.tokens != nil {
+			t.tokens.refill(now)
+			// 单次请求超过每分钟上限时，按上限计算，避免永远等待
+			n := math.Min(float64(tokens), t.tokens.capacity)
+			if d := t.tokens.delay(n); d > delay {
+				delay = d
+			}
+			if delay == 0 {
+				t.tokens.tokens -= n
+			}
+		}
+		if delay == 0 && t.requests != nil {
+			t.requests.tokens--
+		}
+		t.mu.Unlock()
+
+		if delay == 0 {
+			return nil
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// estimateRequestTokens 粗略估算请求的 tokens 数（按请求体字节数）
+func estimateRequestTokens(body []byte) int {
+	return len(body)/3 + 1
+}
+
+// retryable 是否需要重试：429、5xx 和网络错误（不含主动取消）
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// backoff 指数退避（1s、2s、4s…），在 [d/2, d) 内随机抖动
+func backoff(attempt int) time.Duration {
+	d := time.Second << uint(attempt)
+	if d > maxBackoff || d <= 0 {
+		d = maxBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)))
+}
+
+// retryAfter 解析 Retry-After（秒数或 HTTP 日期）及 OpenAI 的 retry-after-ms
+func retryAfter(h http.Header) (time.Duration, bool) {
+	if ms, err := strconv.ParseFloat(h.Get("retry-after-ms"), 64); err == nil && ms >= 0 {
+		return capDelay(time.Duration(ms * float64(time.Millisecond))), true
+	}
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseFloat(v, 64); err == nil && secs >= 0 {
+		return capDelay(time.Duration(secs * float64(time.Second))), true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return capDelay(time.Until(at)), true
+	}
+	return 0, false
+}
+
+func capDelay(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	if d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
+
+// isRateLimited 错误是否为服务商限流（重试后仍然 429）
+func isRateLimited(err error) bool {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.HTTPStatusCode == http.StatusTooManyRequests
+	}
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.HTTPStatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// isBadRequest 错误是否为 400（通常是不支持的参数）
+func isBadRequest(err error) bool {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.HTTPStatusCode == http.StatusBadRequest
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   time.Duration
+		ok     bool
+	}{
+		{"none", nil, 0, false},
+		{"seconds", map[string]string{"Retry-After": "3"}, 3 * time.Second, true},
+		{"fractional seconds", map[string]string{"Retry-After": "1.5"}, 1500 * time.Millisecond, true},
+		{"milliseconds take precedence", map[string]string{"Retry-After": "3", "retry-after-ms": "250"}, 250 * time.Millisecond, true},
+		{"capped", map[string]string{"Retry-After": "3600"}, maxBackoff, true},
+		{"past date", map[string]string{"Retry-After": "Mon, 02 Jan 2006 15:04:05 GMT"}, 0, true},
+		{"invalid", map[string]string{"Retry-After": "soon"}, 0, false},
+		{"negative", map[string]string{"Retry-After": "-1"}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for k, v := range tt.header {
+				h.Set(k, v)
+			}
+			got, ok := retryAfter(h)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("retryAfter(%v) = %v, %v; want %v, %v", tt.header, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+
+	h := http.Header{}
+	h.Set("Retry-After", time.Now().Add(10*time.Second).UTC().Format(http.TimeFormat))
+	if d, ok := retryAfter(h); !ok || d <= 8*time.Second || d > 10*time.Second {
+		t.Errorf("retryAfter(future date) = %v, %v", d, ok)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 0; attempt < 70; attempt++ {
+		base := time.Second << uint(attempt)
+		if base > maxBackoff || base <= 0 {
+			base = maxBackoff
+		}
+		for i := 0; i < 20; i++ {
+			if d := backoff(attempt); d < base/2 || d >= base {
+				t.Fatalf("backoff(%d) = %v, want in [%v, %v)", attempt, d, base/2, base)
+			}
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		status int
+		err    error
+		want   bool
+	}{
+		{200, nil, false},
+		{400, nil, false},
+		{429, nil, true},
+		{503, nil, true},
+		{0, errors.New("connection reset"), true},
+		{0, fmt.Errorf("%w after 1s", errAttemptTimeout), true},
+	}
+	for _, tt := range tests {
+		var resp *http.Response
+		if tt.err == nil {
+			resp = &http.Response{StatusCode: tt.status}
+		}
+		if got := retryable(resp, tt.err); got != tt.want {
+			t.Errorf("retryable(%d, %v) = %v, want %v", tt.status, tt.err, got, tt.want)
+		}
+	}
+}
+
+func newTestTransport(timeout time.Duration, maxRetries int) *limitedTransport {
+	tr := newLimitedTransport()
+	tr.maxRetries = maxRetries
+	tr.timeout = timeout
+	return tr
+}
+
+func TestRoundTripRetriesAfterAttemptTimeout(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if calls.Add(1) == 1 {
+			// 第一次请求超时前不响应
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newTestTransport(100*time.Millisecond, 1)}
+	resp, err := client.Post(srv.URL, "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "ok" || calls.Load() != 2 {
+		t.Errorf("body = %q after %d calls, want ok after 2", body, calls.Load())
+	}
+}
+
+func TestRoundTripIdleTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		// 持续输出的流不受超时影响，之后停止输出则中止
+		for i := 0; i < 4; i++ {
+			fmt.Fprintf(w, "chunk%d\n", i)
+			flusher.Flush()
+			time.Sleep(40 * time.Millisecond)
+		}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newTestTransport(100*time.Millisecond, 0)}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if !errors.Is(err, errAttemptTimeout) {
+		t.Fatalf("read error = %v, want errAttemptTimeout", err)
+	}
+	if !strings.Contains(string(body), "chunk3") {
+		t.Errorf("body = %q, want all chunks before the stall", body)
+	}
+}
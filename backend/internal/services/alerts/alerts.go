@@ -0,0 +1,60 @@
+package alerts
+
+import (
+	"log"
+	"time"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// 告警级别
+const (
+	LevelInfo    = "info"
+	LevelWarning = "warning"
+	LevelError   = "error"
+)
+
+// Raise 发出告警；key 相同的告警只记录一次
+func Raise(key, level, message string) {
+	res, err := database.DB.Exec("INSERT OR IGNORE INTO alerts (id, key, level, message, created_at) VALUES (?, ?, ?, ?, ?)",
+		uuid.New().String(), key, level, message, time.Now())
+	if err != nil {
+		log.Printf("Failed to raise alert %s: %v", key, err)
+		return
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		log.Printf("Alert [%s]: %s", level, message)
+	}
+}
+
+// List 告警列表（all 为 false 时只返回未确认的）
+func List(all bool) ([]models.Alert, error) {
+	query := "SELECT id, level, message, acknowledged, created_at FROM alerts"
+	if !all {
+		query += " WHERE acknowledged = 0"
+	}
+	rows, err := database.DB.Query(query + " ORDER BY created_at DESC LIMIT 100")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.Alert{}
+	for rows.Next() {
+		var a models.Alert
+		if err := rows.Scan(&a.ID, &a.Level, &a.Message, &a.Acknowledged, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, a)
+	}
+	return list, rows.Err()
+}
+
+// Acknowledge 确认告警
+func Acknowledge(id string) error {
+	_, err := database.DB.Exec("UPDATE alerts SET acknowledged = 1 WHERE id = ?", id)
+	return err
+}
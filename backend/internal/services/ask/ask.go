@@ -0,0 +1,420 @@
+package ask
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/ai"
+	"news-intel-app/internal/services/prompts"
+	"news-intel-app/internal/services/search"
+	"news-intel-app/internal/textutil"
+
+	"github.com/google/uuid"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+const (
+	maxSources       = 8   // 每次回答最多参考的新闻数
+	semanticSources  = 5   // 其中来自语义搜索的条数
+	historyMessages  = 6   // 携带的历史消息条数
+	snippetRunes     = 400 // 每条资料的正文片段长度
+	keywordCandidate = 300 // 关键词检索时扫描的最近新闻数
+)
+
+// ErrConversationNotFound 会话不存在
+var ErrConversationNotFound = errors.New("conversation not found")
+
+var citationPattern = regexp.MustCompile(`\[(\d+)\]`)
+
+// Asker 基于新闻库的问答：检索相关新闻，由 AI 引用资料作答
+type Asker struct {
+	ai     *ai.AIService
+	search *search.Searcher
+}
+
+func New(aiSvc *ai.AIService, searcher *search.Searcher) *Asker {
+	return &Asker{ai: aiSvc, search: searcher}
+}
+
+// Session 一次提问：会话、历史消息和检索到的资料
+type Session struct {
+	ConversationID string
+	Question       string
+	Sources        []models.News
+	history        []models.ConversationMessage
+}
+
+// Citations 资料列表（编号从 1 开始，与提示词中的编号一致）
+func (s *Session) Citations() []models.Citation {
+	citations := make([]models.Citation, len(s.Sources))
+	for i, n := range s.Sources {
+		citations[i] = citation(i+1, n)
+	}
+	return citations
+}
+
+// Prepare 创建或加载会话、保存用户问题并检索资料
+func (a *Asker) Prepare(conversationID, question string) (*Session, error) {
+	sess := &Session{ConversationID: conversationID, Question: question}
+
+	if conversationID == "" {
+		sess.ConversationID = uuid.New().String()
+		_, err := database.DB.Exec("INSERT INTO conversations (id, title, created_at, updated_at) VALUES (?, ?, ?, ?)",
+			sess.ConversationID, textutil.Truncate(question, 50), time.Now(), time.Now())
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		history, err := loadMessages(conversationID)
+		if err != nil {
+			return nil, err
+		}
+		sess.history = history
+	}
+
+	// 追问时结合上一个问题检索，避免“它后来怎样了”之类的问题检索不到
+	query := question
+	for i := len(sess.history) - 1; i >= 0; i-- {
+		if sess.history[i].Role == openai.ChatMessageRoleUser {
+			query = sess.history[i].Content + " " + question
+			break
+		}
+	}
+
+	sources, err := a.Retrieve(query)
+	if err != nil {
+		return nil, err
+	}
+	sess.Sources = sources
+
+	if err := saveMessage(sess.ConversationID, openai.ChatMessageRoleUser, question, nil); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// Answer 生成回答（流式回调 onDelta），保存带引用的回答消息
+func (a *Asker) Answer(ctx context.Context, sess *Session, onDelta func(string) error) (*models.ConversationMessage, error) {
+	system, err := systemPrompt(sess.Sources)
+	if err != nil {
+		return nil, err
+	}
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: system},
+	}
+	history := sess.history
+	if len(history) > historyMessages {
+		history = history[len(history)-historyMessages:]
+	}
+	for _, m := range history {
+		messages = append(messages, openai.ChatCompletionMessage{Role: m.Role, Content: m.Content})
+	}
+	messages = append(messages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: sess.Question})
+
+	answer, err := a.ai.ChatStream(ctx, messages, onDelta)
+	if err != nil && answer == "" {
+		return nil, err
+	}
+
+	msg := &models.ConversationMessage{
+		Role:      openai.ChatMessageRoleAssistant,
+		Content:   answer,
+		Citations: citationsFor(answer, sess.Sources),
+		CreatedAt: time.Now(),
+	}
+	if saveErr := saveMessage(sess.ConversationID, msg.Role, msg.Content, msg.Citations); saveErr != nil {
+		log.Printf("Failed to save answer: %v", saveErr)
+	}
+	return msg, err
+}
+
+// Retrieve 检索与问题相关的新闻：语义搜索结果在前，关键词匹配补足
+func (a *Asker) Retrieve(query string) ([]models.News, error) {
+	var ids []string
+	seen := make(map[string]bool)
+
+	if hits, err := a.search.Semantic(query, semanticSources); err != nil {
+		log.Printf("Semantic retrieval failed, using keywords only: %v", err)
+	} else {
+		for _, hit := range hits {
+			ids = append(ids, hit.NewsID)
+			seen[hit.NewsID] = true
+		}
+	}
+
+	keywordIDs, err := keywordSearch(query, maxSources)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range keywordIDs {
+		if len(ids) >= maxSources {
+			break
+		}
+		if !seen[id] {
+			ids = append(ids, id)
+			seen[id] = true
+		}
+	}
+
+	return loadNews(ids)
+}
+
+// keywordSearch 按问题中的关键词匹配最近的新闻，命中词越多越靠前
+func keywordSearch(query string, limit int) ([]string, error) {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, t := range textutil.Tokenize(query) {
+		if !seen[t] {
+			seen[t] = true
+			terms = append(terms, t)
+		}
+	}
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	var conditions []string
+	var args []interface{}
+	for _, t := range terms {
+		conditions = append(conditions, "LOWER(title || ' ' || COALESCE(trans_title, '') || ' ' || COALESCE(summary, '') || ' ' || COALESCE(trans_summary, '')) LIKE ?")
+		args = append(args, "%"+t+"%")
+	}
+	args = append(args, keywordCandidate)
+
+	rows, err := database.DB.Query(`
+		SELECT id, LOWER(title || ' ' || COALESCE(trans_title, '') || ' ' || COALESCE(summary, '') || ' ' || COALESCE(trans_summary, ''))
+		FROM news WHERE is_filtered = 0 AND (`+strings.Join(conditions, " OR ")+`)
+		ORDER BY created_at DESC LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type scored struct {
+		id    string
+		score int
+	}
+	var results []scored
+	for rows.Next() {
+		var id, text string
+		if err := rows.Scan(&id, &text); err != nil {
+			return nil, err
+		}
+		score := 0
+		for _, t := range terms {
+			if strings.Contains(text, t) {
+				score++
+			}
+		}
+		results = append(results, scored{id, score})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 同分时保持时间倒序
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+	var ids []string
+	for i := 0; i < len(results) && i < limit; i++ {
+		ids = append(ids, results[i].id)
+	}
+	return ids, nil
+}
+
+func loadNews(ids []string) ([]models.News, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT id, title, COALESCE(trans_title, ''), COALESCE(summary, ''), COALESCE(trans_summary, ''), COALESCE(content, ''), url, source, published_at
+		FROM news WHERE id IN (`+strings.Join(placeholders, ",")+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[string]models.News)
+	for rows.Next() {
+		var n models.News
+		var publishedAt sql.NullTime
+		if err := rows.Scan(&n.ID, &n.Title, &n.TransTitle, &n.Summary, &n.TransSummary, &n.Content, &n.URL, &n.Source, &publishedAt); err != nil {
+			return nil, err
+		}
+		if publishedAt.Valid {
+			n.PublishedAt = publishedAt.Time
+		}
+		byID[n.ID] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	news := make([]models.News, 0, len(ids))
+	for _, id := range ids {
+		if n, ok := byID[id]; ok {
+			news = append(news, n)
+		}
+	}
+	return news, nil
+}
+
+func systemPrompt(sources []models.News) (string, error) {
+	var sb strings.Builder
+	for i, n := range sources {
+		title := n.Title
+		if n.TransTitle != "" {
+			title = n.TransTitle + " / " + n.Title
+		}
+		date := ""
+		if !n.PublishedAt.IsZero() {
+			date = "，" + n.PublishedAt.Format("2006-01-02")
+		}
+		body := n.TransSummary
+		if body == "" {
+			body = n.Summary
+		}
+		if body == "" {
+			body = n.Content
+		}
+		sb.WriteString(fmt.Sprintf("\n\n[%d] %s（%s%s）\n%s", i+1, title, n.Source, date,
+			textutil.Truncate(textutil.StripHTML(body), snippetRunes)))
+	}
+	return prompts.Render(prompts.Ask, prompts.Data{Items: sb.String(), Count: len(sources)})
+}
+
+// citationsFor 解析回答中的 [n] 引用；回答未标注任何引用时列出全部资料
+func citationsFor(answer string, sources []models.News) []models.Citation {
+	var citations []models.Citation
+	seen := make(map[int]bool)
+	for _, m := range citationPattern.FindAllStringSubmatch(answer, -1) {
+		idx, _ := strconv.Atoi(m[1])
+		if idx < 1 || idx > len(sources) || seen[idx] {
+			continue
+		}
+		seen[idx] = true
+		citations = append(citations, citation(idx, sources[idx-1]))
+	}
+	if len(citations) > 0 {
+		sort.Slice(citations, func(i, j int) bool { return citations[i].Index < citations[j].Index })
+		return citations
+	}
+	for i, n := range sources {
+		citations = append(citations, citation(i+1, n))
+	}
+	return citations
+}
+
+func citation(idx int, n models.News) models.Citation {
+	title := n.TransTitle
+	if title == "" {
+		title = n.Title
+	}
+	return models.Citation{Index: idx, NewsID: n.ID, Title: title, URL: n.URL}
+}
+
+func saveMessage(conversationID, role, content string, citations []models.Citation) error {
+	var citationsJSON []byte
+	if len(citations) > 0 {
+		citationsJSON, _ = json.Marshal(citations)
+	}
+	now := time.Now()
+	_, err := database.DB.Exec(`INSERT INTO conversation_messages (id, conversation_id, role, content, citations, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`, uuid.New().String(), conversationID, role, content, string(citationsJSON), now)
+	if err != nil {
+		return err
+	}
+	_, err = database.DB.Exec("UPDATE conversations SET updated_at = ? WHERE id = ?", now, conversationID)
+	return err
+}
+
+func loadMessages(conversationID string) ([]models.ConversationMessage, error) {
+	var exists int
+	database.DB.QueryRow("SELECT COUNT(*) FROM conversations WHERE id = ?", conversationID).Scan(&exists)
+	if exists == 0 {
+		return nil, ErrConversationNotFound
+	}
+
+	rows, err := database.DB.Query(`SELECT id, role, COALESCE(content, ''), COALESCE(citations, ''), created_at
+		FROM conversation_messages WHERE conversation_id = ? ORDER BY created_at ASC`, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []models.ConversationMessage
+	for rows.Next() {
+		var m models.ConversationMessage
+		var citations string
+		if err := rows.Scan(&m.ID, &m.Role, &m.Content, &citations, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		if citations != "" {
+			json.Unmarshal([]byte(citations), &m.Citations)
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+// List 最近的会话
+func List(limit int) ([]models.Conversation, error) {
+	rows, err := database.DB.Query("SELECT id, COALESCE(title, ''), created_at, updated_at FROM conversations ORDER BY updated_at DESC LIMIT ?", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.Conversation{}
+	for rows.Next() {
+		var conv models.Conversation
+		if err := rows.Scan(&conv.ID, &conv.Title, &conv.CreatedAt, &conv.UpdatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, conv)
+	}
+	return list, rows.Err()
+}
+
+// Get 会话详情（含全部消息）
+func Get(id string) (*models.Conversation, error) {
+	var conv models.Conversation
+	err := database.DB.QueryRow("SELECT id, COALESCE(title, ''), created_at, updated_at FROM conversations WHERE id = ?", id).
+		Scan(&conv.ID, &conv.Title, &conv.CreatedAt, &conv.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrConversationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if conv.Messages, err = loadMessages(id); err != nil {
+		return nil, err
+	}
+	return &conv, nil
+}
+
+// Delete 删除会话及其消息
+func Delete(id string) error {
+	if _, err := database.DB.Exec("DELETE FROM conversation_messages WHERE conversation_id = ?", id); err != nil {
+		return err
+	}
+	_, err := database.DB.Exec("DELETE FROM conversations WHERE id = ?", id)
+	return err
+}
@@ -61,6 +61,7 @@ func (c *Collector) CollectRSS(source *models.NewsSource) ([]models.News, error)
 			Content:     item.Description,
 			URL:         item.Link,
 			Source:      source.Name,
+			SourceID:    source.ID,
 			Category:    source.Category,
 			ImageURL:    imageURL,
 			Author:      author,
@@ -76,8 +77,8 @@ func (c *Collector) CollectRSS(source *models.NewsSource) ([]models.News, error)
 // SaveNews 保存新闻到数据库，返回新保存的新闻列表
 func (c *Collector) SaveNews(news []models.News) ([]models.News, error) {
 	stmt, err := database.DB.Prepare(`
-		INSERT OR IGNORE INTO news (id, title, content, summary, url, source, category, image_url, author, published_at, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT OR IGNORE INTO news (id, title, content, summary, url, source, source_id, category, image_url, author, published_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return nil, err
@@ -86,7 +87,7 @@ func (c *Collector) SaveNews(news []models.News) ([]models.News, error) {
 
 	var savedNews []models.News
 	for _, n := range news {
-		result, err := stmt.Exec(n.ID, n.Title, n.Content, n.Summary, n.URL, n.Source, n.Category, n.ImageURL, n.Author, n.PublishedAt, n.CreatedAt)
+		result, err := stmt.Exec(n.ID, n.Title, n.Content, n.Summary, n.URL, n.Source, n.SourceID, n.Category, n.ImageURL, n.Author, n.PublishedAt, n.CreatedAt)
 		if err != nil {
 			log.Printf("Failed to save news: %v", err)
 			continue
@@ -0,0 +1,274 @@
+package enrichment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+)
+
+// 字段类型
+const (
+	TypeString  = "string"  // 文本
+	TypeNumber  = "number"  // 数字
+	TypeBoolean = "boolean" // 是 / 否
+	TypeList    = "list"    // 文本列表
+)
+
+const (
+	maxFields      = 20  // 最多定义的字段数
+	maxInstruction = 500 // 提取说明的最大长度
+	maxValueRunes  = 200 // 单个文本值的最大长度
+	maxListItems   = 20  // 列表字段最多保留的值
+)
+
+// namePattern 字段名需能在模板中以 .Fields.<name> 引用
+var namePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,39}$`)
+
+var (
+	ErrUnknownField = errors.New("unknown enrichment field")
+)
+
+// ValidType 是否为支持的字段类型
+func ValidType(t string) bool {
+	switch t {
+	case TypeString, TypeNumber, TypeBoolean, TypeList:
+		return true
+	}
+	return false
+}
+
+// GetFields 自定义提取字段（未定义时为空列表）
+func GetFields() []models.EnrichmentField {
+	fields := []models.EnrichmentField{}
+	var value string
+	database.DB.QueryRow("SELECT value FROM settings WHERE key = 'enrichment_fields'").Scan(&value)
+	if value != "" {
+		json.Unmarshal([]byte(value), &fields)
+	}
+	return fields
+}
+
+// SaveFields 校验并保存自定义提取字段（整体替换）
+func SaveFields(fields []models.EnrichmentField) error {
+	if len(fields) > maxFields {
+		return fmt.Errorf("at most %d fields", maxFields)
+	}
+	seen := make(map[string]bool)
+	for i := range fields {
+		f := &fields[i]
+		f.Name = strings.TrimSpace(f.Name)
+		f.Instruction = strings.TrimSpace(f.Instruction)
+		if !namePattern.MatchString(f.Name) {
+			return fmt.Errorf("invalid field name %q: must start with a letter and contain only letters, digits and underscores", f.Name)
+		}
+		if seen[strings.ToLower(f.Name)] {
+			return fmt.Errorf("duplicate field name %q", f.Name)
+		}
+		seen[strings.ToLower(f.Name)] = true
+		if !ValidType(f.Type) {
+			return fmt.Errorf("field %q: type must be string, number, boolean or list", f.Name)
+		}
+		if f.Instruction == "" {
+			return fmt.Errorf("field %q: instruction is required", f.Name)
+		}
+		if len([]rune(f.Instruction)) > maxInstruction {
+			return fmt.Errorf("field %q: instruction is too long", f.Name)
+		}
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	_, err = database.DB.Exec("INSERT OR REPLACE INTO settings (key, value) VALUES ('enrichment_fields', ?)", string(data))
+	return err
+}
+
+// Find 按名称查找字段（不区分大小写）
+func Find(fields []models.EnrichmentField, name string) (models.EnrichmentField, bool) {
+	for _, f := range fields {
+		if strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+	return models.EnrichmentField{}, false
+}
+
+// Normalize 只保留已定义的字段并按类型转换 AI 返回的值；无法转换或为空的值丢弃，没有任何值时返回 nil
+func Normalize(fields []models.EnrichmentField, raw map[string]interface{}) map[string]interface{} {
+	if len(fields) == 0 || len(raw) == 0 {
+		return nil
+	}
+	values := make(map[string]interface{})
+	for _, f := range fields {
+		v, ok := raw[f.Name]
+		if !ok {
+			continue
+		}
+		if v = convert(f.Type, v); v != nil {
+			values[f.Name] = v
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+func convert(fieldType string, v interface{}) interface{} {
+	switch fieldType {
+	case TypeString:
+		if s := text(v); s != "" {
+			return s
+		}
+	case TypeNumber:
+		switch x := v.(type) {
+		case float64:
+			if !math.IsNaN(x) && !math.IsInf(x, 0) {
+				return x
+			}
+		case string:
+			// 兼容 "1,200" 之类带千分位的数字
+			if f, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(x), ",", ""), 64); err == nil {
+				return f
+			}
+		}
+	case TypeBoolean:
+		switch x := v.(type) {
+		case bool:
+			return x
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(x)); err == nil {
+				return b
+			}
+		}
+	case TypeList:
+		var items []interface{}
+		switch x := v.(type) {
+		case []interface{}:
+			items = x
+		case string:
+			for _, s := range strings.Split(x, ",") {
+				items = append(items, s)
+			}
+		}
+		seen := make(map[string]bool)
+		list := []string{}
+		for _, item := range items {
+			s := text(item)
+			if s == "" || seen[strings.ToLower(s)] || len(list) >= maxListItems {
+				continue
+			}
+			seen[strings.ToLower(s)] = true
+			list = append(list, s)
+		}
+		if len(list) > 0 {
+			return list
+		}
+	}
+	return nil
+}
+
+// text 文本值（数字转为字符串），去除首尾空白并限制长度
+func text(v interface{}) string {
+	var s string
+	switch x := v.(type) {
+	case string:
+		s = x
+	case float64:
+		s = strconv.FormatFloat(x, 'f', -1, 64)
+	default:
+		return ""
+	}
+	s = strings.TrimSpace(s)
+	if r := []rune(s); len(r) > maxValueRunes {
+		s = string(r[:maxValueRunes])
+	}
+	return s
+}
+
+// Instruction 批量翻译提示词中的字段提取说明
+func Instruction(fields []models.EnrichmentField) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	typeNames := map[string]string{TypeString: "文本", TypeNumber: "数字", TypeBoolean: "true/false", TypeList: "文本数组"}
+	var sb strings.Builder
+	sb.WriteString("另外，请为每条新闻增加 \"fields\" 对象，包含以下字段，新闻中没有相关信息时该字段为 null：\n")
+	for _, f := range fields {
+		sb.WriteString(fmt.Sprintf("- %s（%s）：%s\n", f.Name, typeNames[f.Type], f.Instruction))
+	}
+	return sb.String()
+}
+
+// Encode 字段值序列化为 JSON 存入 news.fields（没有值时为空字符串）
+func Encode(values map[string]interface{}) string {
+	if len(values) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(values)
+	return string(data)
+}
+
+// Decode 解析 news.fields
+func Decode(s string) map[string]interface{} {
+	if s == "" {
+		return nil
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &values); err != nil {
+		return nil
+	}
+	return values
+}
+
+// FilterClause 按字段值筛选新闻的 SQL 条件（全部命中）。filters 为字段名到值的映射：
+// 值为 * 时只要求字段有值；文本不区分大小写完全匹配；列表包含该值即可
+func FilterClause(fields []models.EnrichmentField, filters map[string]string) (string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+	for name, value := range filters {
+		f, ok := Find(fields, name)
+		if !ok {
+			return "", nil, fmt.Errorf("%w: %s", ErrUnknownField, name)
+		}
+		path := "$." + f.Name
+		if value == "*" {
+			conditions = append(conditions, "json_extract(news.fields, ?) IS NOT NULL")
+			args = append(args, path)
+			continue
+		}
+		switch f.Type {
+		case TypeString:
+			conditions = append(conditions, "LOWER(json_extract(news.fields, ?)) = LOWER(?)")
+			args = append(args, path, value)
+		case TypeNumber:
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return "", nil, fmt.Errorf("field %s: %q is not a number", f.Name, value)
+			}
+			conditions = append(conditions, "json_extract(news.fields, ?) = ?")
+			args = append(args, path, n)
+		case TypeBoolean:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return "", nil, fmt.Errorf("field %s: %q is not a boolean", f.Name, value)
+			}
+			conditions = append(conditions, "json_extract(news.fields, ?) = ?")
+			args = append(args, path, b)
+		case TypeList:
+			conditions = append(conditions, "EXISTS (SELECT 1 FROM json_each(news.fields, ?) WHERE LOWER(json_each.value) = LOWER(?))")
+			args = append(args, path, value)
+		}
+	}
+	if len(conditions) == 0 {
+		return "", nil, nil
+	}
+	return strings.Join(conditions, " AND "), args, nil
+}
@@ -0,0 +1,173 @@
+package entities
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"unicode"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TypeOrganization = "organization"
+	TypePerson       = "person"
+	TypeProduct      = "product"
+	TypeLocation     = "location"
+)
+
+var ErrEntityNotFound = errors.New("entity not found")
+
+// orgSuffixes 组织名称中归一化时忽略的后缀
+var orgSuffixes = []string{"inc", "incorporated", "corp", "corporation", "co", "company", "ltd", "limited", "llc", "plc", "gmbh", "ag", "sa", "group", "holdings"}
+
+// orgSuffixesCJK 中文组织后缀（长的在前）
+var orgSuffixesCJK = []string{"股份有限公司", "有限公司", "集团", "公司"}
+
+// ValidType 是否为支持的实体类型
+func ValidType(t string) bool {
+	switch t {
+	case TypeOrganization, TypePerson, TypeProduct, TypeLocation:
+		return true
+	}
+	return false
+}
+
+// NormalizeAlias 归一化实体名称，用于别名匹配（忽略大小写、标点和组织后缀）
+func NormalizeAlias(name, entityType string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(r)
+		case r == '&' || r == '+':
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune(' ')
+		}
+	}
+	words := strings.Fields(sb.String())
+
+	if entityType == TypeOrganization {
+		for len(words) > 1 && isOrgSuffix(words[len(words)-1]) {
+			words = words[:len(words)-1]
+		}
+		if len(words) == 1 {
+			for _, suffix := range orgSuffixesCJK {
+				if w := strings.TrimSuffix(words[0], suffix); w != words[0] && w != "" {
+					words[0] = w
+					break
+				}
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func isOrgSuffix(w string) bool {
+	for _, suffix := range orgSuffixes {
+		if w == suffix {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve 根据别名查找实体，不存在时创建
+func Resolve(name, entityType string) (string, error) {
+	name = strings.TrimSpace(name)
+	alias := NormalizeAlias(name, entityType)
+
+	var id string
+	err := database.DB.QueryRow("SELECT entity_id FROM entity_aliases WHERE alias = ? AND type = ?", alias, entityType).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	if err != sql.ErrNoRows {
+		return "", err
+	}
+
+	id = uuid.New().String()
+	if _, err := database.DB.Exec("INSERT OR IGNORE INTO entities (id, name, type) VALUES (?, ?, ?)", id, name, entityType); err != nil {
+		return "", err
+	}
+	// 同名实体已存在（别名被删除等情况）
+	if err := database.DB.QueryRow("SELECT id FROM entities WHERE name = ? AND type = ?", name, entityType).Scan(&id); err != nil {
+		return "", err
+	}
+	if err := AddAlias(id, name, entityType); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// AddAlias 为实体添加别名；别名已指向其他实体时改为指向该实体
+func AddAlias(entityID, alias, entityType string) error {
+	_, err := database.DB.Exec("INSERT OR REPLACE INTO entity_aliases (alias, type, entity_id) VALUES (?, ?, ?)",
+		NormalizeAlias(alias, entityType), entityType, entityID)
+	return err
+}
+
+// SaveNewsEntities 写入新闻提到的实体（覆盖旧数据）
+func SaveNewsEntities(newsID string, mentions []models.EntityMention) error {
+	if _, err := database.DB.Exec("DELETE FROM news_entities WHERE news_id = ?", newsID); err != nil {
+		return err
+	}
+	for _, m := range mentions {
+		entityType := strings.ToLower(strings.TrimSpace(m.Type))
+		if strings.TrimSpace(m.Name) == "" || !ValidType(entityType) {
+			continue
+		}
+		if NormalizeAlias(m.Name, entityType) == "" {
+			continue
+		}
+		id, err := Resolve(m.Name, entityType)
+		if err != nil {
+			return err
+		}
+		if _, err := database.DB.Exec("INSERT OR IGNORE INTO news_entities (news_id, entity_id) VALUES (?, ?)", newsID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Merge 将 sourceID 实体合并到 targetID（迁移别名和新闻关联）；任一实体不存在时返回 ErrEntityNotFound
+func Merge(targetID, sourceID string) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, id := range []string{targetID, sourceID} {
+		var exists string
+		err := tx.QueryRow("SELECT id FROM entities WHERE id = ?", id).Scan(&exists)
+		if err == sql.ErrNoRows {
+			return ErrEntityNotFound
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	stmts := []string{
+		"UPDATE entity_aliases SET entity_id = ? WHERE entity_id = ?",
+		"UPDATE OR IGNORE news_entities SET entity_id = ? WHERE entity_id = ?",
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt, targetID, sourceID); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM news_entities WHERE entity_id = ?", sourceID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM entities WHERE id = ?", sourceID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
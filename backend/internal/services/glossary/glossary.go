@@ -0,0 +1,273 @@
+package glossary
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/textutil"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotFound = errors.New("glossary term not found")
+	ErrExists   = errors.New("glossary term already exists for this language")
+)
+
+// Glossary 某一目标语言适用的术语（含 lang 为空的通用术语）
+type Glossary struct {
+	Lang  string
+	Terms []models.GlossaryTerm
+}
+
+// Load 加载目标语言的术语表；同一术语同时有通用和指定语言的条目时以指定语言为准
+func Load(lang string) (*Glossary, error) {
+	rows, err := database.DB.Query(`
+		SELECT id, lang, term, translation, keep_original, note, created_at FROM glossary
+		WHERE lang = ? OR lang = '' ORDER BY lang DESC, LENGTH(term) DESC
+	`, lang)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	g := &Glossary{Lang: lang}
+	seen := make(map[string]bool)
+	for rows.Next() {
+		t, err := scanTerm(rows)
+		if err != nil {
+			return nil, err
+		}
+		if key := strings.ToLower(t.Term); !seen[key] {
+			seen[key] = true
+			g.Terms = append(g.Terms, t)
+			termPattern(t.Term)
+		}
+	}
+	return g, rows.Err()
+}
+
+// Match 返回在任一文本中出现的术语
+func (g *Glossary) Match(texts ...string) []models.GlossaryTerm {
+	if g == nil || len(g.Terms) == 0 {
+		return nil
+	}
+	var matched []models.GlossaryTerm
+	for _, t := range g.Terms {
+		for _, text := range texts {
+			if containsTerm(text, t.Term) {
+				matched = append(matched, t)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// Instruction 生成注入提示词的术语要求；没有术语时返回空字符串
+func Instruction(terms []models.GlossaryTerm) string {
+	if len(terms) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("术语表（必须严格遵守）：\n")
+	for _, t := range terms {
+		if t.KeepOriginal {
+			sb.WriteString(fmt.Sprintf("- %s → 保留原文 %s，不要翻译\n", t.Term, t.Term))
+		} else {
+			sb.WriteString(fmt.Sprintf("- %s → %s\n", t.Term, t.Translation))
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// Expected 术语在译文中应有的写法
+func Expected(t models.GlossaryTerm) string {
+	if t.KeepOriginal || t.Translation == "" {
+		return t.Term
+	}
+	return t.Translation
+}
+
+// Check 返回原文中出现、但译文未使用指定写法的术语
+func Check(terms []models.GlossaryTerm, source, output string) []models.GlossaryTerm {
+	var violated []models.GlossaryTerm
+	lower := strings.ToLower(output)
+	for _, t := range terms {
+		if !containsTerm(source, t.Term) {
+			continue
+		}
+		if !strings.Contains(lower, strings.ToLower(Expected(t))) {
+			violated = append(violated, t)
+		}
+	}
+	return violated
+}
+
+// Flag 记录译文违反术语表的情况
+func Flag(newsID, lang, operation, output string, violated []models.GlossaryTerm) {
+	for _, t := range violated {
+		log.Printf("Glossary violation (%s, %s): %q should be rendered as %q", operation, lang, t.Term, Expected(t))
+		_, err := database.DB.Exec(`
+			INSERT INTO glossary_violations (id, news_id, lang, operation, term, expected, output, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, uuid.New().String(), newsID, lang, operation, t.Term, Expected(t), textutil.Truncate(output, 300), time.Now())
+		if err != nil {
+			log.Printf("Failed to record glossary violation: %v", err)
+		}
+	}
+}
+
+// patterns 术语的整词匹配正则，Load 时预先编译，键为术语原文
+var patterns sync.Map
+
+// termPattern 返回术语的整词匹配正则；术语首尾都不是字母或数字时返回 nil，按子串匹配
+func termPattern(term string) *regexp.Regexp {
+	if term == "" {
+		return nil
+	}
+	if cached, ok := patterns.Load(term); ok {
+		return cached.(*regexp.Regexp)
+	}
+	var re *regexp.Regexp
+	if isWordChar(term[0]) || isWordChar(term[len(term)-1]) {
+		pattern := regexp.QuoteMeta(term)
+		if isWordChar(term[0]) {
+			pattern = `\b` + pattern
+		}
+		if isWordChar(term[len(term)-1]) {
+			pattern += `\b`
+		}
+		re = regexp.MustCompile("(?i)" + pattern)
+	}
+	patterns.Store(term, re)
+	return re
+}
+
+// containsTerm 判断文本是否包含术语（不区分大小写）；以字母或数字开头结尾的术语按整词匹配
+func containsTerm(text, term string) bool {
+	if term == "" || text == "" {
+		return false
+	}
+	if re := termPattern(term); re != nil {
+		return re.MatchString(text)
+	}
+	return strings.Contains(strings.ToLower(text), strings.ToLower(term))
+}
+
+func isWordChar(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
+
+// List 术语列表，lang 不为空时只返回该语言及通用术语
+func List(lang string) ([]models.GlossaryTerm, error) {
+	query := "SELECT id, lang, term, translation, keep_original, note, created_at FROM glossary"
+	var args []interface{}
+	if lang != "" {
+		query += " WHERE lang = ? OR lang = ''"
+		args = append(args, lang)
+	}
+	rows, err := database.DB.Query(query+" ORDER BY lang, term COLLATE NOCASE", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.GlossaryTerm{}
+	for rows.Next() {
+		t, err := scanTerm(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+// Create 新增术语
+func Create(t *models.GlossaryTerm) error {
+	if exists(t.Lang, t.Term, "") {
+		return ErrExists
+	}
+	t.ID = uuid.New().String()
+	t.CreatedAt = time.Now()
+	_, err := database.DB.Exec(`
+		INSERT INTO glossary (id, lang, term, translation, keep_original, note, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, t.ID, t.Lang, t.Term, t.Translation, t.KeepOriginal, t.Note, t.CreatedAt)
+	return err
+}
+
+// Update 修改术语
+func Update(t models.GlossaryTerm) error {
+	if exists(t.Lang, t.Term, t.ID) {
+		return ErrExists
+	}
+	res, err := database.DB.Exec(`
+		UPDATE glossary SET lang = ?, term = ?, translation = ?, keep_original = ?, note = ? WHERE id = ?
+	`, t.Lang, t.Term, t.Translation, t.KeepOriginal, t.Note, t.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete 删除术语
+func Delete(id string) error {
+	_, err := database.DB.Exec("DELETE FROM glossary WHERE id = ?", id)
+	return err
+}
+
+// Violations 最近的术语违规记录
+func Violations(limit int) ([]models.GlossaryViolation, error) {
+	rows, err := database.DB.Query(`
+		SELECT id, news_id, lang, operation, term, expected, output, created_at
+		FROM glossary_violations ORDER BY created_at DESC LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.GlossaryViolation{}
+	for rows.Next() {
+		var v models.GlossaryViolation
+		if err := rows.Scan(&v.ID, &v.NewsID, &v.Lang, &v.Operation, &v.Term, &v.Expected, &v.Output, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, rows.Err()
+}
+
+// ClearViolations 清空违规记录
+func ClearViolations() error {
+	_, err := database.DB.Exec("DELETE FROM glossary_violations")
+	return err
+}
+
+func exists(lang, term, exceptID string) bool {
+	var n int
+	database.DB.QueryRow("SELECT COUNT(*) FROM glossary WHERE lang = ? AND term = ? COLLATE NOCASE AND id != ?", lang, term, exceptID).Scan(&n)
+	return n > 0
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTerm(row scanner) (models.GlossaryTerm, error) {
+	var t models.GlossaryTerm
+	err := row.Scan(&t.ID, &t.Lang, &t.Term, &t.Translation, &t.KeepOriginal, &t.Note, &t.CreatedAt)
+	return t, err
+}
@@ -0,0 +1,26 @@
+package glossary
+
+import "testing"
+
+func TestContainsTerm(t *testing.T) {
+	tests := []struct {
+		text, term string
+		want       bool
+	}{
+		{"OpenAI released GPT-5 today", "GPT-5", true},
+		{"openai released a model", "OpenAI", true},
+		{"The AIDS epidemic", "AI", false},
+		{"AI chips are scarce", "AI", true},
+		{"Spending on A.I. grows", "A.I.", true},
+		{"Cloud spending on C++ grows", "C++", true},
+		{"苹果发布新手机", "苹果", true},
+		{"Apple released a phone", "苹果", false},
+		{"", "AI", false},
+		{"AI", "", false},
+	}
+	for _, tt := range tests {
+		if got := containsTerm(tt.text, tt.term); got != tt.want {
+			t.Errorf("containsTerm(%q, %q) = %v, want %v", tt.text, tt.term, got, tt.want)
+		}
+	}
+}
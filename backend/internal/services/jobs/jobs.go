@@ -0,0 +1,365 @@
+package jobs
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// 任务状态
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusDone      = "done"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+const (
+	DefaultWorkers     = 2
+	DefaultMaxAttempts = 5
+	pollInterval       = 5 * time.Second
+	retryBaseDelay     = 30 * time.Second
+	retryMaxDelay      = 30 * time.Minute
+)
+
+var (
+	ErrNotFound   = errors.New("job not found")
+	ErrNotAllowed = errors.New("job cannot be changed in its current state")
+)
+
+// Handler 执行一种类型的任务；返回错误时按退避时间重试，超过最大次数后标记为失败
+type Handler func(payload json.RawMessage) error
+
+// Queue 持久化在 SQLite 的任务队列，重启后继续执行未完成的任务
+type Queue struct {
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	wake     chan struct{}
+	stop     chan struct{}
+}
+
+func New() *Queue {
+	return &Queue{
+		handlers: make(map[string]Handler),
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Register 注册任务类型的处理函数（需在 Start 之前调用）
+func (q *Queue) Register(jobType string, h Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = h
+}
+
+// Start 启动 workers；上次退出时仍在执行的任务重新排队
+func (q *Queue) Start(workers int) {
+	res, err := database.DB.Exec("UPDATE jobs SET status = ?, updated_at = ? WHERE status = ?", StatusPending, time.Now(), StatusRunning)
+	if err != nil {
+		log.Printf("Failed to requeue interrupted jobs: %v", err)
+	} else if n, _ := res.RowsAffected(); n > 0 {
+		log.Printf("Requeued %d interrupted jobs", n)
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		go q.worker()
+	}
+	log.Printf("Job queue started with %d workers", workers)
+}
+
+// Stop 停止领取新任务；正在执行的任务下次启动时重新排队
+func (q *Queue) Stop() {
+	close(q.stop)
+}
+
+// Enqueue 新增任务
+func (q *Queue) Enqueue(jobType string, payload interface{}) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return q.insert(jobType, string(data))
+}
+
+// EnqueueOnce 类型和参数都相同的任务已在排队或执行时不再新增，返回已有任务的 ID。
+// 参数不同的任务（如定时采集的 AutoPush）照常新增，避免被合并后丢失
+func (q *Queue) EnqueueOnce(jobType string, payload interface{}) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	var id string
+	err = database.DB.QueryRow("SELECT id FROM jobs WHERE type = ? AND payload = ? AND status IN (?, ?) LIMIT 1",
+		jobType, string(data), StatusPending, StatusRunning).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	if err != sql.ErrNoRows {
+		return "", err
+	}
+	return q.insert(jobType, string(data))
+}
+
+func (q *Queue) insert(jobType, payload string) (string, error) {
+	id := uuid.New().String()
+	now := time.Now()
+	_, err := database.DB.Exec(`
+		INSERT INTO jobs (id, type, payload, status, attempts, max_attempts, next_run_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, 0, ?, ?, ?, ?)
+	`, id, jobType, payload, StatusPending, DefaultMaxAttempts, now, now, now)
+	if err != nil {
+		return "", err
+	}
+	q.notify()
+	return id, nil
+}
+
+// Retry 将失败或已取消的任务重新排队（重置重试次数）
+func (q *Queue) Retry(id string) error {
+	res, err := database.DB.Exec(`
+		UPDATE jobs SET status = ?, attempts = 0, next_run_at = ?, last_error = '', updated_at = ? WHERE id = ? AND status IN (?, ?)
+	`, StatusPending, time.Now(), time.Now(), id, StatusFailed, StatusCancelled)
+	if err != nil {
+		return err
+	}
+	if err := checkChanged(res, id); err != nil {
+		return err
+	}
+	q.notify()
+	return nil
+}
+
+// Cancel 取消排队中的任务（正在执行的任务无法取消）
+func (q *Queue) Cancel(id string) error {
+	res, err := database.DB.Exec("UPDATE jobs SET status = ?, updated_at = ?, finished_at = ? WHERE id = ? AND status = ?",
+		StatusCancelled, time.Now(), time.Now(), id, StatusPending)
+	if err != nil {
+		return err
+	}
+	return checkChanged(res, id)
+}
+
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *Queue) worker() {
+	for {
+		select {
+		case <-q.stop:
+			return
+		default:
+		}
+
+		job, err := claim()
+		if err != nil {
+			log.Printf("Failed to claim job: %v", err)
+		}
+		if job == nil {
+			select {
+			case <-q.wake:
+			case <-time.After(pollInterval):
+			case <-q.stop:
+				return
+			}
+			continue
+		}
+		q.run(job)
+	}
+}
+
+// run 执行任务并记录结果
+func (q *Queue) run(job *models.Job) {
+	q.mu.RLock()
+	h, ok := q.handlers[job.Type]
+	q.mu.RUnlock()
+
+	var err error
+	if !ok {
+		err = fmt.Errorf("unknown job type %q", job.Type)
+		job.Attempts = job.MaxAttempts
+	} else {
+		log.Printf("Running job %s (%s, attempt %d/%d)", job.ID, job.Type, job.Attempts, job.MaxAttempts)
+		err = safeRun(h, json.RawMessage(job.Payload))
+	}
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		_, err = database.DB.Exec("UPDATE jobs SET status = ?, last_error = '', updated_at = ?, finished_at = ? WHERE id = ?",
+			StatusDone, now, now, job.ID)
+	case job.Attempts >= job.MaxAttempts:
+		log.Printf("Job %s (%s) failed permanently: %v", job.ID, job.Type, err)
+		_, err = database.DB.Exec("UPDATE jobs SET status = ?, last_error = ?, updated_at = ?, finished_at = ? WHERE id = ?",
+			StatusFailed, err.Error(), now, now, job.ID)
+	default:
+		next := now.Add(retryDelay(job.Attempts))
+		log.Printf("Job %s (%s) failed, retrying at %s: %v", job.ID, job.Type, next.Format("15:04:05"), err)
+		_, err = database.DB.Exec("UPDATE jobs SET status = ?, last_error = ?, next_run_at = ?, updated_at = ? WHERE id = ?",
+			StatusPending, err.Error(), next, now, job.ID)
+	}
+	if err != nil {
+		log.Printf("Failed to update job %s: %v", job.ID, err)
+	}
+}
+
+// safeRun 执行处理函数，panic 视为失败
+func safeRun(h Handler, payload json.RawMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h(payload)
+}
+
+// retryDelay 第 attempt 次失败后的等待时间（指数退避）
+func retryDelay(attempt int) time.Duration {
+	d := retryBaseDelay
+	for i := 1; i < attempt && d < retryMaxDelay; i++ {
+		d *= 2
+	}
+	if d > retryMaxDelay {
+		d = retryMaxDelay
+	}
+	return d
+}
+
+// claim 领取一个到期的排队任务并标记为执行中
+func claim() (*models.Job, error) {
+	for {
+		var id string
+		err := database.DB.QueryRow("SELECT id FROM jobs WHERE status = ? AND next_run_at <= ? ORDER BY next_run_at LIMIT 1",
+			StatusPending, time.Now()).Scan(&id)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// 条件更新保证多个 worker 不会领取同一任务
+		res, err := database.DB.Exec("UPDATE jobs SET status = ?, attempts = attempts + 1, updated_at = ? WHERE id = ? AND status = ?",
+			StatusRunning, time.Now(), id, StatusPending)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			continue
+		}
+		return Get(id)
+	}
+}
+
+// Get 任务详情
+func Get(id string) (*models.Job, error) {
+	row := database.DB.QueryRow("SELECT "+jobColumns+" FROM jobs WHERE id = ?", id)
+	job, err := scanJob(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return job, err
+}
+
+// List 任务列表（status 为空时返回全部），按创建时间倒序
+func List(status string, limit int) ([]models.Job, error) {
+	query := "SELECT " + jobColumns + " FROM jobs"
+	var args []interface{}
+	if status != "" {
+		query += " WHERE status = ?"
+		args = append(args, status)
+	}
+	args = append(args, limit)
+	rows, err := database.DB.Query(query+" ORDER BY created_at DESC LIMIT ?", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *job)
+	}
+	return list, rows.Err()
+}
+
+// Counts 各状态的任务数
+func Counts() (map[string]int, error) {
+	rows, err := database.DB.Query("SELECT status, COUNT(*) FROM jobs GROUP BY status")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, err
+		}
+		counts[status] = n
+	}
+	return counts, rows.Err()
+}
+
+// Prune 删除完成时间早于 olderThan 的已完成和已取消任务
+func Prune(olderThan time.Duration) (int64, error) {
+	res, err := database.DB.Exec("DELETE FROM jobs WHERE status IN (?, ?) AND finished_at < ?",
+		StatusDone, StatusCancelled, time.Now().Add(-olderThan))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func checkChanged(res sql.Result, id string) error {
+	if n, _ := res.RowsAffected(); n > 0 {
+		return nil
+	}
+	if _, err := Get(id); err != nil {
+		return err
+	}
+	return ErrNotAllowed
+}
+
+const jobColumns = "id, type, payload, status, attempts, max_attempts, next_run_at, COALESCE(last_error, ''), created_at, updated_at, finished_at"
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanJob(row scanner) (*models.Job, error) {
+	var job models.Job
+	var finishedAt sql.NullTime
+	err := row.Scan(&job.ID, &job.Type, &job.Payload, &job.Status, &job.Attempts, &job.MaxAttempts,
+		&job.NextRunAt, &job.LastError, &job.CreatedAt, &job.UpdatedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = finishedAt.Time
+	}
+	return &job, nil
+}
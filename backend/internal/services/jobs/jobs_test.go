@@ -0,0 +1,69 @@
+package jobs
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"news-intel-app/internal/database"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{6, 16 * time.Minute},
+		{7, 30 * time.Minute},
+		{50, 30 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempt); got != tt.want {
+			t.Errorf("retryDelay(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestEnqueueOnceDedupesOnPayload(t *testing.T) {
+	if err := database.Init(filepath.Join(t.TempDir(), "news.db")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.DB.Close() })
+
+	q := New()
+	manual, err := q.EnqueueOnce(TypeCollect, CollectPayload{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := q.EnqueueOnce(TypeCollect, CollectPayload{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != manual {
+		t.Errorf("identical collect was enqueued twice")
+	}
+
+	// 定时采集需要自动推送，不能合并到手动采集中
+	auto, err := q.EnqueueOnce(TypeCollect, CollectPayload{AutoPush: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auto == manual {
+		t.Errorf("auto-push collect was merged into the pending manual collect")
+	}
+
+	if err := q.Cancel(manual); err != nil {
+		t.Fatal(err)
+	}
+	next, err := q.EnqueueOnce(TypeCollect, CollectPayload{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next == manual {
+		t.Errorf("cancelled job was reused")
+	}
+}
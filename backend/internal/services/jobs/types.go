@@ -0,0 +1,26 @@
+package jobs
+
+// 任务类型
+const (
+	TypeCollect   = "collect"   // 采集全部新闻源，新采集的新闻生成翻译任务
+	TypeTranslate = "translate" // 批量翻译指定新闻并移入阅读窗口
+	TypeProcess   = "process"   // 逐条处理未翻译的新闻
+	TypeTrends    = "trends"    // 分析热点趋势并检查关注词
+	TypeStories   = "stories"   // 重新聚类最近的新闻并生成故事摘要
+)
+
+// CollectPayload 采集任务参数
+type CollectPayload struct {
+	AutoPush bool `json:"auto_push"` // 翻译完成后检查自动推送
+}
+
+// TranslatePayload 翻译任务参数；重试时只处理仍未进入阅读窗口的新闻
+type TranslatePayload struct {
+	NewsIDs  []string `json:"news_ids"`
+	AutoPush bool     `json:"auto_push"`
+}
+
+// ProcessPayload 逐条处理任务参数
+type ProcessPayload struct {
+	Limit int `json:"limit"`
+}
@@ -0,0 +1,350 @@
+package preference
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/taxonomy"
+	"news-intel-app/internal/textutil"
+)
+
+// 反馈类型：up 为正向，down 和 not_interested 为负向
+const (
+	FeedbackUp            = "up"
+	FeedbackDown          = "down"
+	FeedbackNotInterested = "not_interested"
+)
+
+// FeedbackColumnSQL 新闻的读者反馈（没有时为空），用于新闻列表查询
+const FeedbackColumnSQL = `COALESCE((SELECT f.label FROM news_feedback f WHERE f.news_id = news.id), '')`
+
+// 默认设置
+const (
+	DefaultThreshold   = 0.2
+	DefaultMinFeedback = 10
+	rescoreDays        = 7 // 重新训练后为最近几天的新闻重新评分
+	topFeatures        = 10
+)
+
+var (
+	ErrInvalidFeedback = errors.New("label must be up, down or not_interested")
+	ErrNewsNotFound    = errors.New("news not found")
+)
+
+// model 朴素贝叶斯模型：每类（0 负向、1 正向）的特征计数，特征在一条新闻中只计一次
+type model struct {
+	counts    [2]map[string]float64
+	totals    [2]float64
+	docs      [2]int
+	vocab     int
+	trainedAt time.Time
+}
+
+var (
+	mu      sync.RWMutex
+	current *model // 反馈不足时为 nil
+)
+
+// GetSettings 读取偏好模型设置
+func GetSettings() models.PreferenceSettings {
+	settings := models.PreferenceSettings{Threshold: DefaultThreshold, MinFeedback: DefaultMinFeedback}
+	var autoFilter, threshold, minFeedback string
+	database.DB.QueryRow("SELECT value FROM settings WHERE key = 'preference_auto_filter'").Scan(&autoFilter)
+	database.DB.QueryRow("SELECT value FROM settings WHERE key = 'preference_threshold'").Scan(&threshold)
+	database.DB.QueryRow("SELECT value FROM settings WHERE key = 'preference_min_feedback'").Scan(&minFeedback)
+	settings.AutoFilter, _ = strconv.ParseBool(autoFilter)
+	if v, err := strconv.ParseFloat(threshold, 64); err == nil {
+		settings.Threshold = v
+	}
+	if v, err := strconv.Atoi(minFeedback); err == nil {
+		settings.MinFeedback = v
+	}
+	return settings
+}
+
+// SaveSettings 保存偏好模型设置
+func SaveSettings(settings models.PreferenceSettings) error {
+	values := map[string]string{
+		"preference_auto_filter":  strconv.FormatBool(settings.AutoFilter),
+		"preference_threshold":    strconv.FormatFloat(settings.Threshold, 'f', -1, 64),
+		"preference_min_feedback": strconv.Itoa(settings.MinFeedback),
+	}
+	for key, value := range values {
+		if _, err := database.DB.Exec("INSERT OR REPLACE INTO settings (key, value) VALUES (?, ?)", key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetFeedback 记录读者对新闻的反馈（覆盖已有反馈）。
+// 不感兴趣的新闻移出阅读窗口；点赞的新闻如果被偏好模型自动过滤则恢复（AI 筛选的不恢复）
+func SetFeedback(newsID, label string) error {
+	switch label {
+	case FeedbackUp, FeedbackDown, FeedbackNotInterested:
+	default:
+		return ErrInvalidFeedback
+	}
+	var n int
+	database.DB.QueryRow("SELECT COUNT(*) FROM news WHERE id = ?", newsID).Scan(&n)
+	if n == 0 {
+		return ErrNewsNotFound
+	}
+
+	_, err := database.DB.Exec(`
+		INSERT INTO news_feedback (news_id, label, created_at) VALUES (?, ?, ?)
+		ON CONFLICT(news_id) DO UPDATE SET label = excluded.label, created_at = excluded.created_at
+	`, newsID, label, time.Now())
+	if err != nil {
+		return err
+	}
+	switch label {
+	case FeedbackNotInterested:
+		_, err = database.DB.Exec("UPDATE news SET in_reading = 0 WHERE id = ?", newsID)
+	case FeedbackUp:
+		_, err = database.DB.Exec("UPDATE news SET is_filtered = 0, pref_filtered = 0 WHERE id = ? AND pref_filtered = 1", newsID)
+	}
+	return err
+}
+
+// DeleteFeedback 撤销反馈；撤销不感兴趣时，已进入过阅读窗口的新闻移回阅读窗口
+func DeleteFeedback(newsID string) error {
+	var label string
+	err := database.DB.QueryRow("SELECT label FROM news_feedback WHERE news_id = ?", newsID).Scan(&label)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := database.DB.Exec("DELETE FROM news_feedback WHERE news_id = ?", newsID); err != nil {
+		return err
+	}
+	if label == FeedbackNotInterested {
+		_, err = database.DB.Exec("UPDATE news SET in_reading = 1 WHERE id = ? AND reading_at IS NOT NULL AND is_filtered = 0", newsID)
+	}
+	return err
+}
+
+// features 新闻的特征：标题分词、来源、分类，withTags 时包括标签（去重）
+func features(n models.News, withTags bool) []string {
+	seen := make(map[string]bool)
+	var list []string
+	add := func(f string) {
+		if !seen[f] {
+			seen[f] = true
+			list = append(list, f)
+		}
+	}
+	for _, token := range textutil.Tokenize(n.Title) {
+		add(token)
+	}
+	if n.Source != "" {
+		add("source:" + n.Source)
+	}
+	if n.Category != "" {
+		add("category:" + n.Category)
+	}
+	if withTags {
+		for _, tag := range textutil.SplitList(n.Tags) {
+			add("tag:" + tag)
+		}
+	}
+	return list
+}
+
+// Train 用全部反馈重新训练模型，并为最近的新闻重新评分；
+// 反馈少于最少反馈数或缺少正向、负向任一类时清空模型
+func Train() error {
+	rows, err := database.DB.Query(`
+		SELECT news.title, COALESCE(news.source, ''), COALESCE(news.category, ''), COALESCE(` + taxonomy.TagsColumnSQL + `, ''), f.label
+		FROM news_feedback f JOIN news ON news.id = f.news_id
+	`)
+	if err != nil {
+		return err
+	}
+	m := &model{counts: [2]map[string]float64{{}, {}}, trainedAt: time.Now()}
+	vocab := make(map[string]bool)
+	for rows.Next() {
+		var n models.News
+		var label string
+		if err := rows.Scan(&n.Title, &n.Source, &n.Category, &n.Tags, &label); err != nil {
+			rows.Close()
+			return err
+		}
+		class := 0
+		if label == FeedbackUp {
+			class = 1
+		}
+		m.docs[class]++
+		for _, f := range features(n, true) {
+			m.counts[class][f]++
+			m.totals[class]++
+			vocab[f] = true
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	m.vocab = len(vocab)
+
+	if m.docs[0]+m.docs[1] < GetSettings().MinFeedback || m.docs[0] == 0 || m.docs[1] == 0 {
+		m = nil
+	}
+	mu.Lock()
+	current = m
+	mu.Unlock()
+	if m == nil {
+		return nil
+	}
+
+	_, err = Rescore("is_filtered = 0 AND created_at > datetime('now', ?)", "-"+strconv.Itoa(rescoreDays)+" days")
+	return err
+}
+
+// weight 特征的对数几率（拉普拉斯平滑），正数表示更可能被点赞
+func (m *model) weight(f string) float64 {
+	pos := (m.counts[1][f] + 1) / (m.totals[1] + float64(m.vocab))
+	neg := (m.counts[0][f] + 1) / (m.totals[0] + float64(m.vocab))
+	return math.Log(pos) - math.Log(neg)
+}
+
+// score 新闻被点赞的概率。两类使用相同的先验，评分只反映内容；训练中未出现过的特征不参与计算。
+// withTags 为 false 时忽略标签特征，用于 AI 打标签之前的评分
+func (m *model) score(n models.News, withTags bool) float64 {
+	var logit float64
+	for _, f := range features(n, withTags) {
+		if m.counts[0][f] == 0 && m.counts[1][f] == 0 {
+			continue
+		}
+		logit += m.weight(f)
+	}
+	return 1 / (1 + math.Exp(-logit))
+}
+
+// Score 新闻的偏好评分；模型未训练时返回 false
+func Score(n models.News) (float64, bool) {
+	mu.RLock()
+	m := current
+	mu.RUnlock()
+	if m == nil {
+		return 0, false
+	}
+	return m.score(n, true), true
+}
+
+// Rescore 为符合条件的新闻重新计算偏好评分，返回评分的条数
+func Rescore(where string, args ...interface{}) (int, error) {
+	rows, err := database.DB.Query(`
+		SELECT id, title, COALESCE(source, ''), COALESCE(category, ''), COALESCE(`+taxonomy.TagsColumnSQL+`, '')
+		FROM news WHERE `+where, args...)
+	if err != nil {
+		return 0, err
+	}
+	var list []models.News
+	for rows.Next() {
+		var n models.News
+		if err := rows.Scan(&n.ID, &n.Title, &n.Source, &n.Category, &n.Tags); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		list = append(list, n)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, n := range list {
+		score, ok := Score(n)
+		if !ok {
+			return count, nil
+		}
+		if _, err := database.DB.Exec("UPDATE news SET pref_score = ? WHERE id = ?", score, n.ID); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Screen 为待翻译的新闻评分；开启自动过滤且模型已训练时，评分低于阈值的新闻标记为已过滤
+// （不再翻译、不进入阅读窗口），返回保留的新闻。
+// 翻译前新闻还没有标签，评分只使用标题、来源和分类，翻译后由 Rescore 按完整特征重新评分
+func Screen(list []models.News) []models.News {
+	mu.RLock()
+	m := current
+	mu.RUnlock()
+	if m == nil {
+		return list
+	}
+	settings := GetSettings()
+	var kept []models.News
+	for _, n := range list {
+		score := m.score(n, false)
+		filtered := settings.AutoFilter && score < settings.Threshold
+		if _, err := database.DB.Exec("UPDATE news SET pref_score = ?, is_filtered = ?, pref_filtered = ? WHERE id = ?", score, filtered, filtered, n.ID); err != nil {
+			kept = append(kept, n)
+			continue
+		}
+		if !filtered {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+// Status 模型状态：反馈数、训练时间和权重最高的特征
+func Status() (models.PreferenceStatus, error) {
+	status := models.PreferenceStatus{PreferenceSettings: GetSettings(), Liked: []models.PreferenceFeature{}, Disliked: []models.PreferenceFeature{}}
+	err := database.DB.QueryRow(`
+		SELECT COALESCE(SUM(CASE WHEN label = ? THEN 1 ELSE 0 END), 0), COALESCE(SUM(CASE WHEN label != ? THEN 1 ELSE 0 END), 0)
+		FROM news_feedback
+	`, FeedbackUp, FeedbackUp).Scan(&status.Positive, &status.Negative)
+	if err != nil && err != sql.ErrNoRows {
+		return status, err
+	}
+
+	mu.RLock()
+	m := current
+	mu.RUnlock()
+	if m == nil {
+		return status, nil
+	}
+	status.Trained = true
+	status.TrainedAt = m.trainedAt
+
+	// 至少出现两次的特征才展示，避免只出现一次的词排在前面
+	seen := make(map[string]bool)
+	var list []models.PreferenceFeature
+	for _, counts := range m.counts {
+		for f := range counts {
+			if seen[f] || m.counts[0][f]+m.counts[1][f] < 2 {
+				continue
+			}
+			seen[f] = true
+			list = append(list, models.PreferenceFeature{Feature: f, Weight: m.weight(f)})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Weight > list[j].Weight })
+	for _, f := range list {
+		if f.Weight > 0 && len(status.Liked) < topFeatures {
+			status.Liked = append(status.Liked, f)
+		}
+	}
+	for i := len(list) - 1; i >= 0; i-- {
+		if list[i].Weight < 0 && len(status.Disliked) < topFeatures {
+			status.Disliked = append(status.Disliked, list[i])
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,207 @@
+package preference
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+)
+
+func setupDB(t *testing.T) {
+	t.Helper()
+	if err := database.Init(filepath.Join(t.TempDir(), "news.db")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		mu.Lock()
+		current = nil
+		mu.Unlock()
+		database.DB.Close()
+	})
+}
+
+func insertNews(t *testing.T, id, title string) {
+	t.Helper()
+	if _, err := database.DB.Exec("INSERT INTO news (id, title, url) VALUES (?, ?, ?)", id, title, "https://example.com/"+id); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newsFlags(t *testing.T, id string) (inReading, isFiltered bool) {
+	t.Helper()
+	if err := database.DB.QueryRow("SELECT in_reading, is_filtered FROM news WHERE id = ?", id).Scan(&inReading, &isFiltered); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+// train 用两条点赞（golang）和两条点踩（market，带 testtag 标签）的反馈训练模型
+func train(t *testing.T) {
+	t.Helper()
+	if err := SaveSettings(models.PreferenceSettings{AutoFilter: true, Threshold: 0.4, MinFeedback: 4}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.DB.Exec("INSERT INTO tags (id, name, status) VALUES ('tt', 'testtag', 'approved')"); err != nil {
+		t.Fatal(err)
+	}
+	feedback := []struct{ id, title, label string }{
+		{"up1", "golang generics", FeedbackUp},
+		{"up2", "golang compiler", FeedbackUp},
+		{"down1", "market update", FeedbackDown},
+		{"down2", "market rally", FeedbackNotInterested},
+	}
+	for _, f := range feedback {
+		insertNews(t, f.id, f.title)
+		if f.label != FeedbackUp {
+			database.DB.Exec("INSERT INTO news_tags (news_id, tag_id) VALUES (?, 'tt')", f.id)
+		}
+		if err := SetFeedback(f.id, f.label); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Train(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTrainAndScore(t *testing.T) {
+	setupDB(t)
+	if _, ok := Score(models.News{Title: "golang tips"}); ok {
+		t.Fatal("Score before training should report an untrained model")
+	}
+	train(t)
+
+	liked, ok := Score(models.News{Title: "golang tips"})
+	if !ok || liked <= 0.5 {
+		t.Errorf("Score(golang) = %v, %v; want > 0.5", liked, ok)
+	}
+	disliked, _ := Score(models.News{Title: "market news"})
+	if disliked >= 0.5 {
+		t.Errorf("Score(market) = %v, want < 0.5", disliked)
+	}
+	unknown, _ := Score(models.News{Title: "weather report"})
+	if unknown != 0.5 {
+		t.Errorf("Score(unseen features) = %v, want 0.5", unknown)
+	}
+	tagged, _ := Score(models.News{Title: "weather report", Tags: "testtag"})
+	if tagged >= 0.5 {
+		t.Errorf("Score(tagged) = %v, want < 0.5", tagged)
+	}
+}
+
+func TestTrainRequiresBothClasses(t *testing.T) {
+	setupDB(t)
+	SaveSettings(models.PreferenceSettings{MinFeedback: 1})
+	insertNews(t, "up1", "golang generics")
+	SetFeedback("up1", FeedbackUp)
+	if err := Train(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Score(models.News{Title: "golang"}); ok {
+		t.Error("model trained without negative feedback")
+	}
+}
+
+func TestScreenIgnoresTags(t *testing.T) {
+	setupDB(t)
+	train(t)
+
+	// 标签特征为负向，但翻译前的评分不使用标签，不应被过滤
+	insertNews(t, "n1", "weather report")
+	database.DB.Exec("INSERT INTO news_tags (news_id, tag_id) VALUES ('n1', 'tt')")
+	// 标题特征为负向，低于阈值被过滤
+	insertNews(t, "n2", "market crash")
+
+	kept := Screen([]models.News{
+		{ID: "n1", Title: "weather report", Tags: "testtag"},
+		{ID: "n2", Title: "market crash"},
+	})
+	if len(kept) != 1 || kept[0].ID != "n1" {
+		t.Fatalf("Screen kept %v, want only n1", kept)
+	}
+	var score float64
+	database.DB.QueryRow("SELECT pref_score FROM news WHERE id = 'n1'").Scan(&score)
+	if score != 0.5 {
+		t.Errorf("screened score = %v, want 0.5", score)
+	}
+	if _, filtered := newsFlags(t, "n2"); !filtered {
+		t.Error("n2 should be filtered")
+	}
+
+	// 打标签后按完整特征重新评分
+	if _, err := Rescore("id = ?", "n1"); err != nil {
+		t.Fatal(err)
+	}
+	database.DB.QueryRow("SELECT pref_score FROM news WHERE id = 'n1'").Scan(&score)
+	if score >= 0.5 {
+		t.Errorf("rescored score = %v, want < 0.5", score)
+	}
+}
+
+func TestFeedbackUpRestoresOnlyPreferenceFiltered(t *testing.T) {
+	setupDB(t)
+	train(t)
+
+	insertNews(t, "pref", "market crash")
+	Screen([]models.News{{ID: "pref", Title: "market crash"}})
+	insertNews(t, "ai", "market crash")
+	database.DB.Exec("UPDATE news SET is_filtered = 1 WHERE id = 'ai'")
+
+	for _, id := range []string{"pref", "ai"} {
+		if err := SetFeedback(id, FeedbackUp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, filtered := newsFlags(t, "pref"); filtered {
+		t.Error("news filtered by the preference model should be restored")
+	}
+	if _, filtered := newsFlags(t, "ai"); !filtered {
+		t.Error("news filtered by AI should stay filtered")
+	}
+}
+
+func TestDeleteFeedbackRestoresReading(t *testing.T) {
+	setupDB(t)
+	insertNews(t, "read", "golang generics")
+	database.DB.Exec("UPDATE news SET in_reading = 1, reading_at = ? WHERE id = 'read'", time.Now())
+	insertNews(t, "unread", "golang compiler")
+
+	for _, id := range []string{"read", "unread"} {
+		if err := SetFeedback(id, FeedbackNotInterested); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if inReading, _ := newsFlags(t, "read"); inReading {
+		t.Fatal("not_interested should remove the news from reading")
+	}
+
+	for _, id := range []string{"read", "unread"} {
+		if err := DeleteFeedback(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if inReading, _ := newsFlags(t, "read"); !inReading {
+		t.Error("undoing not_interested should restore reading")
+	}
+	if inReading, _ := newsFlags(t, "unread"); inReading {
+		t.Error("news never in reading should not be added to reading")
+	}
+	var n int
+	database.DB.QueryRow("SELECT COUNT(*) FROM news_feedback").Scan(&n)
+	if n != 0 {
+		t.Errorf("%d feedback rows left", n)
+	}
+}
+
+func TestSetFeedbackValidation(t *testing.T) {
+	setupDB(t)
+	insertNews(t, "n1", "golang")
+	if err := SetFeedback("n1", "meh"); err != ErrInvalidFeedback {
+		t.Errorf("invalid label error = %v", err)
+	}
+	if err := SetFeedback("missing", FeedbackUp); err != ErrNewsNotFound {
+		t.Errorf("missing news error = %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package prompts
+
+// 内置提示词名称
+const (
+	Translate        = "translate"
+	Summarize        = "summarize"
+	Filter           = "filter"
+	BatchTranslate   = "batch_translate"
+	EmailTemplate    = "email_template"
+	StorySummary     = "story_summary"
+	Briefing         = "briefing"
+	SuggestTags      = "suggest_tags"
+	ExtractEntities  = "extract_entities"
+	Ask              = "ask"
+	TranslateContent = "translate_content"
+	StyledSummary    = "styled_summary"
+	SummarizeSection = "summarize_section"
+	SummarizeMerge   = "summarize_merge"
+)
+
+type builtin struct {
+	Name        string
+	Description string
+	Content     string
+}
+
+// defaults 内置提示词（数据库中未修改过的内置提示词会随版本升级自动更新）
+var defaults = []builtin{
+	{Translate, "单条文本翻译。变量：.Text 待翻译文本，.TargetLang 目标语言代码，.LangName 目标语言名称，.Glossary 术语表要求", `{{if eq .TargetLang "ug"}}将以下文本翻译成维吾尔语(Uyghur)，只返回翻译结果，不要添加任何解释：
+
+{{.Glossary}}{{.Text}}{{else}}将以下文本翻译成{{.LangName}}，只返回翻译结果，不要添加任何解释：
+
+{{.Glossary}}{{.Text}}{{end}}`},
+
+	{Summarize, "单条新闻摘要。变量：.Text 新闻内容，.TargetLang 目标语言代码，.LangName 目标语言名称，.Glossary 术语表要求", `{{if eq .TargetLang "ug"}}为以下新闻生成一个简洁的维吾尔语(Uyghur)摘要（不超过100字），只返回摘要内容：
+
+{{.Glossary}}{{.Text}}{{else}}为以下新闻生成一个简洁的{{.LangName}}摘要（不超过100字）：
+
+{{.Glossary}}{{.Text}}{{end}}`},
+
+	{Filter, "判断新闻是否值得推送，需返回 {\"valuable\": bool, \"reason\": string}。变量：.Title，.Content", `判断以下新闻是否有价值推送给用户。
+标题: {{.Title}}
+内容: {{.Content}}
+
+请返回JSON格式: {"valuable": true/false, "reason": "原因"}`},
+
+	{BatchTranslate, "批量翻译标题并生成摘要，需返回带 index 的 JSON 数组。变量：.Items 新闻列表，.Extra 附加要求（标签、实体），.TargetLang，.LangName，.Glossary 术语表要求", `{{if eq .TargetLang "ug"}}请批量翻译以下新闻的标题为维吾尔语，并为每条新闻生成维吾尔语摘要。
+
+请严格按照以下 JSON 格式返回，不要添加任何其他内容：
+[
+  {
+    "index": 1,
+    "trans_title": "维吾尔语标题",
+    "trans_summary": "维吾尔语摘要（不超过100字）"
+  }
+]
+{{else}}请批量翻译以下新闻的标题为{{.LangName}}，并为每条新闻生成{{.LangName}}摘要。
+
+请严格按照以下 JSON 格式返回，不要添加任何其他内容：
+[
+  {
+    "index": 1,
+    "trans_title": "{{.LangName}}标题",
+    "trans_summary": "{{.LangName}}摘要（不超过100字）"
+  }
+]
+{{end}}
+{{.Extra}}{{.Glossary}}新闻列表：{{.Items}}`},
+
+	{EmailTemplate, "根据描述生成或修改邮件模板。变量：.Description 用户需求，.CurrentTemplate 当前模板（为空表示新建），.Variables 可用的模板变量说明", `{{if .CurrentTemplate}}你是一个专业的邮件模板设计师。用户希望修改现有的邮件模板。
+
+用户需求：{{.Description}}
+
+当前模板：
+{{.CurrentTemplate}}
+
+请根据用户需求修改模板。要求：
+1. 生成完整的 HTML 邮件模板
+2. 必须包含以下 Go 模板变量（保持原样不变）：
+{{.Variables}}
+3. 样式要美观、现代、响应式
+4. 只返回 HTML 代码，不要任何解释
+
+直接输出完整的 HTML 模板：{{else}}你是一个专业的邮件模板设计师。请根据用户的描述创建一个新闻邮件模板。
+
+用户需求：{{.Description}}
+
+要求：
+1. 生成完整的 HTML 邮件模板
+2. 必须包含以下 Go 模板变量：
+{{.Variables}}
+3. 优先显示翻译标题，没有翻译时显示原标题
+4. 样式要美观、现代、响应式
+5. 颜色搭配协调，排版清晰
+6. 只返回 HTML 代码，不要任何解释
+
+直接输出完整的 HTML 模板：{{end}}`},
+
+	{StorySummary, "同一事件多篇报道的综合标题和摘要，需返回 {\"title\", \"summary\"}。变量：.Items 报道列表，.Count 报道数，.TargetLang，.LangName", `以下是来自不同来源、关于同一事件的 {{.Count}} 篇报道。请用{{.LangName}}：
+1. 写一个概括整个事件的标题
+2. 写一段综合多个来源的摘要（不超过200字），指出各来源之间的补充信息或分歧
+
+请严格按照以下 JSON 格式返回，不要添加任何其他内容：
+{"title": "事件标题", "summary": "综合摘要"}
+
+报道列表：{{.Items}}`},
+
+	{Briefing, "推送综述，需返回 {\"takeaways\", \"themes\", \"watch\"}。变量：.Items 新闻列表，.Count 新闻数，.TargetLang，.LangName", `你是一名资深情报分析师。请阅读以下 {{.Count}} 条新闻，用{{.LangName}}为决策者撰写一份简报：
+1. takeaways: 最重要的 3 个要点（每条一句话）
+2. themes: 这批新闻反映出的主要主题（2-4 个，每个不超过 10 个字）
+3. watch: 接下来值得关注的动向（1-3 条）
+
+请严格按照以下 JSON 格式返回，不要添加任何其他内容：
+{"takeaways": ["..."], "themes": ["..."], "watch": ["..."]}
+
+新闻列表：{{.Items}}`},
+
+	{SuggestTags, "为单条新闻打标签，需返回 JSON 字符串数组。变量：.Title，.Content，.Extra 标签词表说明", `为以下新闻打标签。{{.Extra}}
+请返回 JSON 字符串数组，例如 ["标签1", "标签2"]，不要添加任何其他内容。
+
+标题: {{.Title}}
+内容: {{.Content}}`},
+
+	{ExtractEntities, "提取单条新闻中的实体，需返回 [{\"name\", \"type\"}]。变量：.Title，.Content", `提取新闻中提到的组织(organization)、人物(person)、产品(product)、地点(location)，格式为 [{"name": "Anthropic", "type": "organization"}]，name 使用原文中最常见的规范写法，没有则返回空数组。
+请只返回 JSON 数组，不要添加任何其他内容。
+
+标题: {{.Title}}
+内容: {{.Content}}`},
+
+	{TranslateContent, "全文翻译（长文按段落分片，每次翻译一个片段）。变量：.Text 文章片段，.TargetLang，.LangName，.Glossary 术语表要求", `将以下文章片段翻译成{{.LangName}}。
+要求：
+1. 保留段落划分（段落之间的空行）
+2. 保留 Markdown 标记，如 "## " 开头的小标题和 "- " 开头的列表项
+3. 公司、产品等专有名词没有通行译名时保留原文
+4. 只返回译文，不要添加任何解释
+
+{{.Glossary}}{{.Text}}`},
+
+	{SummarizeSection, "长文分段摘要（map 阶段，每次概括一个片段）。变量：.Text 文章片段，.Title 文章标题（可能为空），.Count 片段序号，.TargetLang，.LangName", `{{if .Title}}以下是文章《{{.Title}}》的第 {{.Count}} 部分{{else}}以下是一篇长文的第 {{.Count}} 部分{{end}}。请用{{.LangName}}概括这部分的关键事实、数据和观点，不超过 200 字，只返回概括内容：
+
+{{.Text}}`},
+
+	{SummarizeMerge, "长文合并摘要（reduce 阶段，根据各部分要点生成全文摘要）。变量：.Items 各部分要点（按原文顺序编号），.Count 部分数量，.TargetLang，.LangName，.Glossary 术语表要求", `{{.Glossary}}以下是一篇长文 {{.Count}} 个部分的要点（按原文顺序）：
+{{.Items}}
+
+{{if eq .TargetLang "ug"}}请据此为全文生成一个简洁的维吾尔语(Uyghur)摘要（不超过100字），只返回摘要内容。{{else}}请据此为全文生成一个简洁的{{.LangName}}摘要（不超过100字），只返回摘要内容。{{end}}`},
+
+	{StyledSummary, "按摘要样式生成摘要。变量：.Title 新闻标题，.Text 新闻内容，.Extra 样式要求（长度、格式、语气），.TargetLang，.LangName，.Glossary 术语表要求", `为以下新闻生成{{.LangName}}摘要。
+
+摘要要求：
+{{.Extra}}
+
+只返回摘要内容，不要添加标题或任何解释。
+
+{{.Glossary}}标题：{{.Title}}
+
+{{.Text}}`},
+
+	{Ask, "新闻库问答的系统提示词。变量：.Items 检索到的资料（带编号）", `你是新闻情报分析助手。请仅根据下面提供的新闻资料回答用户的问题：
+- 每个论点句末用 [编号] 标注所依据的资料，例如 [1]、[2][3]
+- 资料不足以回答时直接说明，不要编造
+- 使用与用户提问相同的语言回答
+
+资料：{{if .Items}}{{.Items}}{{else}}
+（没有检索到相关新闻）{{end}}`},
+}
+
+func findBuiltin(name string) *builtin {
+	for i := range defaults {
+		if defaults[i].Name == name {
+			return &defaults[i]
+		}
+	}
+	return nil
+}
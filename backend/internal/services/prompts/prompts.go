@@ -0,0 +1,325 @@
+package prompts
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/alerts"
+)
+
+var (
+	ErrNotFound      = errors.New("prompt not found")
+	ErrExists        = errors.New("prompt already exists")
+	ErrBuiltin       = errors.New("built-in prompts cannot be deleted")
+	ErrVersionAbsent = errors.New("prompt version not found")
+)
+
+// Data 提示词模板可用的变量
+type Data struct {
+	Text            string // 待处理文本
+	TargetLang      string // 目标语言代码：zh-CN / ug / en …
+	LangName        string // 目标语言名称，为空时根据 TargetLang 自动填充
+	Title           string // 新闻标题
+	Content         string // 新闻内容
+	Items           string // 格式化后的新闻列表
+	Count           int    // 新闻数量
+	Extra           string // 附加要求（标签词表、实体提取等）
+	Glossary        string // 术语表要求（原文中出现的术语及指定译法）
+	Description     string // 用户需求描述
+	CurrentTemplate string // 当前邮件模板
+	Variables       string // 邮件模板可用变量说明
+}
+
+type cached struct {
+	version int
+	tmpl    *template.Template
+}
+
+var (
+	mu    sync.RWMutex
+	cache = make(map[string]cached)
+)
+
+// Init 写入内置提示词；未被修改过的内置提示词在默认内容变化时同步更新
+func Init() error {
+	for _, b := range defaults {
+		var content string
+		var isBuiltin bool
+		err := database.DB.QueryRow("SELECT content, builtin FROM prompts WHERE name = ?", b.Name).Scan(&content, &isBuiltin)
+		switch {
+		case err == sql.ErrNoRows:
+			if err := insert(b.Name, b.Description, b.Content, true); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		case isBuiltin && content != b.Content:
+			if err := save(b.Name, b.Content, "内置提示词更新", true); err != nil {
+				return err
+			}
+			log.Printf("Built-in prompt %s updated", b.Name)
+		}
+	}
+	return nil
+}
+
+// Render 渲染指定提示词；数据库中的版本无法使用时回退到内置默认内容
+func Render(name string, data Data) (string, error) {
+	if data.LangName == "" {
+		data.LangName = LangName(data.TargetLang)
+	}
+	tmpl, err := load(name)
+	if err == nil {
+		var buf bytes.Buffer
+		if err = tmpl.Execute(&buf, data); err == nil {
+			if data.Glossary != "" && !strings.Contains(buf.String(), strings.TrimSpace(data.Glossary)) {
+				alerts.Raise(fmt.Sprintf("prompt:glossary:%s:%d", name, Version(name)), alerts.LevelWarning,
+					fmt.Sprintf("提示词 %s 没有引用 {{.Glossary}}，术语表要求未注入，译文可能不遵守术语表", name))
+			}
+			return buf.String(), nil
+		}
+	}
+
+	b := findBuiltin(name)
+	if b == nil {
+		return "", err
+	}
+	log.Printf("Prompt %s unusable, falling back to built-in: %v", name, err)
+	return RenderContent(b.Content, data)
+}
+
+// RenderContent 渲染给定的提示词内容（用于测试草稿）
+func RenderContent(content string, data Data) (string, error) {
+	if data.LangName == "" {
+		data.LangName = LangName(data.TargetLang)
+	}
+	tmpl, err := template.New("prompt").Parse(content)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// langNames 常用语言代码对应的名称
+var langNames = map[string]string{
+	"zh-CN": "中文",
+	"zh-TW": "繁体中文",
+	"ug":    "维吾尔语(Uyghur)",
+	"en":    "英语(English)",
+	"ja":    "日语",
+	"ko":    "韩语",
+	"ar":    "阿拉伯语",
+	"ru":    "俄语",
+	"fr":    "法语",
+	"de":    "德语",
+	"es":    "西班牙语",
+}
+
+// LangName 语言代码对应的名称，未知代码原样返回
+func LangName(code string) string {
+	if name, ok := langNames[code]; ok {
+		return name
+	}
+	return code
+}
+
+// Validate 检查提示词能否解析并使用 Data 中的变量渲染
+func Validate(content string) error {
+	_, err := RenderContent(content, Data{TargetLang: "zh-CN"})
+	return err
+}
+
+// MissingGlossary 内置版本引用了 {{.Glossary}} 而给定内容没有引用时返回 true（术语表要求将不会注入）
+func MissingGlossary(name, content string) bool {
+	b := findBuiltin(name)
+	return b != nil && strings.Contains(b.Content, "{{.Glossary}}") && !strings.Contains(content, ".Glossary")
+}
+
+// Version 提示词当前版本号，不存在时返回 0
+func Version(name string) int {
+	var version int
+	database.DB.QueryRow("SELECT version FROM prompts WHERE name = ?", name).Scan(&version)
+	return version
+}
+
+func load(name string) (*template.Template, error) {
+	var content string
+	var version int
+	err := database.DB.QueryRow("SELECT content, version FROM prompts WHERE name = ?", name).Scan(&content, &version)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mu.RLock()
+	c, ok := cache[name]
+	mu.RUnlock()
+	if ok && c.version == version {
+		return c.tmpl, nil
+	}
+
+	tmpl, err := template.New(name).Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	mu.Lock()
+	cache[name] = cached{version: version, tmpl: tmpl}
+	mu.Unlock()
+	return tmpl, nil
+}
+
+// List 全部提示词
+func List() ([]models.Prompt, error) {
+	rows, err := database.DB.Query("SELECT name, COALESCE(description, ''), content, version, builtin, updated_at FROM prompts ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.Prompt{}
+	for rows.Next() {
+		var p models.Prompt
+		if err := rows.Scan(&p.Name, &p.Description, &p.Content, &p.Version, &p.Builtin, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		p.Custom = findBuiltin(p.Name) == nil
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
+// Get 提示词详情（含版本历史，新版本在前）
+func Get(name string) (*models.Prompt, error) {
+	var p models.Prompt
+	err := database.DB.QueryRow("SELECT name, COALESCE(description, ''), content, version, builtin, updated_at FROM prompts WHERE name = ?", name).
+		Scan(&p.Name, &p.Description, &p.Content, &p.Version, &p.Builtin, &p.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.Custom = findBuiltin(p.Name) == nil
+
+	rows, err := database.DB.Query("SELECT version, content, COALESCE(note, ''), created_at FROM prompt_versions WHERE name = ? ORDER BY version DESC", name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var v models.PromptVersion
+		if err := rows.Scan(&v.Version, &v.Content, &v.Note, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		p.Versions = append(p.Versions, v)
+	}
+	return &p, rows.Err()
+}
+
+// Create 新建自定义提示词
+func Create(name, description, content string) error {
+	if err := Validate(content); err != nil {
+		return err
+	}
+	var exists int
+	database.DB.QueryRow("SELECT COUNT(*) FROM prompts WHERE name = ?", name).Scan(&exists)
+	if exists > 0 {
+		return ErrExists
+	}
+	return insert(name, description, content, false)
+}
+
+// Update 修改提示词，生成新版本
+func Update(name, description, content, note string) error {
+	if err := Validate(content); err != nil {
+		return err
+	}
+	if Version(name) == 0 {
+		return ErrNotFound
+	}
+	if description != "" {
+		if _, err := database.DB.Exec("UPDATE prompts SET description = ? WHERE name = ?", description, name); err != nil {
+			return err
+		}
+	}
+	return save(name, content, note, false)
+}
+
+// Rollback 回滚到指定版本（以该版本内容生成新版本）
+func Rollback(name string, version int) error {
+	var content string
+	err := database.DB.QueryRow("SELECT content FROM prompt_versions WHERE name = ? AND version = ?", name, version).Scan(&content)
+	if err == sql.ErrNoRows {
+		return ErrVersionAbsent
+	}
+	if err != nil {
+		return err
+	}
+
+	// 回滚到与内置默认一致的内容时，恢复随版本自动更新
+	b := findBuiltin(name)
+	return save(name, content, fmt.Sprintf("回滚到 v%d", version), b != nil && b.Content == content)
+}
+
+// Delete 删除自定义提示词（内置提示词只能回滚，不能删除）
+func Delete(name string) error {
+	if findBuiltin(name) != nil {
+		return ErrBuiltin
+	}
+	if _, err := database.DB.Exec("DELETE FROM prompt_versions WHERE name = ?", name); err != nil {
+		return err
+	}
+	_, err := database.DB.Exec("DELETE FROM prompts WHERE name = ?", name)
+	return err
+}
+
+func insert(name, description, content string, isBuiltin bool) error {
+	now := time.Now()
+	if _, err := database.DB.Exec(`INSERT INTO prompts (name, description, content, version, builtin, created_at, updated_at)
+		VALUES (?, ?, ?, 1, ?, ?, ?)`, name, description, content, isBuiltin, now, now); err != nil {
+		return err
+	}
+	_, err := database.DB.Exec("INSERT INTO prompt_versions (name, version, content, note, created_at) VALUES (?, 1, ?, ?, ?)",
+		name, content, "初始版本", now)
+	return err
+}
+
+// save 写入新版本
+func save(name, content, note string, isBuiltin bool) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var version int
+	if err := tx.QueryRow("SELECT version FROM prompts WHERE name = ?", name).Scan(&version); err != nil {
+		return err
+	}
+	version++
+	now := time.Now()
+	if _, err := tx.Exec("UPDATE prompts SET content = ?, version = ?, builtin = ?, updated_at = ? WHERE name = ?",
+		content, version, isBuiltin, now, name); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("INSERT INTO prompt_versions (name, version, content, note, created_at) VALUES (?, ?, ?, ?, ?)",
+		name, version, content, note, now); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
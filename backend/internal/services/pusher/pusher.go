@@ -15,14 +15,35 @@ import (
 
 	"news-intel-app/internal/database"
 	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/ai"
+	"news-intel-app/internal/services/enrichment"
+	"news-intel-app/internal/services/stories"
+	"news-intel-app/internal/services/taxonomy"
+	"news-intel-app/internal/services/translations"
+	"news-intel-app/internal/textutil"
 
 	"gopkg.in/gomail.v2"
 )
 
-type Pusher struct{}
+type Pusher struct {
+	ai *ai.AIService
+}
+
+func New(aiSvc *ai.AIService) *Pusher {
+	return &Pusher{ai: aiSvc}
+}
 
-func New() *Pusher {
-	return &Pusher{}
+// GenerateBriefing 生成推送综述，失败时记录日志并返回 nil（不影响推送）
+func (p *Pusher) GenerateBriefing(news []models.News) *models.Briefing {
+	if p.ai == nil || len(news) == 0 {
+		return nil
+	}
+	briefing, err := p.ai.GenerateBriefing(news)
+	if err != nil {
+		log.Printf("Failed to generate briefing: %v", err)
+		return nil
+	}
+	return briefing
 }
 
 // SendEmail 发送邮件
@@ -72,14 +93,19 @@ func (p *Pusher) SendNtfy(config *models.NtfyConfig, title, message string) erro
 }
 
 // RenderTemplate 渲染邮件模板
-func (p *Pusher) RenderTemplate(tmplContent string, news []models.News) (string, error) {
+func (p *Pusher) RenderTemplate(tmplContent string, news []models.News, briefing *models.Briefing) (string, error) {
 	tmpl, err := template.New("email").Parse(tmplContent)
 	if err != nil {
 		return "", err
 	}
 
+	storyGroups, singles := stories.Group(news)
+
 	data := map[string]interface{}{
 		"News":      news,
+		"Stories":   storyGroups, // 同一事件的多源报道合并为一个故事块
+		"Singles":   singles,     // 不属于任何故事的新闻
+		"Briefing":  briefing,    // AI 综述，未开启或生成失败时为 nil
 		"Date":      time.Now().Format("2006-01-02"),
 		"Count":     len(news),
 		"Generated": time.Now().Format("2006-01-02 15:04:05"),
@@ -93,46 +119,27 @@ func (p *Pusher) RenderTemplate(tmplContent string, news []models.News) (string,
 	return buf.String(), nil
 }
 
-// ExecutePushTask 执行推送任务 - 从阅读窗口取未推送的新闻
-func (p *Pusher) ExecutePushTask(task *models.PushTask) error {
-	// 获取渠道配置
-	var channel models.PushChannel
-	err := database.DB.QueryRow("SELECT id, name, type, config FROM push_channels WHERE id = ?", task.ChannelID).
-		Scan(&channel.ID, &channel.Name, &channel.Type, &channel.Config)
-	if err != nil {
-		return fmt.Errorf("channel not found: %w", err)
-	}
-
-	// 从阅读窗口获取未推送的新闻
-	categories := strings.Split(task.Categories, ",")
-	placeholders := make([]string, len(categories))
-	args := make([]interface{}, len(categories))
-	for i, c := range categories {
-		placeholders[i] = "?"
-		args[i] = strings.TrimSpace(c)
-	}
-
-	query := fmt.Sprintf(`
-		SELECT id, title, content, summary, url, source, category, image_url, trans_title, trans_summary 
-		FROM news 
-		WHERE in_reading = 1 AND pushed = 0 AND translated = 1 AND category IN (%s)
-		ORDER BY reading_at DESC LIMIT 20
-	`, strings.Join(placeholders, ","))
-
-	rows, err := database.DB.Query(query, args...)
+// QueryNews 查询用于推送/预览的新闻，where 为 WHERE 之后的条件（可包含 ORDER BY、LIMIT）
+func QueryNews(where string, args ...interface{}) ([]models.News, error) {
+	rows, err := database.DB.Query(`
+		SELECT id, title, content, summary, url, source, category, image_url, trans_title, trans_summary, story_id, 
+		COALESCE(sentiment, ''), COALESCE(fields, '') 
+		FROM news WHERE `+where, args...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer rows.Close()
 
 	var news []models.News
-	var newsIDs []string
 	for rows.Next() {
 		var n models.News
-		var transTitle, transSummary, content, summary, imageURL sql.NullString
-		if err := rows.Scan(&n.ID, &n.Title, &content, &summary, &n.URL, &n.Source, &n.Category, &imageURL, &transTitle, &transSummary); err != nil {
+		var transTitle, transSummary, content, summary, imageURL, storyID sql.NullString
+		var fields string
+		if err := rows.Scan(&n.ID, &n.Title, &content, &summary, &n.URL, &n.Source, &n.Category, &imageURL, &transTitle, &transSummary, &storyID,
+			&n.Sentiment, &fields); err != nil {
 			continue
 		}
+		n.Fields = enrichment.Decode(fields)
 		if transTitle.Valid {
 			n.TransTitle = transTitle.String
 		}
@@ -148,7 +155,53 @@ func (p *Pusher) ExecutePushTask(task *models.PushTask) error {
 		if imageURL.Valid {
 			n.ImageURL = imageURL.String
 		}
+		n.StoryID = storyID.String
 		news = append(news, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return news, translations.Attach(news)
+}
+
+// ExecutePushTask 执行推送任务 - 从阅读窗口取未推送的新闻
+func (p *Pusher) ExecutePushTask(task *models.PushTask) error {
+	// 获取渠道配置
+	var channel models.PushChannel
+	err := database.DB.QueryRow("SELECT id, name, type, config FROM push_channels WHERE id = ?", task.ChannelID).
+		Scan(&channel.ID, &channel.Name, &channel.Type, &channel.Config)
+	if err != nil {
+		return fmt.Errorf("channel not found: %w", err)
+	}
+
+	// 从阅读窗口获取未推送的新闻（命中任一分类或任一标签）
+	var conditions []string
+	var args []interface{}
+	if categories := textutil.SplitList(task.Categories); len(categories) > 0 {
+		placeholders := make([]string, len(categories))
+		for i, c := range categories {
+			placeholders[i] = "?"
+			args = append(args, c)
+		}
+		conditions = append(conditions, fmt.Sprintf("category IN (%s)", strings.Join(placeholders, ",")))
+	}
+	if clause, tagArgs := taxonomy.FilterClause(textutil.SplitList(task.Tags)); clause != "" {
+		conditions = append(conditions, clause)
+		args = append(args, tagArgs...)
+	}
+	if len(conditions) == 0 {
+		log.Printf("Push task %s has no categories or tags", task.Name)
+		return nil
+	}
+
+	news, err := QueryNews(fmt.Sprintf(`in_reading = 1 AND pushed = 0 AND translated = 1 AND (%s)
+		ORDER BY reading_at DESC LIMIT 20`, strings.Join(conditions, " OR ")), args...)
+	if err != nil {
+		return err
+	}
+
+	var newsIDs []string
+	for _, n := range news {
 		newsIDs = append(newsIDs, n.ID)
 	}
 
@@ -159,12 +212,24 @@ func (p *Pusher) ExecutePushTask(task *models.PushTask) error {
 
 	log.Printf("Pushing %d news from reading window...", len(news))
 
+	var briefing *models.Briefing
+	if task.Briefing {
+		briefing = p.GenerateBriefing(news)
+	}
+
+	// 模板渲染任务选择的摘要样式
+	if task.SummaryStyle != "" && p.ai != nil {
+		if err := p.ai.ApplySummaryStyle(news, task.SummaryStyle); err != nil {
+			log.Printf("Failed to apply summary style %s: %v", task.SummaryStyle, err)
+		}
+	}
+
 	var pushErr error
 	switch channel.Type {
 	case "email":
-		pushErr = p.pushEmail(&channel, task, news)
+		pushErr = p.pushEmail(&channel, task, news, briefing)
 	case "ntfy":
-		pushErr = p.pushNtfy(&channel, news)
+		pushErr = p.pushNtfy(&channel, news, briefing)
 	}
 
 	// 推送成功后标记为已推送
@@ -178,7 +243,7 @@ func (p *Pusher) ExecutePushTask(task *models.PushTask) error {
 	return pushErr
 }
 
-func (p *Pusher) pushEmail(channel *models.PushChannel, task *models.PushTask, news []models.News) error {
+func (p *Pusher) pushEmail(channel *models.PushChannel, task *models.PushTask, news []models.News, briefing *models.Briefing) error {
 	var config models.EmailConfig
 	if err := json.Unmarshal([]byte(channel.Config), &config); err != nil {
 		return err
@@ -194,7 +259,7 @@ func (p *Pusher) pushEmail(channel *models.PushChannel, task *models.PushTask, n
 		tmpl.Subject = fmt.Sprintf("新闻情报日报 - %s", time.Now().Format("2006-01-02"))
 	}
 
-	htmlContent, err := p.RenderTemplate(tmpl.Content, news)
+	htmlContent, err := p.RenderTemplate(tmpl.Content, news, briefing)
 	if err != nil {
 		return err
 	}
@@ -202,7 +267,7 @@ func (p *Pusher) pushEmail(channel *models.PushChannel, task *models.PushTask, n
 	return p.SendEmail(&config, tmpl.Subject, htmlContent)
 }
 
-func (p *Pusher) pushNtfy(channel *models.PushChannel, news []models.News) error {
+func (p *Pusher) pushNtfy(channel *models.PushChannel, news []models.News, briefing *models.Briefing) error {
 	var config models.NtfyConfig
 	if err := json.Unmarshal([]byte(channel.Config), &config); err != nil {
 		return err
@@ -210,6 +275,19 @@ func (p *Pusher) pushNtfy(channel *models.PushChannel, news []models.News) error
 
 	// 构建摘要消息（ntfy 支持 Markdown）
 	var sb strings.Builder
+	if briefing != nil && len(briefing.Takeaways) > 0 {
+		sb.WriteString("**要点**\n")
+		for _, t := range briefing.Takeaways {
+			sb.WriteString(fmt.Sprintf("- %s\n", t))
+		}
+		if len(briefing.Watch) > 0 {
+			sb.WriteString("\n**值得关注**\n")
+			for _, w := range briefing.Watch {
+				sb.WriteString(fmt.Sprintf("- %s\n", w))
+			}
+		}
+		sb.WriteString("\n---\n\n")
+	}
 	maxNews := 10
 	if len(news) < maxNews {
 		maxNews = len(news)
@@ -225,9 +303,9 @@ func (p *Pusher) pushNtfy(channel *models.PushChannel, news []models.News) error
 		if summary == "" {
 			summary = n.Summary
 		}
-		// 截断摘要
-		if len(summary) > 100 {
-			summary = summary[:100] + "..."
+		// 截断默认摘要（样式摘要已按样式控制长度）
+		if n.SummaryStyle == "" {
+			summary = textutil.Truncate(summary, 100)
 		}
 
 		sb.WriteString(fmt.Sprintf("**%d. %s**\n", i+1, title))
@@ -305,6 +383,13 @@ func GetDefaultEmailTemplate() string {
         .bilingual { background: #f9f9f9; padding: 12px; border-radius: 8px; margin-top: 10px; }
         .bilingual .zh { margin-bottom: 8px; padding-bottom: 8px; border-bottom: 1px dashed #ddd; }
         .bilingual .ug { direction: rtl; text-align: right; }
+        .story-sources { margin: 12px 0 0; padding-left: 18px; font-size: 13px; line-height: 1.8; }
+        .story-sources a { color: #667eea; text-decoration: none; }
+        .story-sources span { color: #999; }
+        .briefing { background: #f7f7ff; border-left: 4px solid #667eea; border-radius: 8px; padding: 16px 20px; margin-bottom: 10px; }
+        .briefing h3 { margin: 0 0 8px; font-size: 15px; color: #333; }
+        .briefing ul { margin: 0 0 12px; padding-left: 18px; color: #555; line-height: 1.8; }
+        .briefing ul:last-child { margin-bottom: 0; }
     </style>
 </head>
 <body>
@@ -314,93 +399,43 @@ func GetDefaultEmailTemplate() string {
             <p>{{.Date}} · 共 {{.Count}} 条新闻</p>
         </div>
         <div class="content">
-            {{range .News}}
+            {{if .Briefing}}
+            <div class="briefing">
+                <h3>今日要点</h3>
+                <ul>{{range .Briefing.Takeaways}}<li>{{.}}</li>{{end}}</ul>
+                {{if .Briefing.Themes}}<h3>主题</h3>
+                <ul>{{range .Briefing.Themes}}<li>{{.}}</li>{{end}}</ul>{{end}}
+                {{if .Briefing.Watch}}<h3>值得关注</h3>
+                <ul>{{range .Briefing.Watch}}<li>{{.}}</li>{{end}}</ul>{{end}}
+            </div>
+            {{end}}
+            {{range .Stories}}
             <div class="news-item">
-                <h2 class="news-title">
-                    <a href="{{.URL}}" target="_blank">{{if .TransTitle}}{{.TransTitle}}{{else}}{{.Title}}{{end}}</a>
-                </h2>
+                <h2 class="news-title">{{.Title}}</h2>
                 <div class="news-meta">
-                    <span class="category-tag">{{.Category}}</span>
-                    <span>来源: {{.Source}}</span>
+                    <span class="category-tag">{{.NewsCount}} 篇报道</span>
                 </div>
-                <div class="news-summary">{{if .TransSummary}}{{.TransSummary}}{{else}}{{.Summary}}{{end}}</div>
+                {{if .Summary}}<div class="news-summary">{{.Summary}}</div>{{end}}
+                <ul class="story-sources">
+                    {{range .News}}<li><a href="{{.URL}}" target="_blank">{{if .TransTitle}}{{.TransTitle}}{{else}}{{.Title}}{{end}}</a> <span>· {{.Source}}</span></li>{{end}}
+                </ul>
             </div>
             {{end}}
-        </div>
-        <div class="footer">
-            <p>由 News Intel App 自动生成于 {{.Generated}}</p>
-        </div>
-    </div>
-</body>
-</html>`
-}
-
-// GetBilingualEmailTemplate 获取双语邮件模板（中文+维吾尔语）
-func GetBilingualEmailTemplate() string {
-	return `<!DOCTYPE html>
-<html>
-<head>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <style>
-        body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; background: #f5f5f5; margin: 0; padding: 20px; }
-        .container { max-width: 720px; margin: 0 auto; background: #fff; border-radius: 12px; overflow: hidden; box-shadow: 0 2px 8px rgba(0,0,0,0.1); }
-        .header { background: linear-gradient(135deg, #667eea 0%, #764ba2 100%); color: white; padding: 30px; text-align: center; }
-        .header h1 { margin: 0; font-size: 24px; }
-        .header .subtitle { margin: 10px 0 0; opacity: 0.9; font-size: 14px; }
-        .header .ug-title { direction: rtl; font-size: 20px; margin-top: 8px; }
-        .content { padding: 20px; }
-        .news-item { border-bottom: 1px solid #eee; padding: 24px 0; }
-        .news-item:last-child { border-bottom: none; }
-        .news-title { font-size: 18px; font-weight: 600; margin: 0 0 12px; }
-        .news-title a { color: #667eea; text-decoration: none; }
-        .news-title a:hover { text-decoration: underline; }
-        .news-meta { font-size: 12px; color: #999; margin-bottom: 12px; }
-        .news-meta span { margin-right: 15px; }
-        .category-tag { display: inline-block; background: #667eea; color: white; padding: 2px 10px; border-radius: 4px; font-size: 11px; }
-        .bilingual-content { background: #fafafa; border-radius: 8px; overflow: hidden; }
-        .lang-section { padding: 15px; }
-        .lang-section.zh { border-bottom: 1px solid #eee; }
-        .lang-section.ug { direction: rtl; text-align: right; background: #f5f5f5; }
-        .lang-label { font-size: 11px; color: #999; margin-bottom: 6px; text-transform: uppercase; }
-        .lang-text { color: #555; line-height: 1.8; margin: 0; }
-        .footer { background: #fafafa; padding: 20px; text-align: center; color: #999; font-size: 12px; }
-        .footer .ug { direction: rtl; margin-top: 5px; }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <div class="header">
-            <h1>新闻情报日报</h1>
-            <div class="ug-title">خەۋەر ئۇچۇرلىرى كۈندىلىك</div>
-            <p class="subtitle">{{.Date}} · 共 {{.Count}} 条新闻 · جەمئى {{.Count}} خەۋەر</p>
-        </div>
-        <div class="content">
-            {{range .News}}
+            {{range .Singles}}
             <div class="news-item">
                 <h2 class="news-title">
-                    <a href="{{.URL}}" target="_blank">{{.Title}}</a>
+                    <a href="{{.URL}}" target="_blank">{{if .TransTitle}}{{.TransTitle}}{{else}}{{.Title}}{{end}}</a>
                 </h2>
                 <div class="news-meta">
                     <span class="category-tag">{{.Category}}</span>
-                    <span>来源/مەنبە: {{.Source}}</span>
-                </div>
-                <div class="bilingual-content">
-                    <div class="lang-section zh">
-                        <div class="lang-label">中文</div>
-                        <p class="lang-text">{{if .TransSummary}}{{.TransSummary}}{{else}}{{.Summary}}{{end}}</p>
-                    </div>
-                    <div class="lang-section ug">
-                        <div class="lang-label">ئۇيغۇرچە</div>
-                        <p class="lang-text">{{if .TransTitle}}{{.TransTitle}}{{else}}{{.Title}}{{end}}</p>
-                    </div>
+                    <span>来源: {{.Source}}</span>
                 </div>
+                <div class="news-summary">{{if .TransSummary}}{{.TransSummary}}{{else}}{{.Summary}}{{end}}</div>
             </div>
             {{end}}
         </div>
         <div class="footer">
             <p>由 News Intel App 自动生成于 {{.Generated}}</p>
-            <p class="ug">News Intel App تەرىپىدىن ئاپتوماتىك ھاسىل قىلىندى</p>
         </div>
     </div>
 </body>
@@ -408,14 +443,16 @@ func GetBilingualEmailTemplate() string {
 }
 
 // GetAutoPushConfig 获取自动打包推送配置
-func (p *Pusher) GetAutoPushConfig() (enabled bool, threshold int, channelID, templateID string) {
-	var enabledStr, thresholdStr string
+func (p *Pusher) GetAutoPushConfig() (enabled bool, threshold int, channelID, templateID string, briefing bool) {
+	var enabledStr, thresholdStr, briefingStr string
 	database.DB.QueryRow("SELECT value FROM settings WHERE key = 'auto_push_enabled'").Scan(&enabledStr)
 	database.DB.QueryRow("SELECT value FROM settings WHERE key = 'auto_push_threshold'").Scan(&thresholdStr)
 	database.DB.QueryRow("SELECT value FROM settings WHERE key = 'auto_push_channel_id'").Scan(&channelID)
 	database.DB.QueryRow("SELECT value FROM settings WHERE key = 'auto_push_template_id'").Scan(&templateID)
+	database.DB.QueryRow("SELECT value FROM settings WHERE key = 'auto_push_briefing'").Scan(&briefingStr)
 
 	enabled = enabledStr == "1"
+	briefing = briefingStr == "1"
 	threshold = 6
 	if thresholdStr != "" {
 		fmt.Sscanf(thresholdStr, "%d", &threshold)
@@ -425,7 +462,7 @@ func (p *Pusher) GetAutoPushConfig() (enabled bool, threshold int, channelID, te
 
 // CheckAndAutoPush 检查并触发自动打包推送
 func (p *Pusher) CheckAndAutoPush() error {
-	enabled, threshold, channelID, templateID := p.GetAutoPushConfig()
+	enabled, threshold, channelID, templateID, withBriefing := p.GetAutoPushConfig()
 	if !enabled || channelID == "" {
 		return nil
 	}
@@ -450,41 +487,13 @@ func (p *Pusher) CheckAndAutoPush() error {
 	}
 
 	// 获取待推送的新闻（取 threshold 条）
-	rows, err := database.DB.Query(`
-		SELECT id, title, content, summary, url, source, category, image_url, trans_title, trans_summary 
-		FROM news 
-		WHERE in_reading = 1 AND pushed = 0 AND translated = 1
-		ORDER BY reading_at ASC LIMIT ?
-	`, threshold)
+	news, err := QueryNews("in_reading = 1 AND pushed = 0 AND translated = 1 ORDER BY reading_at ASC LIMIT ?", threshold)
 	if err != nil {
 		return err
 	}
-	defer rows.Close()
 
-	var news []models.News
 	var newsIDs []string
-	for rows.Next() {
-		var n models.News
-		var transTitle, transSummary, content, summary, imageURL sql.NullString
-		if err := rows.Scan(&n.ID, &n.Title, &content, &summary, &n.URL, &n.Source, &n.Category, &imageURL, &transTitle, &transSummary); err != nil {
-			continue
-		}
-		if transTitle.Valid {
-			n.TransTitle = transTitle.String
-		}
-		if transSummary.Valid {
-			n.TransSummary = transSummary.String
-		}
-		if content.Valid {
-			n.Content = content.String
-		}
-		if summary.Valid {
-			n.Summary = summary.String
-		}
-		if imageURL.Valid {
-			n.ImageURL = imageURL.String
-		}
-		news = append(news, n)
+	for _, n := range news {
 		newsIDs = append(newsIDs, n.ID)
 	}
 
@@ -494,6 +503,11 @@ func (p *Pusher) CheckAndAutoPush() error {
 
 	log.Printf("Auto pushing %d news...", len(news))
 
+	var briefing *models.Briefing
+	if withBriefing {
+		briefing = p.GenerateBriefing(news)
+	}
+
 	var pushErr error
 	switch channel.Type {
 	case "email":
@@ -508,7 +522,7 @@ func (p *Pusher) CheckAndAutoPush() error {
 			tmplSubject = fmt.Sprintf("新闻情报 - %s", time.Now().Format("2006-01-02 15:04"))
 		}
 
-		htmlContent, err := p.RenderTemplate(tmplContent, news)
+		htmlContent, err := p.RenderTemplate(tmplContent, news, briefing)
 		if err != nil {
 			return err
 		}
@@ -520,7 +534,7 @@ func (p *Pusher) CheckAndAutoPush() error {
 		pushErr = p.SendEmail(&config, tmplSubject, htmlContent)
 
 	case "ntfy":
-		pushErr = p.pushNtfy(&channel, news)
+		pushErr = p.pushNtfy(&channel, news, briefing)
 	}
 
 	// 推送成功后标记为已推送
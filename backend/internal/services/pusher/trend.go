@@ -0,0 +1,85 @@
+package pusher
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+)
+
+// trendAlertNews 趋势提醒中列出的相关新闻数
+const trendAlertNews = 5
+
+var trendKindNames = map[string]string{"term": "关键词", "entity": "实体", "tag": "标签"}
+
+// PushTrendAlert 关注词出现突增时向渠道推送提醒：最近窗口的新闻数、基线和相关新闻
+func (p *Pusher) PushTrendAlert(channelID string, trend models.Trend) error {
+	var channel models.PushChannel
+	err := database.DB.QueryRow("SELECT id, name, type, config FROM push_channels WHERE id = ?", channelID).
+		Scan(&channel.ID, &channel.Name, &channel.Type, &channel.Config)
+	if err != nil {
+		return fmt.Errorf("channel not found: %w", err)
+	}
+
+	var news []models.News
+	if ids := trend.NewsIDs; len(ids) > 0 {
+		if len(ids) > trendAlertNews {
+			ids = ids[:trendAlertNews]
+		}
+		args := make([]interface{}, len(ids))
+		for i, id := range ids {
+			args[i] = id
+		}
+		news, err = QueryNews("id IN (?"+strings.Repeat(",?", len(ids)-1)+") ORDER BY created_at DESC", args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	kind := trendKindNames[trend.Kind]
+	if kind == "" {
+		kind = trend.Kind
+	}
+	title := fmt.Sprintf("热点提醒：%s「%s」", kind, trend.Term)
+	stats := fmt.Sprintf("最近窗口内 %d 条新闻提到，基线平均 %.1f 条，显著性 %.1f", trend.Count, trend.Baseline, trend.Score)
+
+	switch channel.Type {
+	case "email":
+		var config models.EmailConfig
+		if err := json.Unmarshal([]byte(channel.Config), &config); err != nil {
+			return err
+		}
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("<h2>%s</h2><p>%s</p><ul>", html.EscapeString(title), html.EscapeString(stats)))
+		for _, n := range news {
+			sb.WriteString(fmt.Sprintf(`<li><a href="%s">%s</a> <span style="color:#999">%s</span></li>`,
+				html.EscapeString(n.URL), html.EscapeString(newsTitle(n)), html.EscapeString(n.Source)))
+		}
+		sb.WriteString("</ul>")
+		return p.SendEmail(&config, title, sb.String())
+	case "ntfy":
+		var config models.NtfyConfig
+		if err := json.Unmarshal([]byte(channel.Config), &config); err != nil {
+			return err
+		}
+		var sb strings.Builder
+		sb.WriteString(stats + "\n\n")
+		for _, n := range news {
+			sb.WriteString(fmt.Sprintf("- [%s](%s)\n", newsTitle(n), n.URL))
+		}
+		return p.SendNtfyMarkdown(&config, title, sb.String())
+	default:
+		return fmt.Errorf("unsupported channel type: %s", channel.Type)
+	}
+}
+
+// newsTitle 优先使用译文标题
+func newsTitle(n models.News) string {
+	if n.TransTitle != "" {
+		return n.TransTitle
+	}
+	return n.Title
+}
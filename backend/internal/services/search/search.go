@@ -0,0 +1,246 @@
+package search
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/ai"
+	"news-intel-app/internal/textutil"
+)
+
+const (
+	batchSize     = 32   // 每次调用 embeddings 接口的新闻数
+	maxInputRunes = 2000 // 单条新闻参与向量计算的最大字符数
+	maxResults    = 100  // 单次返回的最多条数
+)
+
+// Searcher 语义搜索：计算并存储新闻向量，按余弦相似度排序
+type Searcher struct {
+	ai *ai.AIService
+	mu sync.Mutex
+}
+
+func New(aiSvc *ai.AIService) *Searcher {
+	return &Searcher{ai: aiSvc}
+}
+
+// Hit 搜索命中的新闻及相似度
+type Hit struct {
+	NewsID string
+	Score  float64
+}
+
+// Backfill 为缺少向量（或向量模型已更换）的新闻补算向量，返回处理条数
+func (s *Searcher) Backfill(limit int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	news, err := loadNews(`LEFT JOIN news_embeddings e ON e.news_id = n.id
+		WHERE n.is_filtered = 0 AND (e.news_id IS NULL OR e.model != ?)
+		ORDER BY n.created_at DESC LIMIT ?`, s.ai.EmbeddingModel(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	done := 0
+	for start := 0; start < len(news); start += batchSize {
+		end := start + batchSize
+		if end > len(news) {
+			end = len(news)
+		}
+		if err := s.embed(news[start:end]); err != nil {
+			return done, err
+		}
+		done += end - start
+	}
+	return done, nil
+}
+
+// Semantic 按与查询文本的语义相似度搜索新闻
+func (s *Searcher) Semantic(query string, limit int) ([]Hit, error) {
+	vectors, err := s.ai.Embed([]string{query})
+	if err != nil {
+		return nil, err
+	}
+	q := vectors[0]
+	normalize(q)
+	return rank(q, s.ai.EmbeddingModel(), "", limit)
+}
+
+// Related 查找与指定新闻最相似的新闻；该新闻尚无向量时先计算
+func (s *Searcher) Related(newsID string, limit int) ([]Hit, error) {
+	model := s.ai.EmbeddingModel()
+
+	var blob []byte
+	err := database.DB.QueryRow("SELECT vector FROM news_embeddings WHERE news_id = ? AND model = ?", newsID, model).Scan(&blob)
+	if err == sql.ErrNoRows {
+		news, err := loadNews("WHERE n.id = ?", newsID)
+		if err != nil {
+			return nil, err
+		}
+		if len(news) == 0 {
+			return nil, sql.ErrNoRows
+		}
+		if err := s.embed(news); err != nil {
+			return nil, err
+		}
+		err = database.DB.QueryRow("SELECT vector FROM news_embeddings WHERE news_id = ? AND model = ?", newsID, model).Scan(&blob)
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return rank(decode(blob), model, newsID, limit)
+}
+
+// embed 计算一批新闻的向量并写入数据库
+func (s *Searcher) embed(news []models.News) error {
+	texts := make([]string, len(news))
+	for i, n := range news {
+		texts[i] = embedText(n)
+	}
+
+	vectors, err := s.ai.Embed(texts)
+	if err != nil {
+		return err
+	}
+
+	model := s.ai.EmbeddingModel()
+	for i, n := range news {
+		v := vectors[i]
+		if len(v) == 0 {
+			continue
+		}
+		normalize(v)
+		_, err := database.DB.Exec(`INSERT OR REPLACE INTO news_embeddings (news_id, model, dim, vector, created_at)
+			VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)`, n.ID, model, len(v), encode(v))
+		if err != nil {
+			return err
+		}
+	}
+	log.Printf("Embedded %d news with %s", len(news), model)
+	return nil
+}
+
+// rank 在进程内计算余弦相似度，返回前 limit 条（向量已归一化，点积即余弦），limit 限制在 1 到 maxResults 之间
+func rank(q []float32, model, exclude string, limit int) ([]Hit, error) {
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > maxResults {
+		limit = maxResults
+	}
+	rows, err := database.DB.Query(`
+		SELECT e.news_id, e.vector FROM news_embeddings e
+		JOIN news n ON n.id = e.news_id
+		WHERE e.model = ? AND e.dim = ? AND n.is_filtered = 0
+	`, model, len(q))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []Hit
+	for rows.Next() {
+		var id string
+		var blob []byte
+		if err := rows.Scan(&id, &blob); err != nil {
+			return nil, err
+		}
+		if id == exclude {
+			continue
+		}
+		hits = append(hits, Hit{NewsID: id, Score: dot(q, decode(blob))})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+func loadNews(clause string, args ...interface{}) ([]models.News, error) {
+	rows, err := database.DB.Query(`
+		SELECT n.id, n.title, COALESCE(n.trans_title, ''), COALESCE(n.summary, ''), COALESCE(n.trans_summary, ''), COALESCE(n.content, '')
+		FROM news n `+clause, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var news []models.News
+	for rows.Next() {
+		var n models.News
+		if err := rows.Scan(&n.ID, &n.Title, &n.TransTitle, &n.Summary, &n.TransSummary, &n.Content); err != nil {
+			return nil, err
+		}
+		news = append(news, n)
+	}
+	return news, rows.Err()
+}
+
+// embedText 参与向量计算的文本：原文和译文标题、摘要及正文片段
+func embedText(n models.News) string {
+	parts := []string{n.Title}
+	for _, p := range []string{n.TransTitle, n.Summary, n.TransSummary} {
+		if p != "" {
+			parts = append(parts, textutil.StripHTML(p))
+		}
+	}
+	if content := textutil.StripHTML(n.Content); content != "" {
+		parts = append(parts, content)
+	}
+	return textutil.Truncate(strings.Join(parts, "\n"), maxInputRunes)
+}
+
+func normalize(v []float32) {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range v {
+		v[i] /= norm
+	}
+}
+
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+// encode 将向量编码为小端 float32 序列
+func encode(v []float32) []byte {
+	buf := make([]byte, 4*len(v))
+	for i, x := range v {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(x))
+	}
+	return buf
+}
+
+func decode(buf []byte) []float32 {
+	v := make([]float32, len(buf)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[i*4:]))
+	}
+	return v
+}
@@ -0,0 +1,61 @@
+package search
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"news-intel-app/internal/database"
+)
+
+func setupDB(t *testing.T) {
+	t.Helper()
+	if err := database.Init(filepath.Join(t.TempDir(), "news.db")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.DB.Close() })
+}
+
+func TestRankLimit(t *testing.T) {
+	setupDB(t)
+	// 三条新闻的向量与查询的相似度依次递减
+	vectors := [][]float32{{1, 0}, {0.8, 0.6}, {0, 1}}
+	for i, v := range vectors {
+		id := fmt.Sprintf("n%d", i)
+		if _, err := database.DB.Exec("INSERT INTO news (id, title, url) VALUES (?, ?, ?)", id, id, "https://example.com/"+id); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := database.DB.Exec("INSERT INTO news_embeddings (news_id, model, dim, vector) VALUES (?, 'm', 2, ?)", id, encode(v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		limit   int
+		exclude string
+		want    []string
+	}{
+		{"negative limit", -1, "", []string{"n0"}},
+		{"zero limit", 0, "", []string{"n0"}},
+		{"limit within hits", 2, "", []string{"n0", "n1"}},
+		{"limit above hits", 50, "", []string{"n0", "n1", "n2"}},
+		{"limit above max", 1000, "", []string{"n0", "n1", "n2"}},
+		{"exclude", 2, "n0", []string{"n1", "n2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, err := rank([]float32{1, 0}, "m", tt.exclude, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, h := range hits {
+				got = append(got, h.NewsID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("rank(limit=%d) = %v, want %v", tt.limit, got, tt.want)
+			}
+		})
+	}
+}
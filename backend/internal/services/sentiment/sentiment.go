@@ -0,0 +1,132 @@
+package sentiment
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+)
+
+// 情感倾向
+const (
+	Positive = "positive"
+	Negative = "negative"
+	Neutral  = "neutral"
+)
+
+// Labels 全部情感倾向，用于 JSON Schema 和筛选校验
+var Labels = []string{Positive, Negative, Neutral}
+
+// 聚合统计默认参数
+const (
+	DefaultDays   = 30
+	topEntities   = 20
+	minEntityNews = 2 // 实体至少被几条已分析的新闻提到才参与统计
+)
+
+var ErrInvalidLabel = errors.New("sentiment must be positive, negative or neutral")
+
+// Valid 是否为支持的情感倾向
+func Valid(label string) bool {
+	switch label {
+	case Positive, Negative, Neutral:
+		return true
+	}
+	return false
+}
+
+// Normalize 规范化 AI 返回的情感倾向和评分：倾向不合法时两者都丢弃；
+// 评分截断到 [-1, 1]，缺失时按倾向取 1 / -1 / 0
+func Normalize(label string, score *float64) (string, *float64) {
+	label = strings.ToLower(strings.TrimSpace(label))
+	if !Valid(label) {
+		return "", nil
+	}
+	var v float64
+	if score != nil && !math.IsNaN(*score) {
+		v = math.Max(-1, math.Min(1, *score))
+	} else {
+		switch label {
+		case Positive:
+			v = 1
+		case Negative:
+			v = -1
+		}
+	}
+	v = math.Round(v*100) / 100
+	return label, &v
+}
+
+// FilterClause 按情感倾向筛选新闻（命中任一倾向）的 SQL 条件
+func FilterClause(labels []string) (string, []interface{}, error) {
+	if len(labels) == 0 {
+		return "", nil, nil
+	}
+	placeholders := make([]string, len(labels))
+	args := make([]interface{}, len(labels))
+	for i, label := range labels {
+		label = strings.ToLower(label)
+		if !Valid(label) {
+			return "", nil, ErrInvalidLabel
+		}
+		placeholders[i] = "?"
+		args[i] = label
+	}
+	return fmt.Sprintf("sentiment IN (%s)", strings.Join(placeholders, ",")), args, nil
+}
+
+// Stats 最近 days 天已分析情感的新闻按整体、来源和实体（提到最多的实体）聚合
+func Stats(days int) (models.SentimentStats, error) {
+	stats := models.SentimentStats{Days: days, BySource: []models.SentimentGroup{}, ByEntity: []models.SentimentGroup{}}
+	since := fmt.Sprintf("-%d days", days)
+
+	overall, err := groups(`
+		SELECT '', COUNT(*), SUM(sentiment = 'positive'), SUM(sentiment = 'negative'), SUM(sentiment = 'neutral'), COALESCE(AVG(sentiment_score), 0)
+		FROM news WHERE sentiment IN ('positive', 'negative', 'neutral') AND created_at > datetime('now', ?)
+		HAVING COUNT(*) > 0
+	`, since)
+	if err != nil {
+		return stats, err
+	}
+	if len(overall) > 0 {
+		stats.Overall = overall[0]
+	}
+
+	if stats.BySource, err = groups(`
+		SELECT COALESCE(source, ''), COUNT(*), SUM(sentiment = 'positive'), SUM(sentiment = 'negative'), SUM(sentiment = 'neutral'), COALESCE(AVG(sentiment_score), 0)
+		FROM news WHERE sentiment IN ('positive', 'negative', 'neutral') AND created_at > datetime('now', ?)
+		GROUP BY source ORDER BY COUNT(*) DESC
+	`, since); err != nil {
+		return stats, err
+	}
+
+	stats.ByEntity, err = groups(`
+		SELECT e.name, COUNT(*), SUM(n.sentiment = 'positive'), SUM(n.sentiment = 'negative'), SUM(n.sentiment = 'neutral'), COALESCE(AVG(n.sentiment_score), 0)
+		FROM news_entities ne JOIN entities e ON e.id = ne.entity_id JOIN news n ON n.id = ne.news_id
+		WHERE n.sentiment IN ('positive', 'negative', 'neutral') AND n.created_at > datetime('now', ?)
+		GROUP BY e.id HAVING COUNT(*) >= ? ORDER BY COUNT(*) DESC LIMIT ?
+	`, since, minEntityNews, topEntities)
+	return stats, err
+}
+
+func groups(query string, args ...interface{}) ([]models.SentimentGroup, error) {
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.SentimentGroup{}
+	for rows.Next() {
+		var g models.SentimentGroup
+		if err := rows.Scan(&g.Key, &g.Count, &g.Positive, &g.Negative, &g.Neutral, &g.AvgScore); err != nil {
+			return nil, err
+		}
+		g.AvgScore = math.Round(g.AvgScore*100) / 100
+		list = append(list, g)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,436 @@
+package stories
+
+import (
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/ai"
+	"news-intel-app/internal/textutil"
+
+	"github.com/google/uuid"
+)
+
+const (
+	windowHours         = 48  // 参与聚类的新闻时间窗口
+	similarityThreshold = 0.3 // 加入已有故事的最低相似度
+	maxSummaryItems     = 8   // 生成故事摘要时最多使用的报道数
+)
+
+// Clusterer 故事聚类器：把同一事件的多篇报道聚成一个故事
+type Clusterer struct {
+	ai *ai.AIService
+	mu sync.Mutex
+}
+
+func New(aiSvc *ai.AIService) *Clusterer {
+	return &Clusterer{ai: aiSvc}
+}
+
+type doc struct {
+	news models.News
+	vec  map[string]float64
+	norm float64
+}
+
+type cluster struct {
+	docs     []*doc
+	centroid map[string]float64
+}
+
+// Run 对最近的新闻重新聚类，并为成员发生变化的故事重新生成摘要
+func (c *Clusterer) Run() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	news, err := loadRecent()
+	if err != nil {
+		return err
+	}
+	if len(news) == 0 {
+		return nil
+	}
+
+	clusters := clusterDocs(vectorize(news))
+	return c.save(news, clusters)
+}
+
+func loadRecent() ([]models.News, error) {
+	rows, err := database.DB.Query(`
+		SELECT id, title, content, trans_title, source, url, published_at, story_id
+		FROM news WHERE is_filtered = 0 AND created_at > datetime('now', ?)
+		ORDER BY published_at ASC
+	`, fmt.Sprintf("-%d hours", windowHours))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var news []models.News
+	for rows.Next() {
+		var n models.News
+		var content, transTitle, storyID sql.NullString
+		var publishedAt sql.NullTime
+		if err := rows.Scan(&n.ID, &n.Title, &content, &transTitle, &n.Source, &n.URL, &publishedAt, &storyID); err != nil {
+			continue
+		}
+		n.Content = content.String
+		n.TransTitle = transTitle.String
+		n.StoryID = storyID.String
+		n.PublishedAt = publishedAt.Time
+		news = append(news, n)
+	}
+	return news, rows.Err()
+}
+
+// vectorize 计算 TF-IDF 向量（标题权重加倍，翻译标题用于跨语言匹配）
+func vectorize(news []models.News) []*doc {
+	docs := make([]*doc, len(news))
+	df := make(map[string]int)
+
+	for i, n := range news {
+		tf := make(map[string]float64)
+		for _, t := range textutil.Tokenize(n.Title) {
+			tf[t] += 2
+		}
+		for _, t := range textutil.Tokenize(n.TransTitle) {
+			tf[t]++
+		}
+		for _, t := range textutil.Tokenize(textutil.Truncate(textutil.StripHTML(n.Content), 300)) {
+			tf[t]++
+		}
+		for t := range tf {
+			df[t]++
+		}
+		docs[i] = &doc{news: n, vec: tf}
+	}
+
+	total := float64(len(news))
+	for _, d := range docs {
+		var sum float64
+		for t, w := range d.vec {
+			d.vec[t] = w * (math.Log((1+total)/(1+float64(df[t]))) + 1)
+			sum += d.vec[t] * d.vec[t]
+		}
+		d.norm = math.Sqrt(sum)
+	}
+	return docs
+}
+
+// clusterDocs 按时间顺序贪心聚类：与最相似的故事质心相似度超过阈值则加入，否则新建
+func clusterDocs(docs []*doc) []*cluster {
+	var clusters []*cluster
+	for _, d := range docs {
+		if d.norm == 0 {
+			continue
+		}
+		var best *cluster
+		bestSim := similarityThreshold
+		for _, cl := range clusters {
+			if sim := cosine(d.vec, d.norm, cl.centroid); sim >= bestSim {
+				best, bestSim = cl, sim
+			}
+		}
+		if best == nil {
+			best = &cluster{centroid: make(map[string]float64)}
+			clusters = append(clusters, best)
+		}
+		best.docs = append(best.docs, d)
+		for t, w := range d.vec {
+			best.centroid[t] += w / d.norm
+		}
+	}
+	return clusters
+}
+
+func cosine(vec map[string]float64, norm float64, centroid map[string]float64) float64 {
+	var dot, cnorm float64
+	for _, w := range centroid {
+		cnorm += w * w
+	}
+	if cnorm == 0 {
+		return 0
+	}
+	for t, w := range vec {
+		dot += w * centroid[t]
+	}
+	return dot / (norm * math.Sqrt(cnorm))
+}
+
+// save 写入聚类结果；成员过半已属于某个故事时沿用该故事 ID，保持 ID 稳定
+func (c *Clusterer) save(news []models.News, clusters []*cluster) error {
+	assigned := make(map[string]string) // news_id -> story_id
+	usedIDs := make(map[string]bool)
+
+	for _, cl := range clusters {
+		if len(cl.docs) < 2 {
+			continue
+		}
+
+		storyID := previousStoryID(cl, usedIDs)
+		if storyID == "" {
+			storyID = uuid.New().String()
+		}
+		usedIDs[storyID] = true
+
+		members := make([]models.News, len(cl.docs))
+		ids := make([]string, len(cl.docs))
+		for i, d := range cl.docs {
+			members[i] = d.news
+			ids[i] = d.news.ID
+			assigned[d.news.ID] = storyID
+		}
+
+		if err := c.saveStory(storyID, members, signature(ids)); err != nil {
+			log.Printf("Failed to save story %s: %v", storyID, err)
+		}
+	}
+
+	// 更新窗口内新闻的故事归属
+	for _, n := range news {
+		storyID := assigned[n.ID]
+		if storyID == n.StoryID {
+			continue
+		}
+		var value interface{}
+		if storyID != "" {
+			value = storyID
+		}
+		if _, err := database.DB.Exec("UPDATE news SET story_id = ? WHERE id = ?", value, n.ID); err != nil {
+			return err
+		}
+	}
+
+	// 刷新计数并清理没有成员的故事
+	if _, err := database.DB.Exec(`UPDATE stories SET news_count = (SELECT COUNT(*) FROM news WHERE news.story_id = stories.id)`); err != nil {
+		return err
+	}
+	_, err := database.DB.Exec("DELETE FROM stories WHERE news_count = 0")
+	return err
+}
+
+func previousStoryID(cl *cluster, used map[string]bool) string {
+	votes := make(map[string]int)
+	for _, d := range cl.docs {
+		if d.news.StoryID != "" && !used[d.news.StoryID] {
+			votes[d.news.StoryID]++
+		}
+	}
+	var best string
+	for id, v := range votes {
+		if v > votes[best] || (v == votes[best] && id < best) {
+			best = id
+		}
+	}
+	return best
+}
+
+func signature(ids []string) string {
+	sorted := append([]string{}, ids...)
+	sort.Strings(sorted)
+	sum := sha1.Sum([]byte(strings.Join(sorted, ",")))
+	return hex.EncodeToString(sum[:])
+}
+
+// saveStory 写入故事；成员变化时重新生成 AI 摘要
+func (c *Clusterer) saveStory(id string, members []models.News, sig string) error {
+	firstSeen, lastSeen := members[0].PublishedAt, members[0].PublishedAt
+	for _, n := range members {
+		if n.PublishedAt.Before(firstSeen) {
+			firstSeen = n.PublishedAt
+		}
+		if n.PublishedAt.After(lastSeen) {
+			lastSeen = n.PublishedAt
+		}
+	}
+
+	var oldSig, oldSummary sql.NullString
+	err := database.DB.QueryRow("SELECT signature, summary FROM stories WHERE id = ?", id).Scan(&oldSig, &oldSummary)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil && oldSig.String == sig && oldSummary.String != "" {
+		_, err := database.DB.Exec("UPDATE stories SET first_seen_at = ?, last_seen_at = ? WHERE id = ?", firstSeen, lastSeen, id)
+		return err
+	}
+
+	title, summary := c.summarize(members)
+	_, err = database.DB.Exec(`
+		INSERT INTO stories (id, title, summary, news_count, signature, first_seen_at, last_seen_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET title = excluded.title, summary = excluded.summary, news_count = excluded.news_count,
+			signature = excluded.signature, first_seen_at = excluded.first_seen_at, last_seen_at = excluded.last_seen_at, updated_at = excluded.updated_at
+	`, id, title, summary, len(members), sig, firstSeen, lastSeen, time.Now())
+	return err
+}
+
+func (c *Clusterer) summarize(members []models.News) (string, string) {
+	// 最新的报道优先
+	items := append([]models.News{}, members...)
+	sort.Slice(items, func(i, j int) bool { return items[i].PublishedAt.After(items[j].PublishedAt) })
+	if len(items) > maxSummaryItems {
+		items = items[:maxSummaryItems]
+	}
+
+	title, summary, err := c.ai.SummarizeStory(items)
+	if err != nil {
+		log.Printf("Failed to summarize story: %v", err)
+	}
+	if title == "" {
+		title = items[0].TransTitle
+		if title == "" {
+			title = items[0].Title
+		}
+	}
+	return title, summary
+}
+
+// List 获取最近的故事（含成员新闻）
+func List(hours, limit int) ([]models.Story, error) {
+	rows, err := database.DB.Query(`
+		SELECT id, title, summary, news_count, first_seen_at, last_seen_at FROM stories
+		WHERE last_seen_at > datetime('now', ?)
+		ORDER BY news_count DESC, last_seen_at DESC LIMIT ?
+	`, fmt.Sprintf("-%d hours", hours), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []models.Story
+	for rows.Next() {
+		st, err := scanStory(rows)
+		if err != nil {
+			continue
+		}
+		list = append(list, st)
+	}
+	rows.Close()
+
+	for i := range list {
+		if err := loadMembers(&list[i]); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// Get 获取单个故事
+func Get(id string) (*models.Story, error) {
+	row := database.DB.QueryRow("SELECT id, title, summary, news_count, first_seen_at, last_seen_at FROM stories WHERE id = ?", id)
+	st, err := scanStory(row)
+	if err != nil {
+		return nil, err
+	}
+	if err := loadMembers(&st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanStory(row scanner) (models.Story, error) {
+	var st models.Story
+	var title, summary sql.NullString
+	var firstSeen, lastSeen sql.NullTime
+	err := row.Scan(&st.ID, &title, &summary, &st.NewsCount, &firstSeen, &lastSeen)
+	st.Title = title.String
+	st.Summary = summary.String
+	st.FirstSeenAt = firstSeen.Time
+	st.LastSeenAt = lastSeen.Time
+	return st, err
+}
+
+func loadMembers(st *models.Story) error {
+	rows, err := database.DB.Query(`
+		SELECT id, title, url, source, category, trans_title, trans_summary, published_at
+		FROM news WHERE story_id = ? ORDER BY published_at DESC
+	`, st.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var n models.News
+		var transTitle, transSummary sql.NullString
+		var publishedAt sql.NullTime
+		if err := rows.Scan(&n.ID, &n.Title, &n.URL, &n.Source, &n.Category, &transTitle, &transSummary, &publishedAt); err != nil {
+			continue
+		}
+		n.TransTitle = transTitle.String
+		n.TransSummary = transSummary.String
+		n.PublishedAt = publishedAt.Time
+		n.StoryID = st.ID
+		st.News = append(st.News, n)
+		if !seen[n.Source] {
+			seen[n.Source] = true
+			st.Sources = append(st.Sources, n.Source)
+		}
+	}
+	return rows.Err()
+}
+
+// Group 将待推送的新闻按故事分组：同一故事在本批中有 2 条以上时合并为一个故事块，其余作为单条新闻
+func Group(news []models.News) ([]models.Story, []models.News) {
+	counts := make(map[string]int)
+	for _, n := range news {
+		if n.StoryID != "" {
+			counts[n.StoryID]++
+		}
+	}
+
+	var groups []models.Story
+	index := make(map[string]int)
+	var singles []models.News
+	for _, n := range news {
+		if counts[n.StoryID] < 2 {
+			singles = append(singles, n)
+			continue
+		}
+		i, ok := index[n.StoryID]
+		if !ok {
+			st := models.Story{ID: n.StoryID}
+			var title, summary sql.NullString
+			database.DB.QueryRow("SELECT title, summary FROM stories WHERE id = ?", n.StoryID).Scan(&title, &summary)
+			st.Title = title.String
+			st.Summary = summary.String
+			if st.Title == "" {
+				st.Title = n.TransTitle
+				if st.Title == "" {
+					st.Title = n.Title
+				}
+			}
+			i = len(groups)
+			index[n.StoryID] = i
+			groups = append(groups, st)
+		}
+		groups[i].News = append(groups[i].News, n)
+		groups[i].NewsCount++
+		if !contains(groups[i].Sources, n.Source) {
+			groups[i].Sources = append(groups[i].Sources, n.Source)
+		}
+	}
+	return groups, singles
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
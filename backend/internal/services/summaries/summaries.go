@@ -0,0 +1,225 @@
+package summaries
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// 摘要格式
+const (
+	FormatParagraph = "paragraph"
+	FormatBullets   = "bullets"
+	FormatOneLine   = "one_line"
+)
+
+var (
+	ErrNotFound = errors.New("summary style not found")
+	ErrExists   = errors.New("summary style name already exists")
+)
+
+// defaults 内置摘要样式（只在不存在时写入，之后可在页面修改）
+var defaults = []models.SummaryStyle{
+	{Name: "tldr", Label: "一句话速览", Format: FormatOneLine, MaxChars: 40, Tone: "简洁直接"},
+	{Name: "bullets", Label: "三个要点", Format: FormatBullets, MaxChars: 40, Points: 3, Tone: "客观"},
+	{Name: "paragraph", Label: "段落摘要", Format: FormatParagraph, MaxChars: 200, Tone: "客观完整，适合存档"},
+}
+
+// InitDefaults 写入内置摘要样式
+func InitDefaults() error {
+	for _, st := range defaults {
+		now := time.Now()
+		_, err := database.DB.Exec(`
+			INSERT INTO summary_styles (id, name, label, format, max_chars, points, tone, instruction, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT(name) DO NOTHING
+		`, uuid.New().String(), st.Name, st.Label, st.Format, st.MaxChars, st.Points, st.Tone, st.Instruction, now, now)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Instruction 把样式转换为提示词中的摘要要求
+func Instruction(st *models.SummaryStyle) string {
+	var lines []string
+	switch st.Format {
+	case FormatOneLine:
+		lines = append(lines, fmt.Sprintf("- 用一句话概括核心事实，不超过 %d 字，不换行", st.MaxChars))
+	case FormatBullets:
+		points := st.Points
+		if points <= 0 {
+			points = 3
+		}
+		lines = append(lines, fmt.Sprintf("- 列出 %d 条要点，每条单独一行并以“- ”开头，每条不超过 %d 字", points, st.MaxChars))
+	default:
+		lines = append(lines, fmt.Sprintf("- 写成一段连贯的文字，不超过 %d 字", st.MaxChars))
+	}
+	if st.Tone != "" {
+		lines = append(lines, "- 语气："+st.Tone)
+	}
+	if st.Instruction != "" {
+		lines = append(lines, "- "+st.Instruction)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// List 摘要样式列表
+func List() ([]models.SummaryStyle, error) {
+	rows, err := database.DB.Query("SELECT " + styleColumns + " FROM summary_styles ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.SummaryStyle{}
+	for rows.Next() {
+		st, err := scanStyle(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, st)
+	}
+	return list, rows.Err()
+}
+
+// Get 按名称获取摘要样式
+func Get(name string) (*models.SummaryStyle, error) {
+	st, err := scanStyle(database.DB.QueryRow("SELECT "+styleColumns+" FROM summary_styles WHERE name = ?", name))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// Create 新增摘要样式
+func Create(st *models.SummaryStyle) error {
+	var n int
+	database.DB.QueryRow("SELECT COUNT(*) FROM summary_styles WHERE name = ?", st.Name).Scan(&n)
+	if n > 0 {
+		return ErrExists
+	}
+	st.ID = uuid.New().String()
+	st.CreatedAt = time.Now()
+	st.UpdatedAt = st.CreatedAt
+	_, err := database.DB.Exec(`
+		INSERT INTO summary_styles (id, name, label, format, max_chars, points, tone, instruction, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, st.ID, st.Name, st.Label, st.Format, st.MaxChars, st.Points, st.Tone, st.Instruction, st.CreatedAt, st.UpdatedAt)
+	return err
+}
+
+// Update 修改摘要样式（名称不可修改）；已生成的该样式摘要随之失效
+func Update(st models.SummaryStyle) error {
+	var name string
+	if err := database.DB.QueryRow("SELECT name FROM summary_styles WHERE id = ?", st.ID).Scan(&name); err != nil {
+		return ErrNotFound
+	}
+	_, err := database.DB.Exec(`
+		UPDATE summary_styles SET label = ?, format = ?, max_chars = ?, points = ?, tone = ?, instruction = ?, updated_at = ? WHERE id = ?
+	`, st.Label, st.Format, st.MaxChars, st.Points, st.Tone, st.Instruction, time.Now(), st.ID)
+	if err != nil {
+		return err
+	}
+	_, err = database.DB.Exec("DELETE FROM news_summaries WHERE style = ?", name)
+	return err
+}
+
+// Delete 删除摘要样式及按该样式生成的摘要
+func Delete(id string) error {
+	var name string
+	if err := database.DB.QueryRow("SELECT name FROM summary_styles WHERE id = ?", id).Scan(&name); err != nil {
+		return nil
+	}
+	if _, err := database.DB.Exec("DELETE FROM summary_styles WHERE id = ?", id); err != nil {
+		return err
+	}
+	_, err := database.DB.Exec("DELETE FROM news_summaries WHERE style = ?", name)
+	return err
+}
+
+// DeleteForNews 删除新闻的全部样式摘要
+func DeleteForNews(newsID string) error {
+	_, err := database.DB.Exec("DELETE FROM news_summaries WHERE news_id = ?", newsID)
+	return err
+}
+
+// Load 批量读取新闻在某一样式和语言下已生成的摘要，返回 news_id -> 摘要
+func Load(newsIDs []string, style, lang string) (map[string]string, error) {
+	result := make(map[string]string)
+	if len(newsIDs) == 0 {
+		return result, nil
+	}
+	args := []interface{}{style, lang}
+	for _, id := range newsIDs {
+		args = append(args, id)
+	}
+	rows, err := database.DB.Query(`
+		SELECT news_id, summary FROM news_summaries WHERE style = ? AND lang = ? AND news_id IN (?`+strings.Repeat(",?", len(newsIDs)-1)+`)
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, summary string
+		if err := rows.Scan(&id, &summary); err != nil {
+			return nil, err
+		}
+		result[id] = summary
+	}
+	return result, rows.Err()
+}
+
+// Save 保存新闻某一样式和语言的摘要（覆盖已有）
+func Save(newsID, style, lang, summary string) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO news_summaries (news_id, style, lang, summary, created_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(news_id, style, lang) DO UPDATE SET summary = excluded.summary, created_at = excluded.created_at
+	`, newsID, style, lang, summary, time.Now())
+	return err
+}
+
+// ForNews 新闻已生成的全部样式摘要
+func ForNews(newsID string) ([]models.NewsSummary, error) {
+	rows, err := database.DB.Query(`
+		SELECT news_id, style, lang, summary, created_at FROM news_summaries WHERE news_id = ? ORDER BY style, lang
+	`, newsID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.NewsSummary{}
+	for rows.Next() {
+		var s models.NewsSummary
+		if err := rows.Scan(&s.NewsID, &s.Style, &s.Lang, &s.Summary, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, rows.Err()
+}
+
+const styleColumns = "id, name, COALESCE(label, ''), format, max_chars, points, COALESCE(tone, ''), COALESCE(instruction, ''), created_at, updated_at"
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanStyle(row scanner) (models.SummaryStyle, error) {
+	var st models.SummaryStyle
+	err := row.Scan(&st.ID, &st.Name, &st.Label, &st.Format, &st.MaxChars, &st.Points, &st.Tone, &st.Instruction, &st.CreatedAt, &st.UpdatedAt)
+	return st, err
+}
@@ -0,0 +1,170 @@
+package taxonomy
+
+import (
+	"fmt"
+	"strings"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/textutil"
+
+	"github.com/google/uuid"
+)
+
+const (
+	StatusApproved = "approved"
+	StatusPending  = "pending"
+)
+
+// TagsColumnSQL 从 news_tags 聚合出新闻的已审核标签（逗号分隔），用于替代旧的 news.tags 列
+const TagsColumnSQL = `(SELECT GROUP_CONCAT(t.name) FROM news_tags nt JOIN tags t ON t.id = nt.tag_id WHERE nt.news_id = news.id AND t.status = 'approved')`
+
+// Taxonomy 标签词表快照
+type Taxonomy struct {
+	approved []models.Tag
+	lookup   map[string]models.Tag // 规范化的名称/同义词 -> 标签
+}
+
+// Load 从数据库加载标签词表（包含待审核标签，避免重复提议）
+func Load() (*Taxonomy, error) {
+	rows, err := database.DB.Query("SELECT id, name, synonyms, status FROM tags ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	t := &Taxonomy{lookup: make(map[string]models.Tag)}
+	for rows.Next() {
+		var tag models.Tag
+		var synonyms *string
+		if err := rows.Scan(&tag.ID, &tag.Name, &synonyms, &tag.Status); err != nil {
+			continue
+		}
+		if synonyms != nil {
+			tag.Synonyms = *synonyms
+		}
+		if tag.Status == StatusApproved {
+			t.approved = append(t.approved, tag)
+		}
+		t.lookup[normalize(tag.Name)] = tag
+		for _, syn := range textutil.SplitList(tag.Synonyms) {
+			if _, exists := t.lookup[normalize(syn)]; !exists {
+				t.lookup[normalize(syn)] = tag
+			}
+		}
+	}
+	return t, rows.Err()
+}
+
+// Empty 词表中是否没有可用标签
+func (t *Taxonomy) Empty() bool {
+	return len(t.approved) == 0
+}
+
+// PromptList 生成供 AI 选择的标签列表
+func (t *Taxonomy) PromptList() string {
+	var sb strings.Builder
+	for _, tag := range t.approved {
+		sb.WriteString("- " + tag.Name)
+		if syns := textutil.SplitList(tag.Synonyms); len(syns) > 0 {
+			sb.WriteString("（同义词: " + strings.Join(syns, ", ") + "）")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Resolve 将 AI 返回的标签映射到词表中的标签
+func (t *Taxonomy) Resolve(raw string) (models.Tag, bool) {
+	tag, ok := t.lookup[normalize(raw)]
+	return tag, ok
+}
+
+// AssignTags 为新闻写入标签；词表外的标签在 allowNew 时作为待审核标签提议，否则丢弃
+// 返回最终关联的标签名
+func (t *Taxonomy) AssignTags(newsID string, raw []string, allowNew bool) ([]string, error) {
+	var names []string
+	tagIDs := make(map[string]bool)
+
+	for _, r := range raw {
+		r = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(r), "#"))
+		if r == "" {
+			continue
+		}
+		tag, ok := t.Resolve(r)
+		if !ok {
+			if !allowNew {
+				continue
+			}
+			proposed, err := proposeTag(r)
+			if err != nil {
+				return names, err
+			}
+			tag = proposed
+			t.lookup[normalize(r)] = tag
+		}
+		if tagIDs[tag.ID] {
+			continue
+		}
+		tagIDs[tag.ID] = true
+		names = append(names, tag.Name)
+	}
+
+	if _, err := database.DB.Exec("DELETE FROM news_tags WHERE news_id = ?", newsID); err != nil {
+		return nil, err
+	}
+	for id := range tagIDs {
+		if _, err := database.DB.Exec("INSERT OR IGNORE INTO news_tags (news_id, tag_id) VALUES (?, ?)", newsID, id); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+// proposeTag 创建待审核标签（同名标签已存在时直接返回）
+func proposeTag(name string) (models.Tag, error) {
+	tag := models.Tag{ID: uuid.New().String(), Name: name, Status: StatusPending}
+	_, err := database.DB.Exec("INSERT OR IGNORE INTO tags (id, name, synonyms, status) VALUES (?, ?, '', ?)", tag.ID, tag.Name, tag.Status)
+	if err != nil {
+		return tag, err
+	}
+	err = database.DB.QueryRow("SELECT id, status FROM tags WHERE name = ?", name).Scan(&tag.ID, &tag.Status)
+	return tag, err
+}
+
+// FilterClause 构造按标签筛选新闻的 SQL 条件（命中任一标签即可），标签名和同义词都可用于筛选
+func FilterClause(tags []string) (string, []interface{}) {
+	if len(tags) == 0 {
+		return "", nil
+	}
+	t, err := Load()
+	if err != nil {
+		t = &Taxonomy{lookup: make(map[string]models.Tag)}
+	}
+	return t.FilterClause(tags)
+}
+
+// FilterClause 按词表把标签名或同义词解析为标签 ID 后构造筛选条件；无法解析的按标签名匹配
+func (t *Taxonomy) FilterClause(tags []string) (string, []interface{}) {
+	if len(tags) == 0 {
+		return "", nil
+	}
+	placeholders := make([]string, len(tags))
+	args := make([]interface{}, len(tags))
+	for i, name := range tags {
+		placeholders[i] = "?"
+		if tag, ok := t.Resolve(name); ok {
+			args[i] = tag.ID
+		} else {
+			args[i] = normalize(name)
+		}
+	}
+	list := strings.Join(placeholders, ",")
+	clause := fmt.Sprintf(`id IN (SELECT nt.news_id FROM news_tags nt JOIN tags t ON t.id = nt.tag_id
+		WHERE t.status = 'approved' AND (t.id IN (%s) OR LOWER(t.name) IN (%s)))`, list, list)
+	return clause, append(args, args...)
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
@@ -0,0 +1,43 @@
+package taxonomy
+
+import (
+	"reflect"
+	"testing"
+
+	"news-intel-app/internal/models"
+)
+
+func TestFilterClauseResolvesSynonyms(t *testing.T) {
+	ai := models.Tag{ID: "t1", Name: "AI", Synonyms: "人工智能, machine learning", Status: StatusApproved}
+	tax := &Taxonomy{lookup: map[string]models.Tag{
+		"ai":               ai,
+		"人工智能":             ai,
+		"machine learning": ai,
+	}}
+
+	tests := []struct {
+		name string
+		tags []string
+		want []interface{}
+	}{
+		{"name", []string{"AI"}, []interface{}{"t1", "t1"}},
+		{"synonym", []string{"人工智能"}, []interface{}{"t1", "t1"}},
+		{"synonym with spacing", []string{"Machine   Learning"}, []interface{}{"t1", "t1"}},
+		{"unknown", []string{"Crypto"}, []interface{}{"crypto", "crypto"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clause, args := tax.FilterClause(tt.tags)
+			if clause == "" {
+				t.Fatal("empty clause")
+			}
+			if !reflect.DeepEqual(args, tt.want) {
+				t.Errorf("args = %v, want %v", args, tt.want)
+			}
+		})
+	}
+
+	if clause, args := tax.FilterClause(nil); clause != "" || args != nil {
+		t.Errorf("FilterClause(nil) = %q, %v", clause, args)
+	}
+}
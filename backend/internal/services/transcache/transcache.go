@@ -0,0 +1,176 @@
+package transcache
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+)
+
+// 默认缓存有效期和条数上限
+const (
+	DefaultTTLDays    = 30
+	DefaultMaxEntries = 20000
+)
+
+// Key 由调用类型、模型、目标语言、提示词版本和原文等拼出缓存键
+func Key(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get 读取未过期的缓存，命中时更新命中次数
+func Get(key string) (string, bool) {
+	settings := GetSettings()
+	if !settings.Enabled {
+		return "", false
+	}
+
+	var value string
+	var createdAt time.Time
+	err := database.DB.QueryRow("SELECT value, created_at FROM translation_cache WHERE key = ?", key).Scan(&value, &createdAt)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Failed to read translation cache: %v", err)
+		}
+		return "", false
+	}
+	if settings.TTLDays > 0 && time.Since(createdAt) > time.Duration(settings.TTLDays)*24*time.Hour {
+		return "", false
+	}
+	database.DB.Exec("UPDATE translation_cache SET hits = hits + 1, last_used_at = ? WHERE key = ?", time.Now(), key)
+	return value, true
+}
+
+// GetJSON 读取缓存并解析为 JSON
+func GetJSON(key string, v interface{}) bool {
+	value, ok := Get(key)
+	if !ok {
+		return false
+	}
+	return json.Unmarshal([]byte(value), v) == nil
+}
+
+// Put 写入缓存
+func Put(key, kind, model, value string) {
+	if value == "" || !GetSettings().Enabled {
+		return
+	}
+	now := time.Now()
+	_, err := database.DB.Exec(`INSERT OR REPLACE INTO translation_cache (key, kind, model, value, hits, created_at, last_used_at)
+		VALUES (?, ?, ?, ?, 0, ?, ?)`, key, kind, model, value, now, now)
+	if err != nil {
+		log.Printf("Failed to write translation cache: %v", err)
+	}
+}
+
+// PutJSON 以 JSON 格式写入缓存
+func PutJSON(key, kind, model string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	Put(key, kind, model, string(data))
+}
+
+// GetSettings 读取缓存设置
+func GetSettings() models.CacheSettings {
+	settings := models.CacheSettings{Enabled: true, TTLDays: DefaultTTLDays, MaxEntries: DefaultMaxEntries}
+	var enabled, ttl, maxEntries string
+	database.DB.QueryRow("SELECT value FROM settings WHERE key = 'translation_cache_enabled'").Scan(&enabled)
+	database.DB.QueryRow("SELECT value FROM settings WHERE key = 'translation_cache_ttl_days'").Scan(&ttl)
+	database.DB.QueryRow("SELECT value FROM settings WHERE key = 'translation_cache_max_entries'").Scan(&maxEntries)
+	if enabled != "" {
+		settings.Enabled = enabled == "true"
+	}
+	if v, err := strconv.Atoi(ttl); err == nil {
+		settings.TTLDays = v
+	}
+	if v, err := strconv.Atoi(maxEntries); err == nil {
+		settings.MaxEntries = v
+	}
+	return settings
+}
+
+// SaveSettings 保存缓存设置
+func SaveSettings(settings models.CacheSettings) error {
+	values := map[string]string{
+		"translation_cache_enabled":     strconv.FormatBool(settings.Enabled),
+		"translation_cache_ttl_days":    strconv.Itoa(settings.TTLDays),
+		"translation_cache_max_entries": strconv.Itoa(settings.MaxEntries),
+	}
+	for key, value := range values {
+		if _, err := database.DB.Exec("INSERT OR REPLACE INTO settings (key, value) VALUES (?, ?)", key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stats 缓存统计
+func Stats() (*models.CacheStats, error) {
+	stats := &models.CacheStats{Settings: GetSettings(), ByKind: map[string]int{}}
+	rows, err := database.DB.Query("SELECT kind, COUNT(*), COALESCE(SUM(hits), 0) FROM translation_cache GROUP BY kind")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var kind string
+		var count, hits int
+		if err := rows.Scan(&kind, &count, &hits); err != nil {
+			return nil, err
+		}
+		stats.ByKind[kind] = count
+		stats.Entries += count
+		stats.Hits += hits
+	}
+	return stats, rows.Err()
+}
+
+// Prune 删除过期缓存，并按最近使用时间淘汰超出上限的条目
+func Prune() (int64, error) {
+	settings := GetSettings()
+	var removed int64
+	if settings.TTLDays > 0 {
+		res, err := database.DB.Exec("DELETE FROM translation_cache WHERE created_at < ?", time.Now().AddDate(0, 0, -settings.TTLDays))
+		if err != nil {
+			return removed, err
+		}
+		n, _ := res.RowsAffected()
+		removed += n
+	}
+	if settings.MaxEntries > 0 {
+		res, err := database.DB.Exec(`DELETE FROM translation_cache WHERE key IN (
+			SELECT key FROM translation_cache ORDER BY last_used_at DESC LIMIT -1 OFFSET ?
+		)`, settings.MaxEntries)
+		if err != nil {
+			return removed, err
+		}
+		n, _ := res.RowsAffected()
+		removed += n
+	}
+	return removed, nil
+}
+
+// Purge 清空缓存（kind 不为空时只清除该类型）
+func Purge(kind string) (int64, error) {
+	var res sql.Result
+	var err error
+	if kind == "" {
+		res, err = database.DB.Exec("DELETE FROM translation_cache")
+	} else {
+		res, err = database.DB.Exec("DELETE FROM translation_cache WHERE kind = ?", kind)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
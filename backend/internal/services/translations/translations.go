@@ -0,0 +1,73 @@
+package translations
+
+import (
+	"strings"
+	"time"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+)
+
+// Save 写入新闻某一语言的译文；content 为空时保留已有的全文译文
+func Save(newsID string, t models.Translation) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO news_translations (news_id, lang, title, summary, content, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(news_id, lang) DO UPDATE SET
+			title = excluded.title,
+			summary = excluded.summary,
+			content = CASE WHEN excluded.content != '' THEN excluded.content ELSE news_translations.content END,
+			updated_at = excluded.updated_at
+	`, newsID, t.Lang, t.Title, t.Summary, t.Content, time.Now())
+	return err
+}
+
+// SaveContent 只写入某一语言的全文译文，不影响已有的标题和摘要
+func SaveContent(newsID, lang, content string) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO news_translations (news_id, lang, content, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(news_id, lang) DO UPDATE SET content = excluded.content, updated_at = excluded.updated_at
+	`, newsID, lang, content, time.Now())
+	return err
+}
+
+// Attach 为新闻列表填充各语言译文
+func Attach(news []models.News) error {
+	if len(news) == 0 {
+		return nil
+	}
+	index := make(map[string]int, len(news))
+	args := make([]interface{}, len(news))
+	for i, n := range news {
+		index[n.ID] = i
+		args[i] = n.ID
+	}
+
+	rows, err := database.DB.Query(`
+		SELECT news_id, lang, COALESCE(title, ''), COALESCE(summary, ''), COALESCE(content, '')
+		FROM news_translations WHERE news_id IN (?`+strings.Repeat(",?", len(news)-1)+`)
+	`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var newsID string
+		var t models.Translation
+		if err := rows.Scan(&newsID, &t.Lang, &t.Title, &t.Summary, &t.Content); err != nil {
+			return err
+		}
+		n := &news[index[newsID]]
+		if n.Translations == nil {
+			n.Translations = make(map[string]models.Translation)
+		}
+		n.Translations[t.Lang] = t
+	}
+	return rows.Err()
+}
+
+// Delete 删除新闻的所有译文
+func Delete(newsID string) error {
+	_, err := database.DB.Exec("DELETE FROM news_translations WHERE news_id = ?", newsID)
+	return err
+}
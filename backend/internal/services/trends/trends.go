@@ -0,0 +1,261 @@
+package trends
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/textutil"
+)
+
+// 趋势类型
+const (
+	KindTerm   = "term"   // 标题中的词
+	KindEntity = "entity" // AI 提取的实体
+	KindTag    = "tag"    // 已审核的标签
+)
+
+const (
+	WindowHours  = 6   // 最近窗口长度
+	BaselineDays = 7   // 基线期（最近窗口之前）的天数
+	MinCount     = 3   // 最近窗口内至少被多少条新闻提到
+	MinScore     = 3.0 // 显著性阈值（z 分数）
+	minRatio     = 2.0 // 最近窗口的新闻数至少是基线平均值的倍数
+	maxTrends    = 50  // 保存的趋势条数
+	maxTrendNews = 10  // 每个趋势保存的相关新闻数
+)
+
+// stat 一个词在最近窗口和基线期内被提到的新闻数
+type stat struct {
+	kind, term string
+	count      int
+	baseline   int
+	newsIDs    []string
+}
+
+// Analyze 统计最近窗口和基线期内标题词、实体和标签被提到的新闻数，
+// 返回最近窗口内至少被 MinCount 条新闻提到的全部词（按显著性从高到低），不做显著性筛选
+func Analyze() ([]models.Trend, error) {
+	now := time.Now()
+	windowStart := now.Add(-WindowHours * time.Hour)
+
+	rows, err := database.DB.Query(`
+		SELECT id, title, created_at FROM news
+		WHERE is_filtered = 0 AND created_at > datetime('now', ?)
+		ORDER BY created_at DESC
+	`, fmt.Sprintf("-%d hours", WindowHours+BaselineDays*24))
+	if err != nil {
+		return nil, err
+	}
+	recent := make(map[string]bool) // news_id -> 是否在最近窗口内
+	features := make(map[string][][2]string)
+	earliest := windowStart
+	for rows.Next() {
+		var id, title string
+		var createdAt sql.NullTime
+		if err := rows.Scan(&id, &title, &createdAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		recent[id] = createdAt.Time.After(windowStart)
+		if createdAt.Valid && createdAt.Time.Before(earliest) {
+			earliest = createdAt.Time
+		}
+		for _, token := range titleTerms(title) {
+			features[id] = append(features[id], [2]string{KindTerm, token})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(recent) == 0 {
+		return []models.Trend{}, nil
+	}
+
+	// 实体和标签只统计上面查询到的新闻
+	for _, q := range []struct{ kind, query string }{
+		{KindEntity, `SELECT ne.news_id, e.name FROM news_entities ne JOIN entities e ON e.id = ne.entity_id
+			JOIN news ON news.id = ne.news_id WHERE news.created_at > datetime('now', ?)`},
+		{KindTag, `SELECT nt.news_id, t.name FROM news_tags nt JOIN tags t ON t.id = nt.tag_id
+			JOIN news ON news.id = nt.news_id WHERE t.status = 'approved' AND news.created_at > datetime('now', ?)`},
+	} {
+		rows, err := database.DB.Query(q.query, fmt.Sprintf("-%d hours", WindowHours+BaselineDays*24))
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var id, name string
+			if err := rows.Scan(&id, &name); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if _, ok := recent[id]; ok {
+				features[id] = append(features[id], [2]string{q.kind, name})
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	stats := make(map[[2]string]*stat)
+	for id, list := range features {
+		seen := make(map[[2]string]bool) // 同名不同类型的实体只计一次
+		for _, f := range list {
+			if seen[f] {
+				continue
+			}
+			seen[f] = true
+			st := stats[f]
+			if st == nil {
+				st = &stat{kind: f[0], term: f[1]}
+				stats[f] = st
+			}
+			if recent[id] {
+				st.count++
+				if len(st.newsIDs) < maxTrendNews {
+					st.newsIDs = append(st.newsIDs, id)
+				}
+			} else {
+				st.baseline++
+			}
+		}
+	}
+
+	// 数据不足基线期时按实际覆盖的窗口数计算平均值
+	windows := math.Ceil(windowStart.Sub(earliest).Hours() / WindowHours)
+	if windows < 1 {
+		windows = 1
+	}
+	if windows > BaselineDays*24/WindowHours {
+		windows = BaselineDays * 24 / WindowHours
+	}
+
+	list := []models.Trend{}
+	for _, st := range stats {
+		if st.count < MinCount {
+			continue
+		}
+		mean := float64(st.baseline) / windows
+		list = append(list, models.Trend{
+			Kind:       st.kind,
+			Term:       st.term,
+			Count:      st.count,
+			Baseline:   math.Round(mean*100) / 100,
+			Score:      math.Round(score(st.count, mean)*100) / 100,
+			NewsIDs:    st.newsIDs,
+			ComputedAt: now,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Score != list[j].Score {
+			return list[i].Score > list[j].Score
+		}
+		return list[i].Count > list[j].Count
+	})
+	return list, nil
+}
+
+// score 泊松近似的 z 分数：(当前 - 基线均值) / sqrt(基线均值)，均值小于 1 时按 1 计算，避免新词得分无穷大
+func score(count int, mean float64) float64 {
+	return (float64(count) - mean) / math.Sqrt(math.Max(mean, 1))
+}
+
+// Spiking 是否为显著突增：z 分数达到阈值且至少是基线均值的 minRatio 倍
+func Spiking(t models.Trend, minScore float64) bool {
+	return t.Count >= MinCount && t.Score >= minScore && float64(t.Count) >= minRatio*t.Baseline
+}
+
+// titleTerms 标题中的词（去重，忽略纯数字）
+func titleTerms(title string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, token := range textutil.Tokenize(title) {
+		if seen[token] || strings.IndexFunc(token, func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+			continue
+		}
+		seen[token] = true
+		terms = append(terms, token)
+	}
+	return terms
+}
+
+// Save 用本次分析中显著突增的词（最多 maxTrends 条）替换已保存的趋势
+func Save(list []models.Trend) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM trends"); err != nil {
+		return err
+	}
+	saved := 0
+	for _, t := range list {
+		if saved >= maxTrends {
+			break
+		}
+		if !Spiking(t, MinScore) {
+			continue
+		}
+		_, err := tx.Exec(`
+			INSERT INTO trends (kind, term, count, baseline, score, news_ids, computed_at) VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, t.Kind, t.Term, t.Count, t.Baseline, t.Score, strings.Join(t.NewsIDs, ","), t.ComputedAt)
+		if err != nil {
+			return err
+		}
+		saved++
+	}
+	// 没有突增时也记录分析时间
+	if _, err := tx.Exec("INSERT OR REPLACE INTO settings (key, value) VALUES ('trends_computed_at', ?)", time.Now().Format(time.RFC3339)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// List 已保存的趋势（按显著性从高到低），kind 为空时返回全部类型
+func List(kind string, limit int) ([]models.Trend, error) {
+	query := "SELECT kind, term, count, baseline, score, COALESCE(news_ids, ''), computed_at FROM trends"
+	var args []interface{}
+	if kind != "" {
+		query += " WHERE kind = ?"
+		args = append(args, kind)
+	}
+	query += " ORDER BY score DESC, count DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.Trend{}
+	for rows.Next() {
+		var t models.Trend
+		var ids string
+		if err := rows.Scan(&t.Kind, &t.Term, &t.Count, &t.Baseline, &t.Score, &ids, &t.ComputedAt); err != nil {
+			return nil, err
+		}
+		t.NewsIDs = textutil.SplitList(ids)
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+// LastComputed 最近一次分析的时间（尚未分析时为零值）
+func LastComputed() time.Time {
+	var value string
+	database.DB.QueryRow("SELECT value FROM settings WHERE key = 'trends_computed_at'").Scan(&value)
+	t, _ := time.Parse(time.RFC3339, value)
+	return t
+}
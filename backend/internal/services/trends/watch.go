@@ -0,0 +1,109 @@
+package trends
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var ErrWatchNotFound = errors.New("watch not found")
+
+// ListWatches 关注词列表
+func ListWatches() ([]models.TrendWatch, error) {
+	rows, err := database.DB.Query(`
+		SELECT id, term, COALESCE(kind, ''), channel_id, min_score, enabled, last_fired_at, created_at
+		FROM trend_watches ORDER BY created_at DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.TrendWatch{}
+	for rows.Next() {
+		var w models.TrendWatch
+		var lastFiredAt sql.NullTime
+		if err := rows.Scan(&w.ID, &w.Term, &w.Kind, &w.ChannelID, &w.MinScore, &w.Enabled, &lastFiredAt, &w.CreatedAt); err != nil {
+			return nil, err
+		}
+		w.LastFiredAt = lastFiredAt.Time
+		list = append(list, w)
+	}
+	return list, rows.Err()
+}
+
+// CreateWatch 新增关注词
+func CreateWatch(w *models.TrendWatch) error {
+	w.ID = uuid.New().String()
+	w.CreatedAt = time.Now()
+	_, err := database.DB.Exec(`
+		INSERT INTO trend_watches (id, term, kind, channel_id, min_score, enabled, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, w.ID, w.Term, w.Kind, w.ChannelID, w.MinScore, w.Enabled, w.CreatedAt)
+	return err
+}
+
+// UpdateWatch 修改关注词
+func UpdateWatch(w models.TrendWatch) error {
+	res, err := database.DB.Exec(`
+		UPDATE trend_watches SET term = ?, kind = ?, channel_id = ?, min_score = ?, enabled = ? WHERE id = ?
+	`, w.Term, w.Kind, w.ChannelID, w.MinScore, w.Enabled, w.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrWatchNotFound
+	}
+	return nil
+}
+
+// DeleteWatch 删除关注词
+func DeleteWatch(id string) error {
+	_, err := database.DB.Exec("DELETE FROM trend_watches WHERE id = ?", id)
+	return err
+}
+
+// WatchHit 触发的关注词和对应的趋势
+type WatchHit struct {
+	Watch models.TrendWatch
+	Trend models.Trend
+}
+
+// CheckWatches 找出本次分析中出现突增的关注词（不区分大小写，类型为空时匹配任意类型），
+// 同一关注词在一个窗口内只触发一次；推送成功后需调用 MarkFired 记录触发时间
+func CheckWatches(list []models.Trend) ([]WatchHit, error) {
+	watches, err := ListWatches()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var hits []WatchHit
+	for _, w := range watches {
+		if !w.Enabled || now.Sub(w.LastFiredAt) < WindowHours*time.Hour {
+			continue
+		}
+		minScore := w.MinScore
+		if minScore <= 0 {
+			minScore = MinScore
+		}
+		for _, t := range list {
+			if !strings.EqualFold(t.Term, w.Term) || (w.Kind != "" && w.Kind != t.Kind) || !Spiking(t, minScore) {
+				continue
+			}
+			hits = append(hits, WatchHit{Watch: w, Trend: t})
+			break
+		}
+	}
+	return hits, nil
+}
+
+// MarkFired 记录关注词的触发时间，之后一个窗口内不再触发
+func MarkFired(id string) error {
+	_, err := database.DB.Exec("UPDATE trend_watches SET last_fired_at = ? WHERE id = ?", time.Now(), id)
+	return err
+}
@@ -0,0 +1,230 @@
+package usage
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+	"news-intel-app/internal/services/alerts"
+)
+
+// ErrBudgetExceeded 预算已用尽
+var ErrBudgetExceeded = errors.New("AI budget exceeded")
+
+// 预算单位
+const (
+	UnitTokens = "tokens"
+	UnitCost   = "cost"
+)
+
+// amount 一段时间内的 tokens 和费用
+type amount struct {
+	tokens float64
+	cost   float64
+}
+
+func (a amount) in(unit string) float64 {
+	if unit == UnitCost {
+		return a.cost
+	}
+	return a.tokens
+}
+
+// spend 一个模型本日和本月的用量
+type spend struct {
+	daily, monthly amount
+}
+
+// 预算设置和本月各模型用量的缓存，避免每次 AI 调用前都查询设置和汇总 ai_usage：
+// 用量在进程内首次使用或跨日时从数据库汇总一次，之后由 Record 累加
+var (
+	cacheMu      sync.Mutex
+	budgetCache  *models.Budget
+	spentDay     string // 用量缓存对应的日期（本地时间）
+	spentByModel map[string]*spend
+)
+
+// GetBudget 读取预算设置
+func GetBudget() models.Budget {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if budgetCache == nil {
+		b := loadBudget()
+		budgetCache = &b
+	}
+	return *budgetCache
+}
+
+func loadBudget() models.Budget {
+	b := models.Budget{Unit: UnitTokens}
+	var unit, daily, monthly string
+	database.DB.QueryRow("SELECT value FROM settings WHERE key = 'ai_budget_unit'").Scan(&unit)
+	database.DB.QueryRow("SELECT value FROM settings WHERE key = 'ai_budget_daily'").Scan(&daily)
+	database.DB.QueryRow("SELECT value FROM settings WHERE key = 'ai_budget_monthly'").Scan(&monthly)
+	database.DB.QueryRow("SELECT value FROM settings WHERE key = 'ai_budget_fallback_model'").Scan(&b.FallbackModel)
+	if unit == UnitCost {
+		b.Unit = UnitCost
+	}
+	b.Daily, _ = strconv.ParseFloat(daily, 64)
+	b.Monthly, _ = strconv.ParseFloat(monthly, 64)
+	return b
+}
+
+// SaveBudget 保存预算设置
+func SaveBudget(b models.Budget) error {
+	values := map[string]string{
+		"ai_budget_unit":           b.Unit,
+		"ai_budget_daily":          strconv.FormatFloat(b.Daily, 'f', -1, 64),
+		"ai_budget_monthly":        strconv.FormatFloat(b.Monthly, 'f', -1, 64),
+		"ai_budget_fallback_model": b.FallbackModel,
+	}
+	for key, value := range values {
+		if _, err := database.DB.Exec("INSERT OR REPLACE INTO settings (key, value) VALUES (?, ?)", key, value); err != nil {
+			return err
+		}
+	}
+	cacheMu.Lock()
+	budgetCache = nil
+	cacheMu.Unlock()
+	return nil
+}
+
+// spentSnapshot 本日和本月各模型的用量（缓存过期时重新汇总）
+func spentSnapshot(now time.Time) (map[string]spend, error) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	day := now.Format("2006-01-02")
+	if spentByModel == nil || spentDay != day {
+		byModel, err := loadSpent(now)
+		if err != nil {
+			return nil, err
+		}
+		spentByModel, spentDay = byModel, day
+	}
+	snapshot := make(map[string]spend, len(spentByModel))
+	for model, sp := range spentByModel {
+		snapshot[model] = *sp
+	}
+	return snapshot, nil
+}
+
+func loadSpent(now time.Time) (map[string]*spend, error) {
+	rows, err := database.DB.Query(`
+		SELECT model,
+			COALESCE(SUM(CASE WHEN DATE(created_at, 'localtime') = ? THEN total_tokens ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN DATE(created_at, 'localtime') = ? THEN cost ELSE 0 END), 0),
+			COALESCE(SUM(total_tokens), 0), COALESCE(SUM(cost), 0)
+		FROM ai_usage WHERE DATE(created_at, 'localtime') >= ? GROUP BY model
+	`, now.Format("2006-01-02"), now.Format("2006-01-02"), now.Format("2006-01")+"-01")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byModel := make(map[string]*spend)
+	for rows.Next() {
+		var model string
+		sp := &spend{}
+		if err := rows.Scan(&model, &sp.daily.tokens, &sp.daily.cost, &sp.monthly.tokens, &sp.monthly.cost); err != nil {
+			return nil, err
+		}
+		byModel[model] = sp
+	}
+	return byModel, rows.Err()
+}
+
+// addSpent 把一次调用的用量累加到缓存（缓存未加载或已过期时由下次汇总包含）
+func addSpent(model string, tokens int, cost float64, at time.Time) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if spentByModel == nil || spentDay != at.Format("2006-01-02") {
+		return
+	}
+	sp := spentByModel[model]
+	if sp == nil {
+		sp = &spend{}
+		spentByModel[model] = sp
+	}
+	a := amount{tokens: float64(tokens), cost: cost}
+	sp.daily.tokens += a.tokens
+	sp.daily.cost += a.cost
+	sp.monthly.tokens += a.tokens
+	sp.monthly.cost += a.cost
+}
+
+// exceeded 用量是否达到任一上限
+func exceeded(b models.Budget, daily, monthly float64) bool {
+	return (b.Daily > 0 && daily >= b.Daily) || (b.Monthly > 0 && monthly >= b.Monthly)
+}
+
+// CheckBudget 当前预算使用情况
+func CheckBudget() (*models.BudgetStatus, error) {
+	status, _, err := checkBudget(time.Now())
+	return status, err
+}
+
+// checkBudget 预算使用情况和各模型用量
+func checkBudget(now time.Time) (*models.BudgetStatus, map[string]spend, error) {
+	status := &models.BudgetStatus{Budget: GetBudget()}
+	byModel, err := spentSnapshot(now)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, sp := range byModel {
+		status.DailySpent += sp.daily.in(status.Unit)
+		status.MonthlySpent += sp.monthly.in(status.Unit)
+	}
+	status.Exceeded = exceeded(status.Budget, status.DailySpent, status.MonthlySpent)
+	return status, byModel, nil
+}
+
+// Allow 检查是否允许调用指定模型：预算用尽后只允许使用降级模型，并发出告警；
+// 降级模型自身的用量也受同样的日 / 月上限约束，达到后不再调用 AI
+func Allow(model string) error {
+	if b := GetBudget(); b.Daily <= 0 && b.Monthly <= 0 {
+		return nil
+	}
+	now := time.Now()
+	status, byModel, err := checkBudget(now)
+	if err != nil || !status.Exceeded {
+		return nil
+	}
+
+	if status.Daily > 0 && status.DailySpent >= status.Daily {
+		alerts.Raise("budget:daily:"+now.Format("2006-01-02"), alerts.LevelWarning,
+			fmt.Sprintf("今日 AI 预算已用尽（%s / %s），%s", formatAmount(status.Unit, status.DailySpent), formatAmount(status.Unit, status.Daily), degradation(status.FallbackModel)))
+	} else {
+		alerts.Raise("budget:monthly:"+now.Format("2006-01"), alerts.LevelWarning,
+			fmt.Sprintf("本月 AI 预算已用尽（%s / %s），%s", formatAmount(status.Unit, status.MonthlySpent), formatAmount(status.Unit, status.Monthly), degradation(status.FallbackModel)))
+	}
+
+	if status.FallbackModel == "" || model != status.FallbackModel {
+		return ErrBudgetExceeded
+	}
+	fallback := byModel[model]
+	if !exceeded(status.Budget, fallback.daily.in(status.Unit), fallback.monthly.in(status.Unit)) {
+		return nil
+	}
+	alerts.Raise("budget:fallback:"+now.Format("2006-01-02"), alerts.LevelWarning,
+		fmt.Sprintf("降级模型 %s 的用量也已达到预算上限，%s", model, degradation("")))
+	return ErrBudgetExceeded
+}
+
+func formatAmount(unit string, v float64) string {
+	if unit == UnitCost {
+		return fmt.Sprintf("$%.4f", v)
+	}
+	return fmt.Sprintf("%.0f tokens", v)
+}
+
+func degradation(fallbackModel string) string {
+	if fallbackModel != "" {
+		return "已切换到降级模型 " + fallbackModel
+	}
+	return "新闻将不经翻译直接进入阅读窗口（未翻译的新闻不会推送，预算恢复后可通过处理未翻译新闻补译）"
+}
@@ -0,0 +1,70 @@
+package usage
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+)
+
+func setupDB(t *testing.T) {
+	t.Helper()
+	if err := database.Init(filepath.Join(t.TempDir(), "news.db")); err != nil {
+		t.Fatal(err)
+	}
+	cacheMu.Lock()
+	budgetCache, spentByModel, spentDay = nil, nil, ""
+	cacheMu.Unlock()
+	t.Cleanup(func() { database.DB.Close() })
+}
+
+func TestAllowWithFallbackCap(t *testing.T) {
+	setupDB(t)
+	if err := SaveBudget(models.Budget{Unit: UnitTokens, Daily: 100, FallbackModel: "cheap"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Allow("main"); err != nil {
+		t.Fatalf("Allow before any usage = %v", err)
+	}
+
+	// 先汇总一次，之后的用量由 Record 累加到缓存
+	Record(Entry{Operation: "test", Model: "main", PromptTokens: 80, CompletionTokens: 40})
+	if err := Allow("main"); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("Allow(main) over budget = %v, want ErrBudgetExceeded", err)
+	}
+	if err := Allow("cheap"); err != nil {
+		t.Fatalf("Allow(cheap) with fallback spend 0 = %v", err)
+	}
+
+	Record(Entry{Operation: "test", Model: "cheap", PromptTokens: 100})
+	if err := Allow("cheap"); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("Allow(cheap) over fallback cap = %v, want ErrBudgetExceeded", err)
+	}
+
+	status, err := CheckBudget()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.DailySpent != 220 || status.MonthlySpent != 220 || !status.Exceeded {
+		t.Errorf("CheckBudget = %+v, want 220 spent and exceeded", status)
+	}
+
+	// 缓存累加的结果与重新汇总一致
+	cacheMu.Lock()
+	spentByModel = nil
+	cacheMu.Unlock()
+	if reloaded, _ := CheckBudget(); reloaded.DailySpent != status.DailySpent {
+		t.Errorf("reloaded DailySpent = %v, want %v", reloaded.DailySpent, status.DailySpent)
+	}
+}
+
+func TestAllowWithoutLimits(t *testing.T) {
+	setupDB(t)
+	Record(Entry{Operation: "test", Model: "main", PromptTokens: 1000000})
+	if err := Allow("main"); err != nil {
+		t.Fatalf("Allow without limits = %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package usage
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"news-intel-app/internal/database"
+	"news-intel-app/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// defaultPricing 默认模型单价（美元 / 百万 tokens）和上下文限制，可在设置中修改
+var defaultPricing = []models.ModelPricing{
+	{Model: "gpt-4o-mini", PromptPrice: 0.15, CompletionPrice: 0.6, ContextTokens: 128000, MaxOutputTokens: 16384},
+	{Model: "gpt-4o", PromptPrice: 2.5, CompletionPrice: 10, ContextTokens: 128000, MaxOutputTokens: 16384},
+	{Model: "gpt-4.1-mini", PromptPrice: 0.4, CompletionPrice: 1.6, ContextTokens: 1047576, MaxOutputTokens: 32768},
+	{Model: "gpt-4.1", PromptPrice: 2, CompletionPrice: 8, ContextTokens: 1047576, MaxOutputTokens: 32768},
+	{Model: "text-embedding-3-small", PromptPrice: 0.02, CompletionPrice: 0},
+	{Model: "text-embedding-3-large", PromptPrice: 0.13, CompletionPrice: 0},
+}
+
+// 未配置上下文限制的模型使用的保守默认值
+const (
+	DefaultContextTokens   = 8192
+	DefaultMaxOutputTokens = 2048
+)
+
+// Entry 一次 AI 调用的记录
+type Entry struct {
+	Operation        string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	Latency          time.Duration
+	Err              error
+	NewsIDs          []string
+}
+
+// InitDefaultPricing 写入默认单价（已存在的不覆盖，只补充未设置的上下文限制）
+func InitDefaultPricing() error {
+	for _, p := range defaultPricing {
+		_, err := database.DB.Exec(`
+			INSERT INTO ai_pricing (model, prompt_price, completion_price, context_tokens, max_output_tokens) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT(model) DO UPDATE SET
+				context_tokens = CASE WHEN ai_pricing.context_tokens = 0 THEN excluded.context_tokens ELSE ai_pricing.context_tokens END,
+				max_output_tokens = CASE WHEN ai_pricing.max_output_tokens = 0 THEN excluded.max_output_tokens ELSE ai_pricing.max_output_tokens END
+		`, p.Model, p.PromptPrice, p.CompletionPrice, p.ContextTokens, p.MaxOutputTokens)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Record 写入调用记录，并按当前单价计算费用
+func Record(e Entry) {
+	cost := Cost(e.Model, e.PromptTokens, e.CompletionTokens)
+	errMsg := ""
+	if e.Err != nil {
+		errMsg = e.Err.Error()
+	}
+	now := time.Now()
+	_, err := database.DB.Exec(`
+		INSERT INTO ai_usage (id, operation, model, prompt_tokens, completion_tokens, total_tokens, cost, latency_ms, success, error, news_ids, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, uuid.New().String(), e.Operation, e.Model, e.PromptTokens, e.CompletionTokens, e.PromptTokens+e.CompletionTokens,
+		cost, e.Latency.Milliseconds(), e.Err == nil, errMsg, strings.Join(e.NewsIDs, ","), now)
+	if err != nil {
+		log.Printf("Failed to record AI usage: %v", err)
+		return
+	}
+	addSpent(e.Model, e.PromptTokens+e.CompletionTokens, cost, now)
+}
+
+// Cost 按模型单价计算费用（美元），未配置单价的模型费用为 0
+func Cost(model string, promptTokens, completionTokens int) float64 {
+	var p models.ModelPricing
+	err := database.DB.QueryRow("SELECT prompt_price, completion_price FROM ai_pricing WHERE model = ?", model).
+		Scan(&p.PromptPrice, &p.CompletionPrice)
+	if err != nil {
+		return 0
+	}
+	return (float64(promptTokens)*p.PromptPrice + float64(completionTokens)*p.CompletionPrice) / 1e6
+}
+
+// Summary 最近 days 天的用量汇总：总计、按天、按操作、按模型
+func Summary(days int) (*models.UsageSummary, error) {
+	since := time.Now().AddDate(0, 0, -days+1).Format("2006-01-02")
+	summary := &models.UsageSummary{Days: days}
+
+	total, err := aggregate("1", since)
+	if err != nil {
+		return nil, err
+	}
+	if len(total) > 0 {
+		summary.Total = total[0]
+	}
+	summary.Total.Key = ""
+
+	if summary.Daily, err = aggregate("DATE(created_at, 'localtime')", since); err != nil {
+		return nil, err
+	}
+	if summary.ByOperation, err = aggregate("operation", since); err != nil {
+		return nil, err
+	}
+	if summary.ByModel, err = aggregate("model", since); err != nil {
+		return nil, err
+	}
+
+	today := time.Now().Format("2006-01-02")
+	for _, d := range summary.Daily {
+		if d.Key == today {
+			summary.Today = d
+		}
+	}
+	summary.Today.Key = today
+	return summary, nil
+}
+
+func aggregate(groupBy, since string) ([]models.UsageStat, error) {
+	rows, err := database.DB.Query(fmt.Sprintf(`
+		SELECT %s, COUNT(*), COALESCE(SUM(CASE WHEN success = 0 THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(prompt_tokens), 0), COALESCE(SUM(completion_tokens), 0), COALESCE(SUM(cost), 0), COALESCE(AVG(latency_ms), 0)
+		FROM ai_usage WHERE DATE(created_at, 'localtime') >= ?
+		GROUP BY %s ORDER BY %s
+	`, groupBy, groupBy, groupBy), since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []models.UsageStat{}
+	for rows.Next() {
+		var s models.UsageStat
+		var key interface{}
+		if err := rows.Scan(&key, &s.Calls, &s.Errors, &s.PromptTokens, &s.CompletionTokens, &s.Cost, &s.AvgLatencyMs); err != nil {
+			return nil, err
+		}
+		s.Key = fmt.Sprint(key)
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+// Limits 模型的上下文窗口和单次输出上限，未配置时使用默认值
+func Limits(model string) (contextTokens, maxOutputTokens int) {
+	database.DB.QueryRow("SELECT context_tokens, max_output_tokens FROM ai_pricing WHERE model = ?", model).
+		Scan(&contextTokens, &maxOutputTokens)
+	if contextTokens <= 0 {
+		contextTokens = DefaultContextTokens
+	}
+	if maxOutputTokens <= 0 {
+		maxOutputTokens = DefaultMaxOutputTokens
+	}
+	if maxOutputTokens > contextTokens/2 {
+		maxOutputTokens = contextTokens / 2
+	}
+	return contextTokens, maxOutputTokens
+}
+
+// ListPricing 全部模型单价
+func ListPricing() ([]models.ModelPricing, error) {
+	rows, err := database.DB.Query("SELECT model, prompt_price, completion_price, context_tokens, max_output_tokens FROM ai_pricing ORDER BY model")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.ModelPricing{}
+	for rows.Next() {
+		var p models.ModelPricing
+		if err := rows.Scan(&p.Model, &p.PromptPrice, &p.CompletionPrice, &p.ContextTokens, &p.MaxOutputTokens); err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
+// SavePricing 新增或修改模型单价
+func SavePricing(p models.ModelPricing) error {
+	_, err := database.DB.Exec("INSERT OR REPLACE INTO ai_pricing (model, prompt_price, completion_price, context_tokens, max_output_tokens) VALUES (?, ?, ?, ?, ?)",
+		p.Model, p.PromptPrice, p.CompletionPrice, p.ContextTokens, p.MaxOutputTokens)
+	return err
+}
+
+// DeletePricing 删除模型单价
+func DeletePricing(model string) error {
+	_, err := database.DB.Exec("DELETE FROM ai_pricing WHERE model = ?", model)
+	return err
+}
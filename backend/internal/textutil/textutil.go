@@ -0,0 +1,195 @@
+package textutil
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// stopWords 英文停用词
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "has": true, "have": true, "how": true, "in": true, "is": true, "it": true,
+	"its": true, "of": true, "on": true, "or": true, "that": true, "the": true, "this": true, "to": true,
+	"was": true, "were": true, "what": true, "when": true, "who": true, "why": true, "will": true,
+	"with": true, "after": true, "about": true, "into": true, "new": true, "says": true, "said": true,
+	"more": true, "than": true, "over": true, "not": true, "but": true, "can": true, "you": true,
+	"your": true, "we": true, "our": true, "they": true, "their": true, "he": true, "she": true,
+	"his": true, "her": true, "been": true, "up": true, "out": true, "s": true, "t": true,
+}
+
+// StripHTML 去除 HTML 标签并反转义实体
+func StripHTML(s string) string {
+	s = tagPattern.ReplaceAllString(s, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+// Tokenize 分词：英文等按单词切分（小写、去停用词），中文按相邻二字切分
+func Tokenize(s string) []string {
+	var tokens []string
+	var word []rune
+	var han []rune
+
+	flushWord := func() {
+		if len(word) > 1 {
+			w := string(word)
+			if !stopWords[w] {
+				tokens = append(tokens, w)
+			}
+		}
+		word = word[:0]
+	}
+	flushHan := func() {
+		if len(han) == 1 {
+			tokens = append(tokens, string(han))
+		}
+		for i := 0; i+1 < len(han); i++ {
+			tokens = append(tokens, string(han[i:i+2]))
+		}
+		han = han[:0]
+	}
+
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flushWord()
+			han = append(han, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushHan()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushHan()
+		}
+	}
+	flushWord()
+	flushHan()
+	return tokens
+}
+
+// SplitList 解析逗号分隔的列表：去掉空白和空项，去重并保持原顺序
+func SplitList(s string) []string {
+	var items []string
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" && !seen[item] {
+			seen[item] = true
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Truncate 按字符（rune）截断，超出时追加省略号
+func Truncate(s string, maxRunes int) string {
+	runes := []rune(s)
+	if len(runes) <= maxRunes {
+		return s
+	}
+	return string(runes[:maxRunes]) + "..."
+}
+
+// TruncateSentence 按字符截断到 maxRunes 以内，尽量在句末标点处截断，超出时追加省略号
+func TruncateSentence(s string, maxRunes int) string {
+	runes := []rune(s)
+	if len(runes) <= maxRunes {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:sentenceCut(runes, maxRunes)])) + "..."
+}
+
+// sentenceCut 在前 maxRunes 个字符的后半段查找最后一个句末标点，返回截断位置；找不到时为 maxRunes
+func sentenceCut(runes []rune, maxRunes int) int {
+	for i := maxRunes - 1; i > maxRunes/2; i-- {
+		if strings.ContainsRune("。！？.!?；;", runes[i]) {
+			return i + 1
+		}
+	}
+	return maxRunes
+}
+
+// EstimateTokens 粗略估算文本的 token 数：中日韩等字符约每字 1 个 token，其余约每 4 个字符 1 个 token
+func EstimateTokens(s string) int {
+	wide, other := 0, 0
+	for _, r := range s {
+		if r >= 0x2E80 {
+			wide++
+		} else {
+			other++
+		}
+	}
+	return wide + (other+3)/4
+}
+
+var (
+	headingPattern  = regexp.MustCompile(`(?i)<h[1-6][^>]*>`)
+	listItemPattern = regexp.MustCompile(`(?i)<li[^>]*>`)
+	blockEndPattern = regexp.MustCompile(`(?i)</(p|div|h[1-6]|li|ul|ol|blockquote|pre|tr|table|section|article)>|<br\s*/?>|<hr\s*/?>`)
+	blankLines      = regexp.MustCompile(`\n\s*\n`)
+)
+
+// Paragraphs 将 HTML 或纯文本拆成段落，保留基本格式：标题转为 "## "，列表项转为 "- "
+func Paragraphs(s string) []string {
+	s = headingPattern.ReplaceAllString(s, "\n\n## ")
+	s = listItemPattern.ReplaceAllString(s, "\n\n- ")
+	s = blockEndPattern.ReplaceAllString(s, "\n\n")
+	s = tagPattern.ReplaceAllString(s, "")
+	s = html.UnescapeString(strings.ReplaceAll(s, "\r\n", "\n"))
+
+	var paragraphs []string
+	for _, p := range blankLines.Split(s, -1) {
+		lines := strings.Split(p, "\n")
+		for i, line := range lines {
+			lines[i] = strings.Join(strings.Fields(line), " ")
+		}
+		if p = strings.TrimSpace(strings.Join(lines, "\n")); p != "" && p != "-" && p != "##" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return paragraphs
+}
+
+// ChunkParagraphs 按段落边界将段落合并为不超过 maxRunes 的片段；
+// 单个段落超长时在句末标点处切开
+func ChunkParagraphs(paragraphs []string, maxRunes int) []string {
+	var chunks []string
+	var current []string
+	size := 0
+	flush := func() {
+		if len(current) > 0 {
+			chunks = append(chunks, strings.Join(current, "\n\n"))
+			current, size = nil, 0
+		}
+	}
+	for _, p := range paragraphs {
+		for _, piece := range splitLong(p, maxRunes) {
+			n := len([]rune(piece))
+			if size > 0 && size+n > maxRunes {
+				flush()
+			}
+			current = append(current, piece)
+			size += n
+		}
+	}
+	flush()
+	return chunks
+}
+
+// splitLong 在句末标点处切分超长文本
+func splitLong(s string, maxRunes int) []string {
+	runes := []rune(s)
+	var pieces []string
+	for len(runes) > maxRunes {
+		cut := sentenceCut(runes, maxRunes)
+		pieces = append(pieces, strings.TrimSpace(string(runes[:cut])))
+		runes = runes[cut:]
+	}
+	if rest := strings.TrimSpace(string(runes)); rest != "" {
+		pieces = append(pieces, rest)
+	}
+	return pieces
+}
@@ -0,0 +1,24 @@
+package textutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{" , ,", nil},
+		{"tech", []string{"tech"}},
+		{"tech, ai ,tech,,international", []string{"tech", "ai", "international"}},
+		{"zh-CN,ug", []string{"zh-CN", "ug"}},
+	}
+	for _, tt := range tests {
+		if got := SplitList(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitList(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
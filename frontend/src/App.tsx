@@ -11,6 +11,12 @@ import ChannelsPage from './pages/ChannelsPage';
 import TasksPage from './pages/TasksPage';
 import TemplatesPage from './pages/TemplatesPage';
 import AIConfigPage from './pages/AIConfigPage';
+import GlossaryPage from './pages/GlossaryPage';
+import JobsPage from './pages/JobsPage';
+import SummaryStylesPage from './pages/SummaryStylesPage';
+import PreferencesPage from './pages/PreferencesPage';
+import TrendsPage from './pages/TrendsPage';
+import EnrichmentFieldsPage from './pages/EnrichmentFieldsPage';
 import './App.css';
 
 const App: React.FC = () => {
@@ -34,6 +40,12 @@ const App: React.FC = () => {
               <Route path="tasks" element={<TasksPage />} />
               <Route path="templates" element={<TemplatesPage />} />
               <Route path="ai" element={<AIConfigPage />} />
+              <Route path="glossary" element={<GlossaryPage />} />
+              <Route path="summary-styles" element={<SummaryStylesPage />} />
+              <Route path="preferences" element={<PreferencesPage />} />
+              <Route path="enrichment-fields" element={<EnrichmentFieldsPage />} />
+              <Route path="trends" element={<TrendsPage />} />
+              <Route path="jobs" element={<JobsPage />} />
             </Route>
           </Routes>
         </BrowserRouter>
@@ -6,15 +6,27 @@ const api = axios.create({
 });
 
 // 新闻
-export const getNews = (params?: { category?: string; source?: string; limit?: number; offset?: number }) =>
+export const getNews = (params?: { category?: string; source?: string; tags?: string; sentiment?: string; sort?: string; filtered?: string; limit?: number; offset?: number; [field: `field.${string}`]: string | undefined }) =>
   api.get('/news', { params });
 export const getNewsDetail = (id: string) => api.get(`/news/${id}`);
+export const searchNews = (params: { q?: string; semantic?: string; sentiment?: string; limit?: number; [field: `field.${string}`]: string | undefined }) => api.get('/news/search', { params });
+export const getRelatedNews = (id: string, limit?: number) => api.get(`/news/${id}/related`, { params: { limit } });
 export const deleteNews = (id: string) => api.delete(`/news/${id}`);
+// 全文翻译按段落分片调用 AI，耗时较长
+export const translateFull = (id: string, lang?: string) =>
+  api.post(`/news/${id}/translate-full`, { lang }, { timeout: 300000 });
 export const triggerCollect = () => api.post('/news/collect');
 export const triggerProcess = () => api.post('/news/process');
+export const setNewsFeedback = (id: string, label: 'up' | 'down' | 'not_interested') => api.post(`/news/${id}/feedback`, { label });
+export const deleteNewsFeedback = (id: string) => api.delete(`/news/${id}/feedback`);
+
+// 偏好模型
+export const getPreferences = () => api.get('/preferences');
+export const savePreferences = (data: { auto_filter: boolean; threshold: number; min_feedback: number }) => api.post('/preferences', data);
+export const trainPreferences = () => api.post('/preferences/train');
 
 // 阅读窗口
-export const getReadingNews = (params?: { category?: string; pushed?: string; limit?: number; offset?: number }) =>
+export const getReadingNews = (params?: { category?: string; pushed?: string; tags?: string; sentiment?: string; sort?: string; limit?: number; offset?: number; [field: `field.${string}`]: string | undefined }) =>
   api.get('/reading', { params });
 export const addToReading = (id: string) => api.post(`/reading/${id}/add`);
 export const removeFromReading = (id: string) => api.post(`/reading/${id}/remove`);
@@ -45,23 +57,162 @@ export const getTemplates = () => api.get('/templates');
 export const createTemplate = (data: any) => api.post('/templates', data);
 export const updateTemplate = (id: string, data: any) => api.put(`/templates/${id}`, data);
 export const deleteTemplate = (id: string) => api.delete(`/templates/${id}`);
-export const previewTemplate = (content: string) => api.post('/templates/preview', { content });
+export const previewTemplate = (content: string, briefing = false) => api.post('/templates/preview', { content, briefing });
 export const aiGenerateTemplate = (description: string, currentTemplate?: string) => 
   api.post('/templates/ai-generate', { description, current_template: currentTemplate }, { timeout: 120000 });
+// aiGenerateTemplateStream 流式生成模板（delta 为原始输出，done 的 template 为清理后的模板）
+export const aiGenerateTemplateStream = (
+  description: string,
+  currentTemplate: string | undefined,
+  onEvent: (event: string, data: any) => void,
+  signal?: AbortSignal,
+) => streamSSE('/templates/ai-generate', { description, current_template: currentTemplate, stream: true }, onEvent, signal);
+
+// 摘要样式
+export const getSummaryStyles = () => api.get('/summary-styles');
+export const createSummaryStyle = (data: any) => api.post('/summary-styles', data);
+export const updateSummaryStyle = (id: string, data: any) => api.put(`/summary-styles/${id}`, data);
+export const deleteSummaryStyle = (id: string) => api.delete(`/summary-styles/${id}`);
+export const getNewsSummaries = (id: string) => api.get(`/news/${id}/summaries`);
+export const generateNewsSummary = (id: string, style: string, lang?: string) =>
+  api.post(`/news/${id}/summaries`, { style, lang }, { timeout: 120000 });
+
+// 标签
+export const getTags = (status?: string) => api.get('/tags', { params: { status } });
+export const createTag = (data: any) => api.post('/tags', data);
+export const updateTag = (id: string, data: any) => api.put(`/tags/${id}`, data);
+export const deleteTag = (id: string) => api.delete(`/tags/${id}`);
+export const approveTag = (id: string) => api.post(`/tags/${id}/approve`);
+
+// 实体
+export const getEntities = (params?: { type?: string; q?: string; days?: number; limit?: number }) =>
+  api.get('/entities', { params });
+export const getEntityNews = (id: string, params?: { limit?: number; offset?: number }) =>
+  api.get(`/entities/${id}/news`, { params });
+export const addEntityAlias = (id: string, alias: string) => api.post(`/entities/${id}/aliases`, { alias });
+export const mergeEntity = (id: string, sourceId: string) => api.post(`/entities/${id}/merge`, { source_id: sourceId });
+
+// 故事
+export const getStories = (params?: { hours?: number; limit?: number }) => api.get('/stories', { params });
+export const getStory = (id: string) => api.get(`/stories/${id}`);
+export const rebuildStories = () => api.post('/stories/rebuild');
 
 // AI配置
 export const getAIConfig = () => api.get('/ai/config');
 export const saveAIConfig = (data: any) => api.post('/ai/config', data);
 export const translateText = (text: string, targetLang?: string) => api.post('/ai/translate', { text, target_lang: targetLang });
 export const summarizeText = (text: string) => api.post('/ai/summarize', { text });
+export const translateTextStream = (text: string, onEvent: (event: string, data: any) => void, signal?: AbortSignal) =>
+  streamSSE('/ai/translate', { text, stream: true }, onEvent, signal);
+export const summarizeTextStream = (text: string, onEvent: (event: string, data: any) => void, signal?: AbortSignal) =>
+  streamSSE('/ai/summarize', { text, stream: true }, onEvent, signal);
+export const getAIUsage = (days?: number) => api.get('/ai/usage', { params: { days } });
+export const getAIPricing = () => api.get('/ai/pricing');
+export const saveAIPricing = (data: { model: string; prompt_price: number; completion_price: number; context_tokens?: number; max_output_tokens?: number }) =>
+  api.post('/ai/pricing', data);
+export const deleteAIPricing = (model: string) => api.delete(`/ai/pricing/${encodeURIComponent(model)}`);
+export const getAIBudget = () => api.get('/ai/budget');
+export const saveAIBudget = (data: { unit: string; daily: number; monthly: number; fallback_model?: string }) => api.post('/ai/budget', data);
+export const getTranslationCache = () => api.get('/ai/cache');
+export const saveTranslationCacheSettings = (data: { enabled: boolean; ttl_days: number; max_entries: number }) => api.post('/ai/cache', data);
+export const purgeTranslationCache = (params?: { kind?: string; expired?: boolean }) => api.delete('/ai/cache', { params });
+
+// 热点趋势
+export const getTrends = (params?: { kind?: string; limit?: number }) => api.get('/trends', { params });
+export const refreshTrends = () => api.post('/trends/refresh');
+export const getTrendWatches = () => api.get('/trend-watches');
+export const createTrendWatch = (data: any) => api.post('/trend-watches', data);
+export const updateTrendWatch = (id: string, data: any) => api.put(`/trend-watches/${id}`, data);
+export const deleteTrendWatch = (id: string) => api.delete(`/trend-watches/${id}`);
+
+// 自定义提取字段
+export const getEnrichmentFields = () => api.get('/enrichment-fields');
+export const saveEnrichmentFields = (data: { name: string; type: string; instruction: string }[]) => api.post('/enrichment-fields', data);
+
+// 术语表
+export const getGlossary = (lang?: string) => api.get('/glossary', { params: { lang } });
+export const createGlossaryTerm = (data: any) => api.post('/glossary', data);
+export const updateGlossaryTerm = (id: string, data: any) => api.put(`/glossary/${id}`, data);
+export const deleteGlossaryTerm = (id: string) => api.delete(`/glossary/${id}`);
+export const getGlossaryViolations = (limit?: number) => api.get('/glossary/violations', { params: { limit } });
+export const clearGlossaryViolations = () => api.delete('/glossary/violations');
+
+// 后台任务
+export const getJobs = (status?: string) => api.get('/jobs', { params: { status } });
+export const retryJob = (id: string) => api.post(`/jobs/${id}/retry`);
+export const cancelJob = (id: string) => api.post(`/jobs/${id}/cancel`);
+
+// 告警
+export const getAlerts = (all?: boolean) => api.get('/alerts', { params: { all } });
+export const acknowledgeAlert = (id: string) => api.post(`/alerts/${id}/ack`);
 
 // 统计
 export const getStats = () => api.get('/stats');
 
 // 自动打包推送
 export const getAutoPushConfig = () => api.get('/auto-push/config');
-export const saveAutoPushConfig = (data: { enabled: boolean; threshold: number; channel_id: string; template_id: string }) => 
+export const saveAutoPushConfig = (data: { enabled: boolean; threshold: number; channel_id: string; template_id: string; briefing?: boolean }) => 
   api.post('/auto-push/config', data);
 export const getAutoPushStatus = () => api.get('/auto-push/status');
 
+// 问答（基于新闻库）
+export const ask = (question: string, conversationId?: string) =>
+  api.post('/ask', { question, conversation_id: conversationId, stream: false }, { timeout: 120000 });
+// askStream 流式问答，按 SSE 事件回调（sources / delta / done / error）
+export const askStream = (
+  question: string,
+  conversationId: string | undefined,
+  onEvent: (event: string, data: any) => void,
+  signal?: AbortSignal,
+) => streamSSE('/ask', { question, conversation_id: conversationId }, onEvent, signal);
+
+// streamSSE POST 请求并按 SSE 事件回调；signal 中止时服务端同时取消 AI 请求
+export const streamSSE = async (
+  path: string,
+  body: any,
+  onEvent: (event: string, data: any) => void,
+  signal?: AbortSignal,
+) => {
+  const res = await fetch(`/api${path}`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(body),
+    signal,
+  });
+  if (!res.ok || !res.body) {
+    const err = await res.json().catch(() => ({}));
+    throw new Error(err.error || res.statusText);
+  }
+  const reader = res.body.getReader();
+  const decoder = new TextDecoder();
+  let buffer = '';
+  for (;;) {
+    const { done, value } = await reader.read();
+    if (done) break;
+    buffer += decoder.decode(value, { stream: true });
+    let sep;
+    while ((sep = buffer.indexOf('\n\n')) >= 0) {
+      const block = buffer.slice(0, sep);
+      buffer = buffer.slice(sep + 2);
+      const event = block.match(/^event: (.*)$/m)?.[1] || 'message';
+      const data = block.match(/^data: (.*)$/m)?.[1];
+      if (data) onEvent(event, JSON.parse(data));
+    }
+  }
+};
+export const getConversations = () => api.get('/conversations');
+export const getConversation = (id: string) => api.get(`/conversations/${id}`);
+export const deleteConversation = (id: string) => api.delete(`/conversations/${id}`);
+
+// 提示词
+export const getPrompts = () => api.get('/prompts');
+export const getPrompt = (name: string) => api.get(`/prompts/${name}`);
+export const createPrompt = (data: { name: string; description?: string; content: string }) => api.post('/prompts', data);
+export const updatePrompt = (name: string, data: { description?: string; content: string; note?: string }) =>
+  api.put(`/prompts/${name}`, data);
+export const deletePrompt = (name: string) => api.delete(`/prompts/${name}`);
+export const rollbackPrompt = (name: string, version: number) => api.post(`/prompts/${name}/rollback`, { version });
+export const testPrompt = (name: string, data: { content?: string; news_id?: string }) =>
+  api.post(`/prompts/${name}/test`, data, { timeout: 120000 });
+
 export default api;
@@ -11,6 +11,12 @@ import {
   RobotOutlined,
   MenuOutlined,
   BookOutlined,
+  TranslationOutlined,
+  UnorderedListOutlined,
+  AlignLeftOutlined,
+  LikeOutlined,
+  RiseOutlined,
+  ProfileOutlined,
 } from '@ant-design/icons';
 
 const { Sider, Content, Header } = AntLayout;
@@ -34,11 +40,17 @@ const Layout: React.FC = () => {
     { key: '/dashboard', icon: <DashboardOutlined />, label: '仪表盘' },
     { key: '/reading', icon: <BookOutlined />, label: '阅读窗口' },
     { key: '/news', icon: <ReadOutlined />, label: '全部新闻' },
+    { key: '/trends', icon: <RiseOutlined />, label: '热点趋势' },
     { key: '/sources', icon: <GlobalOutlined />, label: '新闻源' },
     { key: '/channels', icon: <SendOutlined />, label: '推送渠道' },
     { key: '/tasks', icon: <ScheduleOutlined />, label: '推送任务' },
     { key: '/templates', icon: <FileTextOutlined />, label: '邮件模板' },
     { key: '/ai', icon: <RobotOutlined />, label: 'AI 设置' },
+    { key: '/glossary', icon: <TranslationOutlined />, label: '术语表' },
+    { key: '/summary-styles', icon: <AlignLeftOutlined />, label: '摘要样式' },
+    { key: '/preferences', icon: <LikeOutlined />, label: '偏好模型' },
+    { key: '/enrichment-fields', icon: <ProfileOutlined />, label: '自定义字段' },
+    { key: '/jobs', icon: <UnorderedListOutlined />, label: '后台任务' },
   ];
 
   const handleMenuClick = ({ key }: { key: string }) => {
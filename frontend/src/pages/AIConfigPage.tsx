@@ -1,10 +1,18 @@
 import React, { useEffect, useState } from 'react';
-import { Card, Form, Input, Select, Switch, Button, message, Divider, Space, AutoComplete } from 'antd';
-import { getAIConfig, saveAIConfig, translateText, summarizeText } from '../api';
+import { Card, Form, Input, InputNumber, Select, Switch, Button, message, Divider, Space, AutoComplete, Progress, Popconfirm, Table, Modal } from 'antd';
+import { getAIConfig, saveAIConfig, getAIBudget, saveAIBudget, getTranslationCache, saveTranslationCacheSettings, purgeTranslationCache, getAIPricing, saveAIPricing, deleteAIPricing, translateTextStream, summarizeTextStream } from '../api';
 
 const AIConfigPage: React.FC = () => {
   const [form] = Form.useForm();
   const [testForm] = Form.useForm();
+  const [budgetForm] = Form.useForm();
+  const provider = Form.useWatch('provider', form);
+  const [budget, setBudget] = useState<any>(null);
+  const [cacheForm] = Form.useForm();
+  const [cache, setCache] = useState<any>(null);
+  const [modelForm] = Form.useForm();
+  const [modelSettings, setModelSettings] = useState<any[]>([]);
+  const [modelModalOpen, setModelModalOpen] = useState(false);
   const [loading, setLoading] = useState(false);
   const [testResult, setTestResult] = useState('');
   const [testing, setTesting] = useState(false);
@@ -20,10 +28,116 @@ const AIConfigPage: React.FC = () => {
     setLoading(false);
   };
 
+  const fetchBudget = async () => {
+    try {
+      const res = await getAIBudget();
+      setBudget(res.data);
+      budgetForm.setFieldsValue(res.data);
+    } catch {
+      message.error('获取预算失败');
+    }
+  };
+
+  const fetchCache = async () => {
+    try {
+      const res = await getTranslationCache();
+      setCache(res.data);
+      cacheForm.setFieldsValue(res.data.settings);
+    } catch {
+      message.error('获取缓存信息失败');
+    }
+  };
+
+  const fetchModelSettings = async () => {
+    try {
+      const res = await getAIPricing();
+      setModelSettings(res.data || []);
+    } catch {
+      message.error('获取模型参数失败');
+    }
+  };
+
   useEffect(() => {
     fetchConfig();
+    fetchBudget();
+    fetchCache();
+    fetchModelSettings();
   }, []);
 
+  const handleSaveModel = async (values: any) => {
+    try {
+      await saveAIPricing(values);
+      message.success('保存成功');
+      setModelModalOpen(false);
+      fetchModelSettings();
+    } catch (e: any) {
+      message.error(e.response?.data?.error || '保存失败');
+    }
+  };
+
+  const handleDeleteModel = async (model: string) => {
+    try {
+      await deleteAIPricing(model);
+      message.success('删除成功');
+      fetchModelSettings();
+    } catch {
+      message.error('删除失败');
+    }
+  };
+
+  const modelColumns = [
+    { title: '模型', dataIndex: 'model', key: 'model' },
+    { title: '输入单价', dataIndex: 'prompt_price', key: 'prompt_price', render: (v: number) => `$${v}` },
+    { title: '输出单价', dataIndex: 'completion_price', key: 'completion_price', render: (v: number) => `$${v}` },
+    { title: '上下文', dataIndex: 'context_tokens', key: 'context_tokens', render: (v: number) => v ? v.toLocaleString() : '默认' },
+    { title: '输出上限', dataIndex: 'max_output_tokens', key: 'max_output_tokens', render: (v: number) => v ? v.toLocaleString() : '默认' },
+    {
+      title: '操作',
+      key: 'action',
+      render: (_: any, record: any) => (
+        <Space>
+          <Button type="link" size="small" onClick={() => { modelForm.setFieldsValue(record); setModelModalOpen(true); }}>编辑</Button>
+          <Popconfirm title="确定删除?" onConfirm={() => handleDeleteModel(record.model)}>
+            <Button type="link" size="small" danger>删除</Button>
+          </Popconfirm>
+        </Space>
+      ),
+    },
+  ];
+
+  const handleSaveCache = async (values: any) => {
+    try {
+      const res = await saveTranslationCacheSettings(values);
+      message.success(res.data.removed > 0 ? `保存成功，已清理 ${res.data.removed} 条缓存` : '保存成功');
+      fetchCache();
+    } catch (e: any) {
+      message.error(e.response?.data?.error || '保存失败');
+    }
+  };
+
+  const handlePurgeCache = async () => {
+    try {
+      const res = await purgeTranslationCache();
+      message.success(`已清空 ${res.data.removed} 条缓存`);
+      fetchCache();
+    } catch {
+      message.error('清空失败');
+    }
+  };
+
+  const handleSaveBudget = async (values: any) => {
+    try {
+      await saveAIBudget(values);
+      message.success('保存成功');
+      fetchBudget();
+    } catch (e: any) {
+      message.error(e.response?.data?.error || '保存失败');
+    }
+  };
+
+  const formatAmount = (v: number) => budget?.unit === 'cost' ? `$${(v || 0).toFixed(4)}` : `${Math.round(v || 0).toLocaleString()} tokens`;
+  const percent = (spent: number, limit: number) => limit > 0 ? Math.min(100, Math.round(spent / limit * 100)) : 0;
+
   const handleSave = async (values: any) => {
     try {
       await saveAIConfig(values);
@@ -33,6 +147,17 @@ const AIConfigPage: React.FC = () => {
     }
   };
 
+  // streamTestResult 流式测试结果：逐段追加，完成时以完整结果为准
+  const streamTestResult = (event: string, data: any) => {
+    if (event === 'delta') {
+      setTestResult(prev => prev + data.text);
+    } else if (event === 'done') {
+      setTestResult(data.result);
+    } else if (event === 'error') {
+      message.error(data.error);
+    }
+  };
+
   const handleTestTranslate = async () => {
     const text = testForm.getFieldValue('test_text');
     if (!text) {
@@ -40,11 +165,11 @@ const AIConfigPage: React.FC = () => {
       return;
     }
     setTesting(true);
+    setTestResult('');
     try {
-      const res = await translateText(text);
-      setTestResult(res.data.result);
+      await translateTextStream(text, streamTestResult);
     } catch (e: any) {
-      message.error(e.response?.data?.error || '翻译失败');
+      message.error(e.message || '翻译失败');
     }
     setTesting(false);
   };
@@ -56,11 +181,11 @@ const AIConfigPage: React.FC = () => {
       return;
     }
     setTesting(true);
+    setTestResult('');
     try {
-      const res = await summarizeText(text);
-      setTestResult(res.data.result);
+      await summarizeTextStream(text, streamTestResult);
     } catch (e: any) {
-      message.error(e.response?.data?.error || '摘要失败');
+      message.error(e.message || '摘要失败');
     }
     setTesting(false);
   };
@@ -78,16 +203,31 @@ const AIConfigPage: React.FC = () => {
             model: 'gpt-4o-mini',
             enable_trans: true,
             enable_summary: true,
-            target_lang: 'zh-CN',
+            enable_tags: true,
+            enable_entities: true,
+            enable_sentiment: false,
+            target_langs: ['zh-CN'],
+            full_trans_categories: [],
+            rate_limit_rpm: 0,
+            rate_limit_tpm: 0,
+            timeout_seconds: 60,
+            max_retries: 3,
+            batch_concurrency: 2,
           }}>
             <Form.Item name="provider" label="AI 服务商">
               <Select options={[
                 { value: 'openai', label: 'OpenAI' },
                 { value: 'claude', label: 'Claude' },
                 { value: 'ollama', label: 'Ollama (本地)' },
+                { value: 'mock', label: 'Mock (离线模拟)' },
               ]} />
             </Form.Item>
-            <Form.Item name="api_key" label="API Key" rules={[{ required: true }]}>
+            {provider === 'mock' && (
+              <div style={{ marginBottom: 16, color: '#888' }}>
+                离线模拟不会发送任何请求：翻译为加 [mock] 前缀的原文，摘要为原文开头，过滤全部保留，用于本地开发、演示和测试。
+              </div>
+            )}
+            <Form.Item name="api_key" label="API Key" rules={[{ required: provider !== 'mock' }]}>
               <Input.Password placeholder="sk-..." />
             </Form.Item>
             <Form.Item name="base_url" label="Base URL" extra="留空使用默认地址">
@@ -115,11 +255,18 @@ const AIConfigPage: React.FC = () => {
                 }
               />
             </Form.Item>
-            <Form.Item name="target_lang" label="翻译目标语言">
-              <Select options={[
+            <Form.Item name="embedding_model" label="向量模型" extra="用于语义搜索和相关新闻，留空使用 text-embedding-3-small">
+              <Input placeholder="text-embedding-3-small" />
+            </Form.Item>
+            <Form.Item
+              name="target_langs"
+              label="翻译目标语言"
+              extra="可选择多个，第一个为主语言（列表和推送默认显示），其余语言的译文可在模板中用 {{with .T &quot;ug&quot;}}{{.Title}}{{end}} 引用"
+              rules={[{ required: true, message: '至少选择一个目标语言' }]}
+            >
+              <Select mode="multiple" options={[
                 { value: 'zh-CN', label: '简体中文' },
                 { value: 'ug', label: 'ئۇيغۇرچە (维吾尔语)' },
-                { value: 'zh-ug', label: '中文 + 维吾尔语 (双语)' },
                 { value: 'zh-TW', label: '繁体中文' },
                 { value: 'en', label: 'English' },
                 { value: 'ja', label: '日本語' },
@@ -127,6 +274,39 @@ const AIConfigPage: React.FC = () => {
                 { value: 'ar', label: 'العربية (阿拉伯语)' },
               ]} />
             </Form.Item>
+            <Form.Item
+              name="full_trans_categories"
+              label="自动全文翻译的分类"
+              extra="这些分类的新闻会按段落翻译全文（主语言）；也可在新闻源中单独开启，或在阅读窗口手动翻译"
+            >
+              <Select mode="multiple" allowClear options={[
+                { value: 'tech', label: '科技' },
+                { value: 'ai', label: 'AI' },
+                { value: 'github', label: 'GitHub' },
+                { value: 'international', label: '国际' },
+                { value: 'trending', label: '热门' },
+              ]} />
+            </Form.Item>
+
+            <Divider>限流与重试</Divider>
+
+            <Space wrap>
+              <Form.Item name="rate_limit_rpm" label="每分钟请求数" tooltip="0 表示不限制">
+                <InputNumber min={0} style={{ width: 140 }} />
+              </Form.Item>
+              <Form.Item name="rate_limit_tpm" label="每分钟 tokens" tooltip="按请求大小估算，0 表示不限制">
+                <InputNumber min={0} style={{ width: 140 }} />
+              </Form.Item>
+              <Form.Item name="timeout_seconds" label="超时（秒）" tooltip="每次请求等待响应或流式输出停顿的最长时间，排队和重试等待不计入">
+                <InputNumber min={1} style={{ width: 100 }} />
+              </Form.Item>
+              <Form.Item name="max_retries" label="重试次数" tooltip="遇到 429 或服务端错误时按指数退避重试">
+                <InputNumber min={0} max={10} style={{ width: 100 }} />
+              </Form.Item>
+              <Form.Item name="batch_concurrency" label="批量并发数" tooltip="同时进行的批量翻译请求数；每批条数按模型上下文和输出上限自动计算">
+                <InputNumber min={1} max={10} style={{ width: 100 }} />
+              </Form.Item>
+            </Space>
 
             <Divider>功能开关</Divider>
 
@@ -139,6 +319,18 @@ const AIConfigPage: React.FC = () => {
             <Form.Item name="enable_filter" label="启用智能筛选" valuePropName="checked" extra="AI自动过滤低价值新闻">
               <Switch />
             </Form.Item>
+            <Form.Item name="enable_tags" label="启用自动标签" valuePropName="checked" extra="从标签库中为新闻选择标签">
+              <Switch />
+            </Form.Item>
+            <Form.Item name="allow_new_tags" label="允许 AI 提议新标签" valuePropName="checked" extra="新标签需在标签管理中审核后生效">
+              <Switch />
+            </Form.Item>
+            <Form.Item name="enable_entities" label="启用实体提取" valuePropName="checked" extra="提取新闻中的公司、人物、产品和地点">
+              <Switch />
+            </Form.Item>
+            <Form.Item name="enable_sentiment" label="启用情感倾向分析" valuePropName="checked" extra="批量翻译时同时判断报道是正面、负面还是中性，可按情感筛选新闻，仪表盘按来源和实体统计">
+              <Switch />
+            </Form.Item>
 
             <Form.Item>
               <Button type="primary" htmlType="submit">保存配置</Button>
@@ -169,6 +361,104 @@ const AIConfigPage: React.FC = () => {
           )}
         </Card>
       </div>
+
+      <Card
+        title="模型参数"
+        style={{ marginTop: 24 }}
+        extra={<Button size="small" onClick={() => { modelForm.resetFields(); setModelModalOpen(true); }}>添加模型</Button>}
+      >
+        <div style={{ marginBottom: 16, color: '#888' }}>
+          单价（美元 / 百万 tokens）用于计算费用；上下文和输出上限用于批量翻译分批，未设置时按 8192 / 2048 估算。
+        </div>
+        <Table columns={modelColumns} dataSource={modelSettings} rowKey="model" size="small" pagination={false} />
+      </Card>
+
+      <Modal title="模型参数" open={modelModalOpen} onCancel={() => setModelModalOpen(false)} onOk={() => modelForm.submit()}>
+        <Form form={modelForm} layout="vertical" onFinish={handleSaveModel} initialValues={{ prompt_price: 0, completion_price: 0, context_tokens: 0, max_output_tokens: 0 }}>
+          <Form.Item name="model" label="模型" rules={[{ required: true }]}>
+            <Input />
+          </Form.Item>
+          <Space wrap>
+            <Form.Item name="prompt_price" label="输入单价">
+              <InputNumber min={0} step={0.01} style={{ width: 120 }} />
+            </Form.Item>
+            <Form.Item name="completion_price" label="输出单价">
+              <InputNumber min={0} step={0.01} style={{ width: 120 }} />
+            </Form.Item>
+            <Form.Item name="context_tokens" label="上下文 tokens" tooltip="0 表示使用默认值">
+              <InputNumber min={0} style={{ width: 140 }} />
+            </Form.Item>
+            <Form.Item name="max_output_tokens" label="输出上限 tokens" tooltip="0 表示使用默认值">
+              <InputNumber min={0} style={{ width: 140 }} />
+            </Form.Item>
+          </Space>
+        </Form>
+      </Modal>
+
+      <Card title="AI 预算" style={{ marginTop: 24 }} extra={budget?.exceeded && <span style={{ color: '#ff4d4f' }}>预算已用尽</span>}>
+        {budget && (
+          <div style={{ display: 'flex', gap: 48, marginBottom: 24 }}>
+            <div style={{ flex: 1 }}>
+              <div>今日：{formatAmount(budget.daily_spent)}{budget.daily > 0 && ` / ${formatAmount(budget.daily)}`}</div>
+              {budget.daily > 0 && <Progress percent={percent(budget.daily_spent, budget.daily)} status={budget.daily_spent >= budget.daily ? 'exception' : 'normal'} />}
+            </div>
+            <div style={{ flex: 1 }}>
+              <div>本月：{formatAmount(budget.monthly_spent)}{budget.monthly > 0 && ` / ${formatAmount(budget.monthly)}`}</div>
+              {budget.monthly > 0 && <Progress percent={percent(budget.monthly_spent, budget.monthly)} status={budget.monthly_spent >= budget.monthly ? 'exception' : 'normal'} />}
+            </div>
+          </div>
+        )}
+        <Form form={budgetForm} layout="inline" onFinish={handleSaveBudget} initialValues={{ unit: 'tokens', daily: 0, monthly: 0 }}>
+          <Form.Item name="unit" label="单位">
+            <Select style={{ width: 120 }} options={[{ value: 'tokens', label: 'Tokens' }, { value: 'cost', label: '费用（美元）' }]} />
+          </Form.Item>
+          <Form.Item name="daily" label="每日上限" tooltip="0 表示不限制">
+            <InputNumber min={0} style={{ width: 140 }} />
+          </Form.Item>
+          <Form.Item name="monthly" label="每月上限" tooltip="0 表示不限制">
+            <InputNumber min={0} style={{ width: 140 }} />
+          </Form.Item>
+          <Form.Item name="fallback_model" label="降级模型" tooltip="预算用尽后改用的廉价模型，其自身用量同样受上述上限约束；留空则新闻不经翻译直接进入阅读窗口，未翻译的新闻不会推送，预算恢复后需补译">
+            <Input placeholder="留空不调用 AI" style={{ width: 180 }} />
+          </Form.Item>
+          <Form.Item>
+            <Button type="primary" htmlType="submit">保存预算</Button>
+          </Form.Item>
+        </Form>
+      </Card>
+
+      <Card
+        title="翻译缓存"
+        style={{ marginTop: 24 }}
+        extra={
+          <Popconfirm title="确定清空全部翻译缓存？" onConfirm={handlePurgeCache}>
+            <Button danger size="small">清空缓存</Button>
+          </Popconfirm>
+        }
+      >
+        {cache && (
+          <div style={{ marginBottom: 24 }}>
+            共 {cache.entries} 条缓存，累计命中 {cache.hits} 次
+            {Object.entries(cache.by_kind || {}).map(([kind, count]) => (
+              <span key={kind} style={{ marginLeft: 16, color: '#888' }}>{kind}: {count as number}</span>
+            ))}
+          </div>
+        )}
+        <Form form={cacheForm} layout="inline" onFinish={handleSaveCache}>
+          <Form.Item name="enabled" label="启用" valuePropName="checked">
+            <Switch />
+          </Form.Item>
+          <Form.Item name="ttl_days" label="有效期（天）" tooltip="0 表示永不过期">
+            <InputNumber min={0} style={{ width: 120 }} />
+          </Form.Item>
+          <Form.Item name="max_entries" label="最多条数" tooltip="超出后淘汰最久未使用的缓存，0 表示不限制">
+            <InputNumber min={0} style={{ width: 140 }} />
+          </Form.Item>
+          <Form.Item>
+            <Button type="primary" htmlType="submit">保存</Button>
+          </Form.Item>
+        </Form>
+      </Card>
     </div>
   );
 };
@@ -1,12 +1,15 @@
 import React, { useEffect, useState } from 'react';
-import { Row, Col, Card, Statistic, List, Tag, Button, message, Spin } from 'antd';
-import { ReloadOutlined, RobotOutlined, GlobalOutlined, SendOutlined, FileTextOutlined } from '@ant-design/icons';
-import { getStats, getNews, triggerCollect, triggerProcess } from '../api';
+import { Row, Col, Card, Statistic, List, Tag, Button, message, Spin, Alert } from 'antd';
+import { ReloadOutlined, RobotOutlined, GlobalOutlined, SendOutlined, FileTextOutlined, RiseOutlined } from '@ant-design/icons';
+import { getStats, getNews, getAIUsage, getAlerts, acknowledgeAlert, getTrends, triggerCollect, triggerProcess } from '../api';
 import dayjs from 'dayjs';
 
 const Dashboard: React.FC = () => {
   const [stats, setStats] = useState<any>({});
   const [news, setNews] = useState<any[]>([]);
+  const [usage, setUsage] = useState<any>(null);
+  const [alerts, setAlerts] = useState<any[]>([]);
+  const [trends, setTrends] = useState<any[]>([]);
   const [loading, setLoading] = useState(true);
 
   const fetchData = async () => {
@@ -18,6 +21,9 @@ const Dashboard: React.FC = () => {
     } catch (e) {
       console.error(e);
     }
+    getAIUsage(30).then(res => setUsage(res.data)).catch(() => setUsage(null));
+    getAlerts().then(res => setAlerts(res.data || [])).catch(() => setAlerts([]));
+    getTrends({ limit: 10 }).then(res => setTrends(res.data.data || [])).catch(() => setTrends([]));
     setLoading(false);
   };
 
@@ -51,6 +57,20 @@ const Dashboard: React.FC = () => {
     trending: 'red',
   };
 
+  const renderSentiment = (g: any) => (
+    <div key={g.key} style={{ display: 'flex', justifyContent: 'space-between', marginBottom: 8 }}>
+      <span>{g.key}</span>
+      <span>
+        <Tag color="green">{g.positive}</Tag>
+        <Tag>{g.neutral}</Tag>
+        <Tag color="red">{g.negative}</Tag>
+        <span style={{ color: g.avg_score >= 0 ? '#52c41a' : '#ff4d4f' }}>{g.avg_score.toFixed(2)}</span>
+      </span>
+    </div>
+  );
+
+  const trendKindColors: Record<string, string> = { term: 'blue', entity: 'purple', tag: 'green' };
+
   if (loading) {
     return <Spin size="large" style={{ display: 'block', margin: '100px auto' }} />;
   }
@@ -69,6 +89,19 @@ const Dashboard: React.FC = () => {
         </div>
       </div>
 
+      {alerts.map(a => (
+        <Alert
+          key={a.id}
+          type={a.level === 'error' ? 'error' : a.level === 'info' ? 'info' : 'warning'}
+          message={a.message}
+          description={dayjs(a.created_at).format('MM-DD HH:mm')}
+          showIcon
+          closable
+          onClose={() => acknowledgeAlert(a.id)}
+          style={{ marginBottom: 16 }}
+        />
+      ))}
+
       <Row gutter={[16, 16]} style={{ marginBottom: 24 }}>
         <Col xs={24} sm={12} lg={6}>
           <Card className="stat-card">
@@ -144,6 +177,50 @@ const Dashboard: React.FC = () => {
               </div>
             ))}
           </Card>
+          <Card title={<span><RiseOutlined /> 热点趋势</span>} extra={<a href="/trends">查看全部</a>} style={{ marginTop: 16 }}>
+            {trends.length === 0 && <span style={{ color: '#888' }}>暂无突增的热点</span>}
+            {trends.map(t => (
+              <div key={`${t.kind}:${t.term}`} style={{ display: 'flex', justifyContent: 'space-between', marginBottom: 8 }}>
+                <span><Tag color={trendKindColors[t.kind]}>{t.term}</Tag></span>
+                <span style={{ color: '#888' }}>{t.count} 篇 · 基线 {t.baseline.toFixed(1)}</span>
+              </div>
+            ))}
+          </Card>
+          {stats.sentiment?.overall?.count > 0 && (
+            <Card title={`情感倾向（近 ${stats.sentiment.days} 天）`} style={{ marginTop: 16 }}>
+              {renderSentiment({ ...stats.sentiment.overall, key: '全部' })}
+              <div style={{ color: '#888', margin: '12px 0 8px' }}>按来源</div>
+              {(stats.sentiment.by_source || []).slice(0, 5).map(renderSentiment)}
+              {stats.sentiment.by_entity?.length > 0 && <div style={{ color: '#888', margin: '12px 0 8px' }}>按实体</div>}
+              {(stats.sentiment.by_entity || []).slice(0, 5).map(renderSentiment)}
+            </Card>
+          )}
+          {usage && (
+            <Card title={<span><RobotOutlined /> AI 用量</span>} style={{ marginTop: 16 }}>
+              <Row gutter={16}>
+                <Col span={12}>
+                  <Statistic title="今日费用" value={usage.today?.cost || 0} precision={4} prefix="$" />
+                </Col>
+                <Col span={12}>
+                  <Statistic title={`近 ${usage.days} 天费用`} value={usage.total?.cost || 0} precision={4} prefix="$" />
+                </Col>
+              </Row>
+              <div style={{ display: 'flex', justifyContent: 'space-between', marginTop: 12 }}>
+                <span>今日 tokens</span>
+                <span>{((usage.today?.prompt_tokens || 0) + (usage.today?.completion_tokens || 0)).toLocaleString()}</span>
+              </div>
+              <div style={{ display: 'flex', justifyContent: 'space-between', marginTop: 8 }}>
+                <span>今日调用 / 失败</span>
+                <span>{usage.today?.calls || 0} / {usage.today?.errors || 0}</span>
+              </div>
+              {(usage.by_operation || []).map((op: any) => (
+                <div key={op.key} style={{ display: 'flex', justifyContent: 'space-between', marginTop: 8, color: '#888' }}>
+                  <span>{op.key}</span>
+                  <span>${op.cost.toFixed(4)}</span>
+                </div>
+              ))}
+            </Card>
+          )}
         </Col>
       </Row>
     </div>
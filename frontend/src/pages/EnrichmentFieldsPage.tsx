@@ -0,0 +1,98 @@
+import React, { useEffect, useState } from 'react';
+import { Button, Card, Form, Input, Select, Space, message, Spin } from 'antd';
+import { PlusOutlined, DeleteOutlined, SaveOutlined } from '@ant-design/icons';
+import { getEnrichmentFields, saveEnrichmentFields } from '../api';
+
+const typeOptions = [
+  { value: 'string', label: '文本' },
+  { value: 'number', label: '数字' },
+  { value: 'boolean', label: '是 / 否' },
+  { value: 'list', label: '文本列表' },
+];
+
+const EnrichmentFieldsPage: React.FC = () => {
+  const [loading, setLoading] = useState(false);
+  const [saving, setSaving] = useState(false);
+  const [form] = Form.useForm();
+
+  const fetchFields = async () => {
+    setLoading(true);
+    try {
+      const res = await getEnrichmentFields();
+      form.setFieldsValue({ fields: res.data || [] });
+    } catch {
+      message.error('获取失败');
+    }
+    setLoading(false);
+  };
+
+  useEffect(() => {
+    fetchFields();
+  }, []);
+
+  const handleSave = async (values: any) => {
+    setSaving(true);
+    try {
+      const res = await saveEnrichmentFields(values.fields || []);
+      form.setFieldsValue({ fields: res.data || [] });
+      message.success('保存成功');
+    } catch (e: any) {
+      message.error(e.response?.data?.error || '保存失败');
+    }
+    setSaving(false);
+  };
+
+  return (
+    <div>
+      <div className="page-header" style={{ display: 'flex', justifyContent: 'space-between' }}>
+        <h2>自定义字段</h2>
+        <Button type="primary" icon={<SaveOutlined />} loading={saving} onClick={() => form.submit()}>
+          保存
+        </Button>
+      </div>
+
+      <div style={{ marginBottom: 16, color: '#888' }}>
+        AI 批量翻译新闻时按说明一并提取这些字段，新闻中没有相关信息时字段为空。
+        提取结果显示在新闻列表中，可点击筛选，也可在邮件模板中以 {'{{.Fields.字段名}}'} 引用。修改后只对之后翻译的新闻生效。
+      </div>
+
+      <Spin spinning={loading}>
+        <Card>
+          <Form form={form} onFinish={handleSave} initialValues={{ fields: [] }}>
+            <Form.List name="fields">
+              {(fields, { add, remove }) => (
+                <>
+                  {fields.map(({ key, name }) => (
+                    <Space key={key} align="baseline" wrap style={{ display: 'flex', marginBottom: 8 }}>
+                      <Form.Item
+                        name={[name, 'name']}
+                        rules={[
+                          { required: true, message: '请输入字段名' },
+                          { pattern: /^[A-Za-z][A-Za-z0-9_]{0,39}$/, message: '字母开头，只含字母、数字和下划线' },
+                        ]}
+                      >
+                        <Input placeholder="字段名，如 ticker" style={{ width: 180 }} />
+                      </Form.Item>
+                      <Form.Item name={[name, 'type']} rules={[{ required: true, message: '请选择类型' }]}>
+                        <Select options={typeOptions} placeholder="类型" style={{ width: 120 }} />
+                      </Form.Item>
+                      <Form.Item name={[name, 'instruction']} rules={[{ required: true, message: '请输入提取说明' }, { max: 500 }]}>
+                        <Input placeholder="提取说明，如：新闻涉及的上市公司股票代码" style={{ width: 420 }} />
+                      </Form.Item>
+                      <Button type="link" danger icon={<DeleteOutlined />} onClick={() => remove(name)} />
+                    </Space>
+                  ))}
+                  <Button type="dashed" icon={<PlusOutlined />} onClick={() => add({ type: 'string' })} disabled={fields.length >= 20}>
+                    添加字段
+                  </Button>
+                </>
+              )}
+            </Form.List>
+          </Form>
+        </Card>
+      </Spin>
+    </div>
+  );
+};
+
+export default EnrichmentFieldsPage;
@@ -0,0 +1,187 @@
+import React, { useEffect, useState } from 'react';
+import { Table, Button, Modal, Form, Input, Select, Switch, message, Popconfirm, Space, Card, Tag } from 'antd';
+import { PlusOutlined, EditOutlined, DeleteOutlined } from '@ant-design/icons';
+import dayjs from 'dayjs';
+import { getGlossary, createGlossaryTerm, updateGlossaryTerm, deleteGlossaryTerm, getGlossaryViolations, clearGlossaryViolations } from '../api';
+
+const langOptions = [
+  { value: '', label: '所有语言' },
+  { value: 'zh-CN', label: '简体中文' },
+  { value: 'ug', label: 'ئۇيغۇرچە (维吾尔语)' },
+  { value: 'zh-TW', label: '繁体中文' },
+  { value: 'en', label: 'English' },
+  { value: 'ja', label: '日本語' },
+  { value: 'ko', label: '한국어 (韩语)' },
+  { value: 'ar', label: 'العربية (阿拉伯语)' },
+];
+
+const GlossaryPage: React.FC = () => {
+  const [terms, setTerms] = useState<any[]>([]);
+  const [violations, setViolations] = useState<any[]>([]);
+  const [loading, setLoading] = useState(false);
+  const [modalOpen, setModalOpen] = useState(false);
+  const [editingId, setEditingId] = useState<string | null>(null);
+  const [form] = Form.useForm();
+  const keepOriginal = Form.useWatch('keep_original', form);
+
+  const fetchTerms = async () => {
+    setLoading(true);
+    try {
+      const res = await getGlossary();
+      setTerms(res.data || []);
+    } catch {
+      message.error('获取失败');
+    }
+    setLoading(false);
+  };
+
+  const fetchViolations = async () => {
+    try {
+      const res = await getGlossaryViolations();
+      setViolations(res.data || []);
+    } catch {
+      message.error('获取违规记录失败');
+    }
+  };
+
+  useEffect(() => {
+    fetchTerms();
+    fetchViolations();
+  }, []);
+
+  const handleSubmit = async (values: any) => {
+    try {
+      if (editingId) {
+        await updateGlossaryTerm(editingId, values);
+        message.success('更新成功');
+      } else {
+        await createGlossaryTerm(values);
+        message.success('创建成功');
+      }
+      setModalOpen(false);
+      form.resetFields();
+      setEditingId(null);
+      fetchTerms();
+    } catch (e: any) {
+      message.error(e.response?.data?.error || '操作失败');
+    }
+  };
+
+  const handleEdit = (record: any) => {
+    setEditingId(record.id);
+    form.setFieldsValue(record);
+    setModalOpen(true);
+  };
+
+  const handleDelete = async (id: string) => {
+    try {
+      await deleteGlossaryTerm(id);
+      message.success('删除成功');
+      fetchTerms();
+    } catch {
+      message.error('删除失败');
+    }
+  };
+
+  const handleClearViolations = async () => {
+    try {
+      await clearGlossaryViolations();
+      message.success('已清空');
+      fetchViolations();
+    } catch {
+      message.error('操作失败');
+    }
+  };
+
+  const langLabel = (lang: string) => langOptions.find((o) => o.value === lang)?.label || lang;
+
+  const columns = [
+    { title: '原文术语', dataIndex: 'term', key: 'term' },
+    {
+      title: '译法',
+      key: 'translation',
+      render: (_: any, record: any) => record.keep_original ? <Tag>保留原文</Tag> : record.translation,
+    },
+    { title: '目标语言', dataIndex: 'lang', key: 'lang', render: langLabel },
+    { title: '备注', dataIndex: 'note', key: 'note', ellipsis: true },
+    {
+      title: '操作',
+      key: 'action',
+      render: (_: any, record: any) => (
+        <Space>
+          <Button type="link" icon={<EditOutlined />} onClick={() => handleEdit(record)} />
+          <Popconfirm title="确定删除?" onConfirm={() => handleDelete(record.id)}>
+            <Button type="link" danger icon={<DeleteOutlined />} />
+          </Popconfirm>
+        </Space>
+      ),
+    },
+  ];
+
+  const violationColumns = [
+    { title: '时间', dataIndex: 'created_at', key: 'created_at', render: (v: string) => dayjs(v).format('MM-DD HH:mm') },
+    { title: '术语', dataIndex: 'term', key: 'term' },
+    { title: '应为', dataIndex: 'expected', key: 'expected' },
+    { title: '语言', dataIndex: 'lang', key: 'lang' },
+    { title: '操作', dataIndex: 'operation', key: 'operation' },
+    { title: '译文', dataIndex: 'output', key: 'output', ellipsis: true },
+  ];
+
+  return (
+    <div>
+      <div className="page-header" style={{ display: 'flex', justifyContent: 'space-between' }}>
+        <h2>术语表</h2>
+        <Button type="primary" icon={<PlusOutlined />} onClick={() => { form.resetFields(); setEditingId(null); setModalOpen(true); }}>
+          添加术语
+        </Button>
+      </div>
+
+      <div style={{ marginBottom: 16, color: '#888' }}>
+        翻译、摘要和批量翻译时，原文中出现的术语会连同指定译法一起注入提示词；译文未使用指定译法时记录在下方的违规列表中。
+      </div>
+
+      <Table columns={columns} dataSource={terms} rowKey="id" loading={loading} />
+
+      <Card
+        title="违规记录"
+        style={{ marginTop: 24 }}
+        extra={
+          <Popconfirm title="确定清空违规记录？" onConfirm={handleClearViolations}>
+            <Button danger size="small">清空</Button>
+          </Popconfirm>
+        }
+      >
+        <Table columns={violationColumns} dataSource={violations} rowKey="id" size="small" pagination={{ pageSize: 10 }} />
+      </Card>
+
+      <Modal
+        title={editingId ? '编辑术语' : '添加术语'}
+        open={modalOpen}
+        onCancel={() => setModalOpen(false)}
+        onOk={() => form.submit()}
+      >
+        <Form form={form} layout="vertical" onFinish={handleSubmit} initialValues={{ lang: '', keep_original: false }}>
+          <Form.Item name="term" label="原文术语" rules={[{ required: true }]}>
+            <Input placeholder="Anthropic" />
+          </Form.Item>
+          <Form.Item name="keep_original" label="保留原文不翻译" valuePropName="checked">
+            <Switch />
+          </Form.Item>
+          {!keepOriginal && (
+            <Form.Item name="translation" label="指定译法" rules={[{ required: true }]}>
+              <Input />
+            </Form.Item>
+          )}
+          <Form.Item name="lang" label="目标语言">
+            <Select options={langOptions} />
+          </Form.Item>
+          <Form.Item name="note" label="备注">
+            <Input />
+          </Form.Item>
+        </Form>
+      </Modal>
+    </div>
+  );
+};
+
+export default GlossaryPage;
@@ -0,0 +1,141 @@
+import React, { useEffect, useState } from 'react';
+import { Table, Button, Select, Space, Tag, Tooltip, message } from 'antd';
+import { ReloadOutlined } from '@ant-design/icons';
+import dayjs from 'dayjs';
+import { getJobs, retryJob, cancelJob } from '../api';
+
+const statusMeta: Record<string, { label: string; color: string }> = {
+  pending: { label: '排队中', color: 'blue' },
+  running: { label: '执行中', color: 'processing' },
+  done: { label: '已完成', color: 'green' },
+  failed: { label: '失败', color: 'red' },
+  cancelled: { label: '已取消', color: 'default' },
+};
+
+const typeLabels: Record<string, string> = {
+  collect: '采集',
+  translate: '翻译',
+  process: '处理',
+};
+
+const JobsPage: React.FC = () => {
+  const [jobs, setJobs] = useState<any[]>([]);
+  const [counts, setCounts] = useState<Record<string, number>>({});
+  const [status, setStatus] = useState<string>('');
+  const [loading, setLoading] = useState(false);
+
+  const fetchJobs = async () => {
+    setLoading(true);
+    try {
+      const res = await getJobs(status || undefined);
+      setJobs(res.data.jobs || []);
+      setCounts(res.data.counts || {});
+    } catch {
+      message.error('获取失败');
+    }
+    setLoading(false);
+  };
+
+  useEffect(() => {
+    fetchJobs();
+    const timer = setInterval(fetchJobs, 10000);
+    return () => clearInterval(timer);
+  }, [status]);
+
+  const handleRetry = async (id: string) => {
+    try {
+      await retryJob(id);
+      message.success('已重新排队');
+      fetchJobs();
+    } catch (e: any) {
+      message.error(e.response?.data?.error || '操作失败');
+    }
+  };
+
+  const handleCancel = async (id: string) => {
+    try {
+      await cancelJob(id);
+      message.success('已取消');
+      fetchJobs();
+    } catch (e: any) {
+      message.error(e.response?.data?.error || '操作失败');
+    }
+  };
+
+  const formatTime = (v: string) => (v && !v.startsWith('0001') ? dayjs(v).format('MM-DD HH:mm:ss') : '-');
+
+  const columns = [
+    { title: '创建时间', dataIndex: 'created_at', key: 'created_at', render: formatTime },
+    { title: '类型', dataIndex: 'type', key: 'type', render: (v: string) => typeLabels[v] || v },
+    {
+      title: '状态',
+      dataIndex: 'status',
+      key: 'status',
+      render: (v: string) => <Tag color={statusMeta[v]?.color}>{statusMeta[v]?.label || v}</Tag>,
+    },
+    {
+      title: '尝试次数',
+      key: 'attempts',
+      render: (_: any, record: any) => `${record.attempts}/${record.max_attempts}`,
+    },
+    {
+      title: '下次执行',
+      dataIndex: 'next_run_at',
+      key: 'next_run_at',
+      render: (v: string, record: any) => (record.status === 'pending' ? formatTime(v) : '-'),
+    },
+    { title: '完成时间', dataIndex: 'finished_at', key: 'finished_at', render: formatTime },
+    {
+      title: '错误',
+      dataIndex: 'last_error',
+      key: 'last_error',
+      ellipsis: true,
+      render: (v: string) => (v ? <Tooltip title={v}>{v}</Tooltip> : '-'),
+    },
+    {
+      title: '操作',
+      key: 'action',
+      render: (_: any, record: any) => (
+        <Space>
+          {(record.status === 'failed' || record.status === 'cancelled') && (
+            <Button type="link" size="small" onClick={() => handleRetry(record.id)}>重试</Button>
+          )}
+          {record.status === 'pending' && (
+            <Button type="link" size="small" danger onClick={() => handleCancel(record.id)}>取消</Button>
+          )}
+        </Space>
+      ),
+    },
+  ];
+
+  return (
+    <div>
+      <div className="page-header" style={{ display: 'flex', justifyContent: 'space-between' }}>
+        <h2>后台任务</h2>
+        <Space>
+          <Select
+            value={status}
+            onChange={setStatus}
+            style={{ width: 160 }}
+            options={[
+              { value: '', label: '全部状态' },
+              ...Object.entries(statusMeta).map(([value, meta]) => ({
+                value,
+                label: `${meta.label} (${counts[value] || 0})`,
+              })),
+            ]}
+          />
+          <Button icon={<ReloadOutlined />} onClick={fetchJobs}>刷新</Button>
+        </Space>
+      </div>
+
+      <div style={{ marginBottom: 16, color: '#888' }}>
+        采集、翻译和处理任务保存在数据库中，失败后按指数退避自动重试，服务重启后继续执行未完成的任务。
+      </div>
+
+      <Table columns={columns} dataSource={jobs} rowKey="id" loading={loading} />
+    </div>
+  );
+};
+
+export default JobsPage;
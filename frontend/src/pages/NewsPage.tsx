@@ -1,5 +1,5 @@
 import React, { useEffect, useState } from 'react';
-import { Card, List, Tag, Select, Button, Pagination, message, Popconfirm, Empty, Spin } from 'antd';
+import { Card, List, Tag, Select, Button, Pagination, message, Popconfirm, Empty, Spin, Tooltip } from 'antd';
 import { ReloadOutlined, DeleteOutlined } from '@ant-design/icons';
 import { getNews, deleteNews, triggerCollect } from '../api';
 import dayjs from 'dayjs';
@@ -9,6 +9,8 @@ const NewsPage: React.FC = () => {
   const [total, setTotal] = useState(0);
   const [loading, setLoading] = useState(false);
   const [category, setCategory] = useState<string>('');
+  const [sentiment, setSentiment] = useState<string>('');
+  const [fieldFilter, setFieldFilter] = useState<{ name: string; value: string } | null>(null);
   const [page, setPage] = useState(1);
   const pageSize = 20;
 
@@ -17,6 +19,8 @@ const NewsPage: React.FC = () => {
     try {
       const res = await getNews({
         category: category || undefined,
+        sentiment: sentiment || undefined,
+        ...(fieldFilter ? { [`field.${fieldFilter.name}`]: fieldFilter.value } : {}),
         limit: pageSize,
         offset: (page - 1) * pageSize,
       });
@@ -30,7 +34,7 @@ const NewsPage: React.FC = () => {
 
   useEffect(() => {
     fetchNews();
-  }, [category, page]);
+  }, [category, sentiment, fieldFilter, page]);
 
   const handleDelete = async (id: string) => {
     try {
@@ -68,6 +72,26 @@ const NewsPage: React.FC = () => {
     { value: 'trending', label: '热门' },
   ];
 
+  const sentimentOptions = [
+    { value: '', label: '全部情感' },
+    { value: 'positive', label: '正面' },
+    { value: 'neutral', label: '中性' },
+    { value: 'negative', label: '负面' },
+  ];
+
+  const fieldValue = (v: any) => (Array.isArray(v) ? v.join(', ') : typeof v === 'boolean' ? (v ? '是' : '否') : String(v));
+
+  const filterByField = (name: string, v: any) => {
+    setFieldFilter({ name, value: Array.isArray(v) ? String(v[0]) : String(v) });
+    setPage(1);
+  };
+
+  const sentimentTags: Record<string, { color: string; label: string }> = {
+    positive: { color: 'green', label: '正面' },
+    neutral: { color: 'default', label: '中性' },
+    negative: { color: 'red', label: '负面' },
+  };
+
   return (
     <div>
       <div className="page-header" style={{ display: 'flex', justifyContent: 'space-between', alignItems: 'center' }}>
@@ -79,6 +103,17 @@ const NewsPage: React.FC = () => {
             onChange={setCategory}
             options={categories}
           />
+          <Select
+            style={{ width: 120, marginRight: 8 }}
+            value={sentiment}
+            onChange={(v) => { setSentiment(v); setPage(1); }}
+            options={sentimentOptions}
+          />
+          {fieldFilter && (
+            <Tag closable onClose={() => { setFieldFilter(null); setPage(1); }} style={{ marginRight: 8 }}>
+              {fieldFilter.name} = {fieldFilter.value}
+            </Tag>
+          )}
           <Button icon={<ReloadOutlined />} onClick={handleCollect}>
             立即采集
           </Button>
@@ -115,7 +150,25 @@ const NewsPage: React.FC = () => {
                       <Tag color={categoryColors[item.category] || 'default'}>{item.category}</Tag>
                       <span>{item.source}</span>
                       <span style={{ marginLeft: 8 }}>{dayjs(item.created_at).format('MM-DD HH:mm')}</span>
+                      {sentimentTags[item.sentiment] && (
+                        <Tooltip title={`情感评分 ${item.sentiment_score?.toFixed(2) ?? '-'}`}>
+                          <Tag color={sentimentTags[item.sentiment].color} style={{ marginLeft: 8 }}>
+                            {sentimentTags[item.sentiment].label}
+                          </Tag>
+                        </Tooltip>
+                      )}
                     </div>
+                    {item.fields && (
+                      <div style={{ marginTop: 8 }}>
+                        {Object.entries(item.fields).map(([name, v]) => (
+                          <Tooltip title="点击按该字段筛选" key={name}>
+                            <Tag style={{ cursor: 'pointer' }} onClick={() => filterByField(name, v)}>
+                              {name}: {fieldValue(v)}
+                            </Tag>
+                          </Tooltip>
+                        ))}
+                      </div>
+                    )}
                     <div className="news-summary" style={{ marginTop: 8 }}>
                       {item.trans_summary || item.summary || '暂无摘要'}
                     </div>
@@ -0,0 +1,129 @@
+import React, { useEffect, useState } from 'react';
+import { Card, Form, Switch, InputNumber, Button, message, Descriptions, Tag, Space, Row, Col, Empty } from 'antd';
+import { ReloadOutlined } from '@ant-design/icons';
+import { getPreferences, savePreferences, trainPreferences } from '../api';
+import dayjs from 'dayjs';
+
+// 特征前缀的显示名称
+const featureLabel = (feature: string) => {
+  const [prefix, ...rest] = feature.split(':');
+  const names: Record<string, string> = { source: '来源', category: '分类', tag: '标签' };
+  if (rest.length > 0 && names[prefix]) {
+    return `${names[prefix]}: ${rest.join(':')}`;
+  }
+  return feature;
+};
+
+const PreferencesPage: React.FC = () => {
+  const [status, setStatus] = useState<any>(null);
+  const [training, setTraining] = useState(false);
+  const [form] = Form.useForm();
+
+  const applyStatus = (data: any) => {
+    setStatus(data);
+    form.setFieldsValue({ auto_filter: data.auto_filter, threshold: data.threshold, min_feedback: data.min_feedback });
+  };
+
+  const fetchStatus = async () => {
+    try {
+      const res = await getPreferences();
+      applyStatus(res.data);
+    } catch {
+      message.error('获取失败');
+    }
+  };
+
+  useEffect(() => {
+    fetchStatus();
+  }, []);
+
+  const handleSave = async (values: any) => {
+    try {
+      const res = await savePreferences(values);
+      applyStatus(res.data);
+      message.success('保存成功，已重新训练');
+    } catch (e: any) {
+      message.error(e.response?.data?.error || '保存失败');
+    }
+  };
+
+  const handleTrain = async () => {
+    setTraining(true);
+    try {
+      const res = await trainPreferences();
+      applyStatus(res.data);
+      message.success(res.data.trained ? '训练完成' : '反馈不足，暂未训练');
+    } catch (e: any) {
+      message.error(e.response?.data?.error || '训练失败');
+    }
+    setTraining(false);
+  };
+
+  const renderFeatures = (list: any[], color: string) =>
+    list?.length ? (
+      <Space wrap>
+        {list.map((f) => (
+          <Tag key={f.feature} color={color}>{featureLabel(f.feature)} ({f.weight.toFixed(2)})</Tag>
+        ))}
+      </Space>
+    ) : (
+      <Empty image={Empty.PRESENTED_IMAGE_SIMPLE} />
+    );
+
+  return (
+    <div>
+      <div className="page-header" style={{ display: 'flex', justifyContent: 'space-between' }}>
+        <h2>偏好模型</h2>
+        <Button icon={<ReloadOutlined />} loading={training} onClick={handleTrain}>
+          立即训练
+        </Button>
+      </div>
+
+      <div style={{ marginBottom: 16, color: '#888' }}>
+        在阅读窗口对新闻点赞、点踩或标记不感兴趣，系统会据此在本地训练一个朴素贝叶斯模型（基于标题词、来源、分类和标签，不调用 AI），每小时自动重新训练。
+        评分可用于阅读窗口排序；开启自动过滤后，新采集的低分新闻不再翻译，也不会进入阅读窗口，可在全部新闻中查看被过滤的新闻。
+      </div>
+
+      <Row gutter={16}>
+        <Col xs={24} lg={10}>
+          <Card title="设置" style={{ marginBottom: 16 }}>
+            <Form form={form} layout="vertical" onFinish={handleSave}>
+              <Form.Item name="auto_filter" label="自动过滤低分新闻" valuePropName="checked">
+                <Switch />
+              </Form.Item>
+              <Form.Item name="threshold" label="过滤阈值" extra="评分（0-1）低于该值的新闻被过滤">
+                <InputNumber min={0} max={1} step={0.05} />
+              </Form.Item>
+              <Form.Item name="min_feedback" label="最少反馈数" extra="反馈数达到该值且赞和踩都有时才训练模型">
+                <InputNumber min={2} max={1000} />
+              </Form.Item>
+              <Button type="primary" htmlType="submit">保存</Button>
+            </Form>
+          </Card>
+        </Col>
+        <Col xs={24} lg={14}>
+          <Card title="模型状态" style={{ marginBottom: 16 }}>
+            <Descriptions column={1} size="small">
+              <Descriptions.Item label="状态">
+                {status?.trained ? <Tag color="green">已训练</Tag> : <Tag>反馈不足，未训练</Tag>}
+              </Descriptions.Item>
+              <Descriptions.Item label="训练时间">
+                {status?.trained ? dayjs(status.trained_at).format('YYYY-MM-DD HH:mm') : '-'}
+              </Descriptions.Item>
+              <Descriptions.Item label="正向反馈">{status?.positive ?? 0}</Descriptions.Item>
+              <Descriptions.Item label="负向反馈">{status?.negative ?? 0}</Descriptions.Item>
+            </Descriptions>
+          </Card>
+          <Card title="偏好的特征" size="small" style={{ marginBottom: 16 }}>
+            {renderFeatures(status?.liked, 'blue')}
+          </Card>
+          <Card title="不感兴趣的特征" size="small">
+            {renderFeatures(status?.disliked, 'red')}
+          </Card>
+        </Col>
+      </Row>
+    </div>
+  );
+};
+
+export default PreferencesPage;
@@ -1,7 +1,7 @@
 import React, { useEffect, useState } from 'react';
-import { Card, List, Tag, Select, Button, Pagination, message, Popconfirm, Empty, Spin, Badge, Space, Tooltip } from 'antd';
-import { DeleteOutlined, ClearOutlined, CheckCircleOutlined } from '@ant-design/icons';
-import { getReadingNews, removeFromReading, clearPushedNews } from '../api';
+import { Card, List, Tag, Select, Button, Pagination, message, Popconfirm, Empty, Spin, Badge, Space, Tooltip, Modal } from 'antd';
+import { DeleteOutlined, ClearOutlined, CheckCircleOutlined, TranslationOutlined, LikeOutlined, LikeFilled, DislikeOutlined, DislikeFilled, EyeInvisibleOutlined } from '@ant-design/icons';
+import { getReadingNews, removeFromReading, clearPushedNews, translateFull, setNewsFeedback, deleteNewsFeedback } from '../api';
 import dayjs from 'dayjs';
 
 const ReadingPage: React.FC = () => {
@@ -11,7 +11,12 @@ const ReadingPage: React.FC = () => {
   const [loading, setLoading] = useState(false);
   const [category, setCategory] = useState<string>('');
   const [pushedFilter, setPushedFilter] = useState<string>('all');
+  const [sort, setSort] = useState<string>('');
+  const [sentiment, setSentiment] = useState<string>('');
+  const [fieldFilter, setFieldFilter] = useState<{ name: string; value: string } | null>(null);
   const [page, setPage] = useState(1);
+  const [translatingId, setTranslatingId] = useState<string>('');
+  const [fullText, setFullText] = useState<{ title: string; content: string } | null>(null);
   const pageSize = 20;
 
   const fetchNews = async () => {
@@ -20,6 +25,9 @@ const ReadingPage: React.FC = () => {
       const res = await getReadingNews({
         category: category || undefined,
         pushed: pushedFilter,
+        sentiment: sentiment || undefined,
+        ...(fieldFilter ? { [`field.${fieldFilter.name}`]: fieldFilter.value } : {}),
+        sort: sort || undefined,
         limit: pageSize,
         offset: (page - 1) * pageSize,
       });
@@ -34,7 +42,7 @@ const ReadingPage: React.FC = () => {
 
   useEffect(() => {
     fetchNews();
-  }, [category, pushedFilter, page]);
+  }, [category, pushedFilter, sentiment, sort, fieldFilter, page]);
 
   const handleRemove = async (id: string) => {
     try {
@@ -46,6 +54,41 @@ const ReadingPage: React.FC = () => {
     }
   };
 
+  // 再次点击相同的反馈则撤销；不感兴趣的新闻会移出阅读窗口
+  const handleFeedback = async (item: any, label: 'up' | 'down' | 'not_interested') => {
+    try {
+      if (item.feedback === label) {
+        await deleteNewsFeedback(item.id);
+      } else {
+        await setNewsFeedback(item.id, label);
+      }
+      if (label === 'not_interested') {
+        message.success('已标记为不感兴趣');
+      }
+      fetchNews();
+    } catch {
+      message.error('操作失败');
+    }
+  };
+
+  // 已有全文译文时直接显示，否则请求翻译
+  const handleTranslateFull = async (item: any, retranslate = false) => {
+    const title = item.trans_title || item.title;
+    if (item.trans_content && !retranslate) {
+      setFullText({ title, content: item.trans_content });
+      return;
+    }
+    setTranslatingId(item.id);
+    try {
+      const res = await translateFull(item.id);
+      item.trans_content = res.data.trans_content;
+      setFullText({ title, content: res.data.trans_content });
+    } catch (e: any) {
+      message.error(e.response?.data?.error || '全文翻译失败');
+    }
+    setTranslatingId('');
+  };
+
   const handleClearPushed = async () => {
     try {
       await clearPushedNews();
@@ -72,6 +115,31 @@ const ReadingPage: React.FC = () => {
     { value: 'international', label: '国际' },
   ];
 
+  const sentimentOptions = [
+    { value: '', label: '全部情感' },
+    { value: 'positive', label: '正面' },
+    { value: 'neutral', label: '中性' },
+    { value: 'negative', label: '负面' },
+  ];
+
+  const fieldValue = (v: any) => (Array.isArray(v) ? v.join(', ') : typeof v === 'boolean' ? (v ? '是' : '否') : String(v));
+
+  const filterByField = (name: string, v: any) => {
+    setFieldFilter({ name, value: Array.isArray(v) ? String(v[0]) : String(v) });
+    setPage(1);
+  };
+
+  const sentimentTags: Record<string, { color: string; label: string }> = {
+    positive: { color: 'green', label: '正面' },
+    neutral: { color: 'default', label: '中性' },
+    negative: { color: 'red', label: '负面' },
+  };
+
+  const sortOptions = [
+    { value: '', label: '最新加入' },
+    { value: 'preference', label: '偏好评分' },
+  ];
+
   const pushedOptions = [
     { value: 'all', label: '全部状态' },
     { value: 'no', label: '待推送' },
@@ -100,6 +168,23 @@ const ReadingPage: React.FC = () => {
             onChange={(v) => { setPushedFilter(v); setPage(1); }}
             options={pushedOptions}
           />
+          <Select
+            style={{ width: 120 }}
+            value={sentiment}
+            onChange={(v) => { setSentiment(v); setPage(1); }}
+            options={sentimentOptions}
+          />
+          {fieldFilter && (
+            <Tag closable onClose={() => { setFieldFilter(null); setPage(1); }}>
+              {fieldFilter.name} = {fieldFilter.value}
+            </Tag>
+          )}
+          <Select
+            style={{ width: 120 }}
+            value={sort}
+            onChange={(v) => { setSort(v); setPage(1); }}
+            options={sortOptions}
+          />
           <Popconfirm title="确定清空所有已推送的新闻?" onConfirm={handleClearPushed}>
             <Button icon={<ClearOutlined />} danger>清空已推送</Button>
           </Popconfirm>
@@ -127,6 +212,32 @@ const ReadingPage: React.FC = () => {
                       <a href={item.url} target="_blank" rel="noopener noreferrer" key="view">
                         查看原文
                       </a>,
+                      <Tooltip title={item.trans_content ? '查看全文译文' : '翻译全文'} key="full">
+                        <Button
+                          type="link"
+                          size="small"
+                          icon={<TranslationOutlined />}
+                          loading={translatingId === item.id}
+                          onClick={() => handleTranslateFull(item)}
+                        >
+                          全文
+                        </Button>
+                      </Tooltip>,
+                      <Space key="feedback" size={12}>
+                        <Tooltip title="赞">
+                          {item.feedback === 'up'
+                            ? <LikeFilled style={{ color: '#1677ff' }} onClick={() => handleFeedback(item, 'up')} />
+                            : <LikeOutlined onClick={() => handleFeedback(item, 'up')} />}
+                        </Tooltip>
+                        <Tooltip title="踩">
+                          {item.feedback === 'down'
+                            ? <DislikeFilled style={{ color: '#ff4d4f' }} onClick={() => handleFeedback(item, 'down')} />
+                            : <DislikeOutlined onClick={() => handleFeedback(item, 'down')} />}
+                        </Tooltip>
+                        <Tooltip title="不感兴趣（移出阅读窗口）">
+                          <EyeInvisibleOutlined onClick={() => handleFeedback(item, 'not_interested')} />
+                        </Tooltip>
+                      </Space>,
                       <Popconfirm title="移出阅读窗口?" onConfirm={() => handleRemove(item.id)} key="remove">
                         <DeleteOutlined />
                       </Popconfirm>,
@@ -147,7 +258,32 @@ const ReadingPage: React.FC = () => {
                       <span>{item.source}</span>
                       <span style={{ marginLeft: 8 }}>{dayjs(item.reading_at).format('MM-DD HH:mm')}</span>
                       {!item.pushed && <Tag color="green" style={{ marginLeft: 8 }}>待推送</Tag>}
+                      {item.preference !== undefined && (
+                        <Tooltip title="偏好评分：根据赞、踩和不感兴趣的反馈训练">
+                          <Tag color={item.preference >= 0.5 ? 'blue' : 'default'} style={{ marginLeft: 8 }}>
+                            {Math.round(item.preference * 100)}
+                          </Tag>
+                        </Tooltip>
+                      )}
+                      {sentimentTags[item.sentiment] && (
+                        <Tooltip title={`情感评分 ${item.sentiment_score?.toFixed(2) ?? '-'}`}>
+                          <Tag color={sentimentTags[item.sentiment].color} style={{ marginLeft: 8 }}>
+                            {sentimentTags[item.sentiment].label}
+                          </Tag>
+                        </Tooltip>
+                      )}
                     </div>
+                    {item.fields && (
+                      <div style={{ marginTop: 8 }}>
+                        {Object.entries(item.fields).map(([name, v]) => (
+                          <Tooltip title="点击按该字段筛选" key={name}>
+                            <Tag style={{ cursor: 'pointer' }} onClick={() => filterByField(name, v)}>
+                              {name}: {fieldValue(v)}
+                            </Tag>
+                          </Tooltip>
+                        ))}
+                      </div>
+                    )}
                     <div className="news-summary" style={{ marginTop: 8 }}>
                       {item.trans_summary || item.summary || '暂无摘要'}
                     </div>
@@ -167,6 +303,18 @@ const ReadingPage: React.FC = () => {
           </>
         )}
       </Spin>
+
+      <Modal
+        title={fullText?.title}
+        open={!!fullText}
+        onCancel={() => setFullText(null)}
+        footer={null}
+        width={760}
+      >
+        <div style={{ whiteSpace: 'pre-wrap', lineHeight: 1.8, maxHeight: '70vh', overflowY: 'auto' }}>
+          {fullText?.content}
+        </div>
+      </Modal>
     </div>
   );
 };
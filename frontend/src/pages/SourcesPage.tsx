@@ -71,6 +71,12 @@ const SourcesPage: React.FC = () => {
       key: 'enabled',
       render: (v: boolean) => <Switch checked={v} disabled />,
     },
+    {
+      title: '全文翻译',
+      dataIndex: 'full_translate',
+      key: 'full_translate',
+      render: (v: boolean) => <Switch checked={v} disabled />,
+    },
     {
       title: '操作',
       key: 'action',
@@ -127,6 +133,9 @@ const SourcesPage: React.FC = () => {
           <Form.Item name="enabled" label="启用" valuePropName="checked">
             <Switch />
           </Form.Item>
+          <Form.Item name="full_translate" label="自动全文翻译" valuePropName="checked" extra="翻译标题摘要后按段落翻译全文，消耗较多 tokens">
+            <Switch />
+          </Form.Item>
         </Form>
       </Modal>
     </div>
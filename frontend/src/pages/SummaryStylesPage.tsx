@@ -0,0 +1,153 @@
+import React, { useEffect, useState } from 'react';
+import { Table, Button, Modal, Form, Input, InputNumber, Select, message, Popconfirm, Space, Tag } from 'antd';
+import { PlusOutlined, EditOutlined, DeleteOutlined } from '@ant-design/icons';
+import { getSummaryStyles, createSummaryStyle, updateSummaryStyle, deleteSummaryStyle } from '../api';
+
+const formatOptions = [
+  { value: 'one_line', label: '一句话' },
+  { value: 'bullets', label: '要点列表' },
+  { value: 'paragraph', label: '段落' },
+];
+
+const SummaryStylesPage: React.FC = () => {
+  const [styles, setStyles] = useState<any[]>([]);
+  const [loading, setLoading] = useState(false);
+  const [modalOpen, setModalOpen] = useState(false);
+  const [editingId, setEditingId] = useState<string | null>(null);
+  const [form] = Form.useForm();
+  const format = Form.useWatch('format', form);
+
+  const fetchStyles = async () => {
+    setLoading(true);
+    try {
+      const res = await getSummaryStyles();
+      setStyles(res.data || []);
+    } catch {
+      message.error('获取失败');
+    }
+    setLoading(false);
+  };
+
+  useEffect(() => {
+    fetchStyles();
+  }, []);
+
+  const handleSubmit = async (values: any) => {
+    try {
+      if (editingId) {
+        await updateSummaryStyle(editingId, values);
+        message.success('更新成功，已生成的该样式摘要将在下次推送时重新生成');
+      } else {
+        await createSummaryStyle(values);
+        message.success('创建成功');
+      }
+      setModalOpen(false);
+      form.resetFields();
+      setEditingId(null);
+      fetchStyles();
+    } catch (e: any) {
+      message.error(e.response?.data?.error || '操作失败');
+    }
+  };
+
+  const handleEdit = (record: any) => {
+    setEditingId(record.id);
+    form.setFieldsValue(record);
+    setModalOpen(true);
+  };
+
+  const handleDelete = async (id: string) => {
+    try {
+      await deleteSummaryStyle(id);
+      message.success('删除成功');
+      fetchStyles();
+    } catch {
+      message.error('删除失败');
+    }
+  };
+
+  const columns = [
+    { title: '名称', dataIndex: 'name', key: 'name', render: (v: string) => <code>{v}</code> },
+    { title: '显示名称', dataIndex: 'label', key: 'label' },
+    {
+      title: '格式',
+      dataIndex: 'format',
+      key: 'format',
+      render: (v: string, record: any) => (
+        <Tag>{formatOptions.find((o) => o.value === v)?.label || v}{v === 'bullets' ? ` × ${record.points}` : ''}</Tag>
+      ),
+    },
+    {
+      title: '长度',
+      dataIndex: 'max_chars',
+      key: 'max_chars',
+      render: (v: number, record: any) => (record.format === 'bullets' ? `每条 ≤ ${v} 字` : `≤ ${v} 字`),
+    },
+    { title: '语气', dataIndex: 'tone', key: 'tone' },
+    { title: '附加要求', dataIndex: 'instruction', key: 'instruction', ellipsis: true },
+    {
+      title: '操作',
+      key: 'action',
+      render: (_: any, record: any) => (
+        <Space>
+          <Button type="link" icon={<EditOutlined />} onClick={() => handleEdit(record)} />
+          <Popconfirm title="删除样式会同时删除按该样式生成的摘要，确定删除?" onConfirm={() => handleDelete(record.id)}>
+            <Button type="link" danger icon={<DeleteOutlined />} />
+          </Popconfirm>
+        </Space>
+      ),
+    },
+  ];
+
+  return (
+    <div>
+      <div className="page-header" style={{ display: 'flex', justifyContent: 'space-between' }}>
+        <h2>摘要样式</h2>
+        <Button type="primary" icon={<PlusOutlined />} onClick={() => { form.resetFields(); setEditingId(null); setModalOpen(true); }}>
+          添加样式
+        </Button>
+      </div>
+
+      <div style={{ marginBottom: 16, color: '#888' }}>
+        推送任务可选择一种摘要样式，模板中的摘要（.TransSummary）会替换为该样式的摘要；每条新闻的样式摘要生成后保存，不会重复生成。
+      </div>
+
+      <Table columns={columns} dataSource={styles} rowKey="id" loading={loading} />
+
+      <Modal
+        title={editingId ? '编辑样式' : '添加样式'}
+        open={modalOpen}
+        onCancel={() => setModalOpen(false)}
+        onOk={() => form.submit()}
+      >
+        <Form form={form} layout="vertical" onFinish={handleSubmit} initialValues={{ format: 'paragraph', max_chars: 100, points: 3 }}>
+          <Form.Item name="name" label="名称" rules={[{ required: true }, { pattern: /^[a-z0-9_-]+$/, message: '只能包含小写字母、数字、_ 和 -' }]}>
+            <Input placeholder="tldr" disabled={!!editingId} />
+          </Form.Item>
+          <Form.Item name="label" label="显示名称">
+            <Input placeholder="一句话速览" />
+          </Form.Item>
+          <Form.Item name="format" label="格式">
+            <Select options={formatOptions} />
+          </Form.Item>
+          {format === 'bullets' && (
+            <Form.Item name="points" label="要点数">
+              <InputNumber min={1} max={10} />
+            </Form.Item>
+          )}
+          <Form.Item name="max_chars" label={format === 'bullets' ? '每条字数上限' : '字数上限'}>
+            <InputNumber min={10} max={2000} />
+          </Form.Item>
+          <Form.Item name="tone" label="语气">
+            <Input placeholder="客观、简洁" />
+          </Form.Item>
+          <Form.Item name="instruction" label="附加要求">
+            <Input.TextArea rows={2} placeholder="例如：突出对开发者的影响" />
+          </Form.Item>
+        </Form>
+      </Modal>
+    </div>
+  );
+};
+
+export default SummaryStylesPage;
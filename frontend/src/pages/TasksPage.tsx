@@ -1,13 +1,15 @@
 import React, { useEffect, useState } from 'react';
 import { Table, Button, Modal, Form, Input, Select, Switch, message, Popconfirm, Space, Tag, Card, InputNumber, Alert, Badge, Divider } from 'antd';
 import { PlusOutlined, EditOutlined, DeleteOutlined, PlayCircleOutlined, ThunderboltOutlined } from '@ant-design/icons';
-import { getTasks, createTask, updateTask, deleteTask, runTask, getChannels, getTemplates, getAutoPushConfig, saveAutoPushConfig } from '../api';
+import { getTasks, createTask, updateTask, deleteTask, runTask, getChannels, getTemplates, getAutoPushConfig, saveAutoPushConfig, getTags, getSummaryStyles } from '../api';
 import dayjs from 'dayjs';
 
 const TasksPage: React.FC = () => {
   const [tasks, setTasks] = useState<any[]>([]);
   const [channels, setChannels] = useState<any[]>([]);
   const [templates, setTemplates] = useState<any[]>([]);
+  const [tags, setTags] = useState<any[]>([]);
+  const [summaryStyles, setSummaryStyles] = useState<any[]>([]);
   const [loading, setLoading] = useState(false);
   const [modalOpen, setModalOpen] = useState(false);
   const [editingId, setEditingId] = useState<string | null>(null);
@@ -19,6 +21,7 @@ const TasksPage: React.FC = () => {
     threshold: 6,
     channel_id: '',
     template_id: '',
+    briefing: false,
     pending_count: 0,
   });
   const [autoPushForm] = Form.useForm();
@@ -26,15 +29,19 @@ const TasksPage: React.FC = () => {
   const fetchData = async () => {
     setLoading(true);
     try {
-      const [tasksRes, channelsRes, templatesRes, autoPushRes] = await Promise.all([
+      const [tasksRes, channelsRes, templatesRes, autoPushRes, tagsRes, stylesRes] = await Promise.all([
         getTasks(),
         getChannels(),
         getTemplates(),
         getAutoPushConfig(),
+        getTags('approved'),
+        getSummaryStyles(),
       ]);
       setTasks(tasksRes.data || []);
       setChannels(channelsRes.data || []);
       setTemplates(templatesRes.data || []);
+      setTags(tagsRes.data || []);
+      setSummaryStyles(stylesRes.data || []);
       setAutoPushConfig(autoPushRes.data);
       autoPushForm.setFieldsValue(autoPushRes.data);
     } catch {
@@ -60,6 +67,7 @@ const TasksPage: React.FC = () => {
       const data = {
         ...values,
         categories: Array.isArray(values.categories) ? values.categories.join(',') : values.categories,
+        tags: Array.isArray(values.tags) ? values.tags.join(',') : values.tags,
       };
 
       if (editingId) {
@@ -83,6 +91,7 @@ const TasksPage: React.FC = () => {
     form.setFieldsValue({
       ...record,
       categories: record.categories ? record.categories.split(',') : [],
+      tags: record.tags ? record.tags.split(',') : [],
     });
     setModalOpen(true);
   };
@@ -126,6 +135,12 @@ const TasksPage: React.FC = () => {
       key: 'categories',
       render: (v: string) => v?.split(',').map(c => <Tag key={c}>{c}</Tag>),
     },
+    {
+      title: '标签',
+      dataIndex: 'tags',
+      key: 'tags',
+      render: (v: string) => v ? v.split(',').map(t => <Tag key={t} color="blue">{t}</Tag>) : '-',
+    },
     {
       title: '启用',
       dataIndex: 'enabled',
@@ -211,6 +226,9 @@ const TasksPage: React.FC = () => {
               allowClear 
             />
           </Form.Item>
+          <Form.Item name="briefing" label="AI 综述" valuePropName="checked">
+            <Switch checkedChildren="开" unCheckedChildren="关" />
+          </Form.Item>
           <Form.Item>
             <Button type="primary" onClick={handleSaveAutoPush}>保存配置</Button>
           </Form.Item>
@@ -249,7 +267,7 @@ const TasksPage: React.FC = () => {
           <Form.Item name="template_id" label="邮件模板">
             <Select options={templates.map(t => ({ value: t.id, label: t.name }))} placeholder="选择模板(可选)" allowClear />
           </Form.Item>
-          <Form.Item name="categories" label="推送分类" rules={[{ required: true }]}>
+          <Form.Item name="categories" label="推送分类">
             <Select mode="multiple" options={[
               { value: 'tech', label: '科技' },
               { value: 'ai', label: 'AI' },
@@ -258,6 +276,15 @@ const TasksPage: React.FC = () => {
               { value: 'trending', label: '热门' },
             ]} placeholder="选择要推送的分类" />
           </Form.Item>
+          <Form.Item name="tags" label="推送标签" extra="命中任一分类或任一标签的新闻都会被推送">
+            <Select mode="multiple" options={tags.map(t => ({ value: t.name, label: t.name }))} placeholder="选择要推送的标签(可选)" />
+          </Form.Item>
+          <Form.Item name="summary_style" label="摘要样式" extra="模板中的摘要使用该样式，缺失的在推送时生成；不选则使用默认摘要">
+            <Select options={summaryStyles.map(s => ({ value: s.name, label: s.label || s.name }))} placeholder="默认摘要" allowClear />
+          </Form.Item>
+          <Form.Item name="briefing" label="AI 综述" valuePropName="checked" extra="推送前由 AI 生成要点、主题和值得关注的动向">
+            <Switch />
+          </Form.Item>
           <Form.Item name="enabled" label="启用" valuePropName="checked">
             <Switch />
           </Form.Item>
@@ -1,8 +1,8 @@
-import React, { useEffect, useState, useCallback } from 'react';
+import React, { useEffect, useState, useCallback, useRef } from 'react';
 import { Button, Card, Form, Input, message, Popconfirm, Space, List, Spin, Alert, Modal, Drawer } from 'antd';
 import { PlusOutlined, DeleteOutlined, ReloadOutlined, RobotOutlined, SaveOutlined, SendOutlined } from '@ant-design/icons';
 import Editor from '@monaco-editor/react';
-import { getTemplates, createTemplate, updateTemplate, deleteTemplate, previewTemplate, aiGenerateTemplate } from '../api';
+import { getTemplates, createTemplate, updateTemplate, deleteTemplate, previewTemplate, aiGenerateTemplateStream } from '../api';
 import debounce from 'lodash/debounce';
 
 const { TextArea } = Input;
@@ -71,6 +71,8 @@ const TemplatesPage: React.FC = () => {
   const [aiPrompt, setAiPrompt] = useState('');
   const [aiGenerating, setAiGenerating] = useState(false);
   const [aiGeneratedTemplate, setAiGeneratedTemplate] = useState('');
+  const [aiStreamText, setAiStreamText] = useState('');
+  const aiAbortRef = useRef<AbortController | null>(null);
   const [aiPreviewHtml, setAiPreviewHtml] = useState('');
   const [aiPreviewLoading, setAiPreviewLoading] = useState(false);
   const [chatHistory, setChatHistory] = useState<{role: 'user' | 'ai', content: string}[]>([]);
@@ -188,21 +190,47 @@ const TemplatesPage: React.FC = () => {
     }
 
     setAiGenerating(true);
+    setAiStreamText('');
     setChatHistory(prev => [...prev, { role: 'user', content: aiPrompt }]);
 
+    const controller = new AbortController();
+    aiAbortRef.current = controller;
+    let generated = '';
+    let streamError = '';
     try {
-      const res = await aiGenerateTemplate(aiPrompt, aiGeneratedTemplate || undefined);
-      const generatedTemplate = res.data.template;
-      setAiGeneratedTemplate(generatedTemplate);
+      await aiGenerateTemplateStream(aiPrompt, aiGeneratedTemplate || undefined, (event, data) => {
+        if (event === 'delta') {
+          setAiStreamText(prev => prev + data.text);
+        } else if (event === 'done') {
+          generated = data.template;
+        } else if (event === 'error') {
+          streamError = data.error;
+        }
+      }, controller.signal);
+      if (streamError || !generated) {
+        throw new Error(streamError || 'AI 生成失败');
+      }
+      setAiGeneratedTemplate(generated);
       setChatHistory(prev => [...prev, { role: 'ai', content: '已根据您的需求生成模板，请查看右侧预览效果。' }]);
       setAiPrompt('');
     } catch (err: any) {
-      message.error(err.response?.data?.error || 'AI 生成失败');
-      setChatHistory(prev => [...prev, { role: 'ai', content: '生成失败，请重试。' }]);
+      if (controller.signal.aborted) {
+        setChatHistory(prev => [...prev, { role: 'ai', content: '已停止生成。' }]);
+      } else {
+        message.error(err.message || 'AI 生成失败');
+        setChatHistory(prev => [...prev, { role: 'ai', content: '生成失败，请重试。' }]);
+      }
     }
+    aiAbortRef.current = null;
+    setAiStreamText('');
     setAiGenerating(false);
   };
 
+  // 停止生成（服务端同时取消 AI 请求）
+  const handleAiStop = () => {
+    aiAbortRef.current?.abort();
+  };
+
   // 应用 AI 生成的模板到编辑器
   const handleApplyAiTemplate = () => {
     if (!aiGeneratedTemplate) {
@@ -478,8 +506,14 @@ const TemplatesPage: React.FC = () => {
               )}
               {aiGenerating && (
                 <div style={{ display: 'flex', justifyContent: 'flex-start' }}>
-                  <div style={{ padding: '8px 12px', background: '#fff', borderRadius: 8 }}>
-                    <Spin size="small" /> 正在生成...
+                  <div style={{ maxWidth: '85%', padding: '8px 12px', background: '#fff', borderRadius: 8 }}>
+                    <Spin size="small" /> 正在生成...{aiStreamText && `（已生成 ${aiStreamText.length} 字符）`}
+                    <Button type="link" size="small" onClick={handleAiStop}>停止</Button>
+                    {aiStreamText && (
+                      <pre style={{ marginTop: 8, maxHeight: 200, overflow: 'auto', fontSize: 12, whiteSpace: 'pre-wrap', wordBreak: 'break-all' }}>
+                        {aiStreamText.slice(-2000)}
+                      </pre>
+                    )}
                   </div>
                 </div>
               )}
@@ -0,0 +1,215 @@
+import React, { useEffect, useState } from 'react';
+import { Table, Button, Modal, Form, Input, InputNumber, Select, Switch, message, Popconfirm, Space, Card, Tag } from 'antd';
+import { PlusOutlined, EditOutlined, DeleteOutlined, ReloadOutlined } from '@ant-design/icons';
+import dayjs from 'dayjs';
+import { getTrends, refreshTrends, getTrendWatches, createTrendWatch, updateTrendWatch, deleteTrendWatch, getChannels } from '../api';
+
+const kindOptions = [
+  { value: '', label: '全部类型' },
+  { value: 'term', label: '关键词' },
+  { value: 'entity', label: '实体' },
+  { value: 'tag', label: '标签' },
+];
+
+const kindColors: Record<string, string> = { term: 'blue', entity: 'purple', tag: 'green' };
+
+const kindLabel = (kind: string) => kindOptions.find((o) => o.value === kind)?.label || kind;
+
+const TrendsPage: React.FC = () => {
+  const [trends, setTrends] = useState<any>({ data: [] });
+  const [kind, setKind] = useState('');
+  const [watches, setWatches] = useState<any[]>([]);
+  const [channels, setChannels] = useState<any[]>([]);
+  const [loading, setLoading] = useState(false);
+  const [modalOpen, setModalOpen] = useState(false);
+  const [editingId, setEditingId] = useState<string | null>(null);
+  const [form] = Form.useForm();
+
+  const fetchTrends = async () => {
+    setLoading(true);
+    try {
+      const res = await getTrends({ kind: kind || undefined, limit: 50 });
+      setTrends(res.data);
+    } catch {
+      message.error('获取失败');
+    }
+    setLoading(false);
+  };
+
+  const fetchWatches = async () => {
+    try {
+      const [watchRes, channelRes] = await Promise.all([getTrendWatches(), getChannels()]);
+      setWatches(watchRes.data || []);
+      setChannels(channelRes.data || []);
+    } catch {
+      message.error('获取关注词失败');
+    }
+  };
+
+  useEffect(() => {
+    fetchTrends();
+  }, [kind]);
+
+  useEffect(() => {
+    fetchWatches();
+  }, []);
+
+  const handleRefresh = async () => {
+    try {
+      await refreshTrends();
+      message.success('趋势分析任务已启动，稍后刷新查看');
+    } catch {
+      message.error('启动失败');
+    }
+  };
+
+  const handleSubmit = async (values: any) => {
+    try {
+      if (editingId) {
+        await updateTrendWatch(editingId, values);
+        message.success('更新成功');
+      } else {
+        await createTrendWatch(values);
+        message.success('创建成功');
+      }
+      setModalOpen(false);
+      form.resetFields();
+      setEditingId(null);
+      fetchWatches();
+    } catch (e: any) {
+      message.error(e.response?.data?.error || '操作失败');
+    }
+  };
+
+  const handleEdit = (record: any) => {
+    setEditingId(record.id);
+    form.setFieldsValue(record);
+    setModalOpen(true);
+  };
+
+  const handleDelete = async (id: string) => {
+    try {
+      await deleteTrendWatch(id);
+      message.success('删除成功');
+      fetchWatches();
+    } catch {
+      message.error('删除失败');
+    }
+  };
+
+  const channelName = (id: string) => channels.find((c) => c.id === id)?.name || id;
+
+  const trendColumns = [
+    {
+      title: '热点',
+      key: 'term',
+      render: (_: any, record: any) => <Tag color={kindColors[record.kind]}>{record.term}</Tag>,
+    },
+    { title: '类型', dataIndex: 'kind', key: 'kind', render: kindLabel },
+    { title: '最近新闻数', dataIndex: 'count', key: 'count' },
+    { title: '基线', dataIndex: 'baseline', key: 'baseline', render: (v: number) => v.toFixed(1) },
+    { title: '显著性', dataIndex: 'score', key: 'score', render: (v: number) => v.toFixed(1) },
+  ];
+
+  const watchColumns = [
+    { title: '关注词', dataIndex: 'term', key: 'term' },
+    { title: '类型', dataIndex: 'kind', key: 'kind', render: kindLabel },
+    { title: '推送渠道', dataIndex: 'channel_id', key: 'channel_id', render: channelName },
+    { title: '显著性阈值', dataIndex: 'min_score', key: 'min_score', render: (v: number) => v || '默认' },
+    {
+      title: '状态',
+      dataIndex: 'enabled',
+      key: 'enabled',
+      render: (v: boolean) => <Tag color={v ? 'green' : 'default'}>{v ? '启用' : '停用'}</Tag>,
+    },
+    {
+      title: '上次提醒',
+      dataIndex: 'last_fired_at',
+      key: 'last_fired_at',
+      render: (v: string) => (v && !v.startsWith('0001') ? dayjs(v).format('MM-DD HH:mm') : '-'),
+    },
+    {
+      title: '操作',
+      key: 'action',
+      render: (_: any, record: any) => (
+        <Space>
+          <Button type="link" icon={<EditOutlined />} onClick={() => handleEdit(record)} />
+          <Popconfirm title="确定删除?" onConfirm={() => handleDelete(record.id)}>
+            <Button type="link" danger icon={<DeleteOutlined />} />
+          </Popconfirm>
+        </Space>
+      ),
+    },
+  ];
+
+  return (
+    <div>
+      <div className="page-header" style={{ display: 'flex', justifyContent: 'space-between' }}>
+        <h2>热点趋势</h2>
+        <Button icon={<ReloadOutlined />} onClick={handleRefresh}>
+          立即分析
+        </Button>
+      </div>
+
+      <div style={{ marginBottom: 16, color: '#888' }}>
+        每 30 分钟统计一次最近 {trends.window_hours || 6} 小时内标题关键词、实体和标签被提到的新闻数，
+        与之前 {trends.baseline_days || 7} 天的平均水平（基线）比较，只列出显著突增的热点。
+        上次分析：{trends.computed_at ? dayjs(trends.computed_at).format('MM-DD HH:mm') : '尚未分析'}
+      </div>
+
+      <Card
+        title="突增热点"
+        extra={<Select value={kind} onChange={setKind} options={kindOptions} style={{ width: 120 }} size="small" />}
+      >
+        <Table
+          columns={trendColumns}
+          dataSource={trends.data || []}
+          rowKey={(r: any) => `${r.kind}:${r.term}`}
+          loading={loading}
+          size="small"
+          pagination={{ pageSize: 20 }}
+        />
+      </Card>
+
+      <Card
+        title="关注词"
+        style={{ marginTop: 24 }}
+        extra={
+          <Button type="primary" size="small" icon={<PlusOutlined />} onClick={() => { form.resetFields(); setEditingId(null); setModalOpen(true); }}>
+            添加关注词
+          </Button>
+        }
+      >
+        <div style={{ marginBottom: 12, color: '#888' }}>关注的词出现突增时向指定渠道推送提醒，同一关注词每个窗口最多提醒一次。</div>
+        <Table columns={watchColumns} dataSource={watches} rowKey="id" size="small" pagination={false} />
+      </Card>
+
+      <Modal
+        title={editingId ? '编辑关注词' : '添加关注词'}
+        open={modalOpen}
+        onCancel={() => setModalOpen(false)}
+        onOk={() => form.submit()}
+      >
+        <Form form={form} layout="vertical" onFinish={handleSubmit} initialValues={{ kind: '', min_score: 0, enabled: true }}>
+          <Form.Item name="term" label="关注词" rules={[{ required: true }]} extra="不区分大小写；关键词为标题中的英文单词或两字中文词">
+            <Input placeholder="OpenAI" />
+          </Form.Item>
+          <Form.Item name="kind" label="类型">
+            <Select options={kindOptions} />
+          </Form.Item>
+          <Form.Item name="channel_id" label="推送渠道" rules={[{ required: true }]}>
+            <Select options={channels.map((c) => ({ value: c.id, label: c.name }))} />
+          </Form.Item>
+          <Form.Item name="min_score" label="显著性阈值" extra="为 0 时使用默认阈值 3">
+            <InputNumber min={0} max={100} step={0.5} />
+          </Form.Item>
+          <Form.Item name="enabled" label="启用" valuePropName="checked">
+            <Switch />
+          </Form.Item>
+        </Form>
+      </Modal>
+    </div>
+  );
+};
+
+export default TrendsPage;